@@ -0,0 +1,578 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Translation between the Chat Completions schema and the native Anthropic
+// Messages API, used when ClientConfig.APIType is APITypeAnthropic.
+// https://docs.anthropic.com/en/api/messages
+
+const (
+	anthropicMessagesSuffix = "/messages"
+
+	// AnthropicDefaultMaxTokens is sent as max_tokens, which the Messages API
+	// requires, when neither MaxCompletionTokens nor MaxTokens is set.
+	AnthropicDefaultMaxTokens = 4096
+)
+
+// ErrAnthropicUnsupportedField is returned when a chat completion request sets
+// a field that the Messages API has no equivalent for.
+var ErrAnthropicUnsupportedField = errors.New("anthropic: unsupported chat completion field")
+
+type anthropicMessagesRequest struct {
+	Model         string               `json:"model"`
+	Messages      []anthropicMessage   `json:"messages"`
+	System        string               `json:"system,omitempty"`
+	MaxTokens     int                  `json:"max_tokens"`
+	Temperature   float32              `json:"temperature,omitempty"`
+	TopP          float32              `json:"top_p,omitempty"`
+	StopSequences []string             `json:"stop_sequences,omitempty"`
+	Stream        bool                 `json:"stream,omitempty"`
+	Tools         []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice    *anthropicToolChoice `json:"tool_choice,omitempty"`
+	Metadata      *anthropicMetadata   `json:"metadata,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string                  `json:"role"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+type anthropicContentBlock struct {
+	Type string `json:"type"`
+	// text
+	Text string `json:"text,omitempty"`
+	// image
+	Source *anthropicImageSource `json:"source,omitempty"`
+	// tool_use
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+	// tool_result
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	// thinking
+	Thinking string `json:"thinking,omitempty"`
+}
+
+type anthropicImageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+type anthropicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type                   string `json:"type"`
+	Name                   string `json:"name,omitempty"`
+	DisableParallelToolUse bool   `json:"disable_parallel_tool_use,omitempty"`
+}
+
+type anthropicMetadata struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+type anthropicMessagesResponse struct {
+	ID         string                  `json:"id"`
+	Model      string                  `json:"model"`
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+	Usage      anthropicUsage          `json:"usage"`
+}
+
+// promptTokens returns the total input tokens. Anthropic reports cached
+// input separately, while OpenAI includes it in prompt_tokens.
+func (u anthropicUsage) promptTokens() int {
+	return u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+func (u anthropicUsage) toUsage(outputTokens int) Usage {
+	prompt := u.promptTokens()
+	return Usage{
+		PromptTokens:        prompt,
+		CompletionTokens:    outputTokens,
+		TotalTokens:         prompt + outputTokens,
+		PromptTokensDetails: &PromptTokensDetails{CachedTokens: u.CacheReadInputTokens},
+	}
+}
+
+func anthropicFinishReason(stopReason string) FinishReason {
+	switch stopReason {
+	case "end_turn", "stop_sequence", "pause_turn":
+		return FinishReasonStop
+	case "max_tokens":
+		return FinishReasonLength
+	case "tool_use":
+		return FinishReasonToolCalls
+	case "refusal":
+		return FinishReasonContentFilter
+	case "":
+		return ""
+	default:
+		return FinishReason(stopReason)
+	}
+}
+
+// unsupportedAnthropicField returns the JSON name of the first field of
+// request that cannot be translated, or "" if there is none.
+func unsupportedAnthropicField(request ChatCompletionRequest) string {
+	switch {
+	case request.ResponseFormat != nil && request.ResponseFormat.Type != "" &&
+		request.ResponseFormat.Type != ChatCompletionResponseFormatTypeText:
+		return "response_format"
+	case request.N > 1:
+		return "n"
+	case request.Seed != nil:
+		return "seed"
+	case request.LogProbs || request.TopLogProbs > 0:
+		return "logprobs"
+	case len(request.LogitBias) > 0:
+		return "logit_bias"
+	case request.PresencePenalty != 0:
+		return "presence_penalty"
+	case request.FrequencyPenalty != 0:
+		return "frequency_penalty"
+	case len(request.Functions) > 0 || request.FunctionCall != nil:
+		return "functions"
+	case request.Prediction != nil:
+		return "prediction"
+	}
+	return ""
+}
+
+func newAnthropicMessagesRequest(request ChatCompletionRequest) (anthropicMessagesRequest, error) {
+	if field := unsupportedAnthropicField(request); field != "" {
+		return anthropicMessagesRequest{}, fmt.Errorf("%w: %s", ErrAnthropicUnsupportedField, field)
+	}
+
+	maxTokens := request.MaxCompletionTokens
+	if maxTokens == 0 {
+		maxTokens = request.MaxTokens
+	}
+	if maxTokens == 0 {
+		maxTokens = AnthropicDefaultMaxTokens
+	}
+
+	out := anthropicMessagesRequest{
+		Model:         request.Model,
+		MaxTokens:     maxTokens,
+		Temperature:   request.Temperature,
+		TopP:          request.TopP,
+		StopSequences: request.Stop,
+		Stream:        request.Stream,
+		ToolChoice:    newAnthropicToolChoice(request.ToolChoice, request.ParallelToolCalls),
+	}
+	if request.User != "" {
+		out.Metadata = &anthropicMetadata{UserID: request.User}
+	}
+	for _, tool := range request.Tools {
+		if tool.Type != ToolTypeFunction || tool.Function == nil {
+			return out, fmt.Errorf("anthropic: unsupported tool type %q", tool.Type)
+		}
+		schema := tool.Function.Parameters
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+		out.Tools = append(out.Tools, anthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: schema,
+		})
+	}
+
+	var system []string
+	for _, message := range request.Messages {
+		if message.Role == ChatMessageRoleSystem || message.Role == ChatMessageRoleDeveloper {
+			system = append(system, messageText(message))
+			continue
+		}
+		converted, err := newAnthropicMessage(message)
+		if err != nil {
+			return out, err
+		}
+		// The Messages API requires user and assistant turns to alternate, so
+		// consecutive messages of one role, such as several tool results, are merged.
+		if n := len(out.Messages); n > 0 && out.Messages[n-1].Role == converted.Role {
+			out.Messages[n-1].Content = append(out.Messages[n-1].Content, converted.Content...)
+			continue
+		}
+		out.Messages = append(out.Messages, converted)
+	}
+	out.System = strings.Join(system, "\n\n")
+	return out, nil
+}
+
+func messageText(message ChatCompletionMessage) string {
+	if len(message.MultiContent) == 0 {
+		return message.Content
+	}
+	var texts []string
+	for _, part := range message.MultiContent {
+		if part.Type == ChatMessagePartTypeText {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func newAnthropicMessage(message ChatCompletionMessage) (anthropicMessage, error) {
+	switch message.Role {
+	case ChatMessageRoleTool:
+		return anthropicMessage{
+			Role: ChatMessageRoleUser,
+			Content: []anthropicContentBlock{{
+				Type:      "tool_result",
+				ToolUseID: message.ToolCallID,
+				Content:   messageText(message),
+			}},
+		}, nil
+	case ChatMessageRoleUser, ChatMessageRoleAssistant:
+	default:
+		return anthropicMessage{}, fmt.Errorf("anthropic: unsupported message role %q", message.Role)
+	}
+
+	out := anthropicMessage{Role: message.Role}
+	if message.Content != "" {
+		out.Content = append(out.Content, anthropicContentBlock{Type: "text", Text: message.Content})
+	}
+	for _, part := range message.MultiContent {
+		switch part.Type {
+		case ChatMessagePartTypeText:
+			if part.Text != "" {
+				out.Content = append(out.Content, anthropicContentBlock{Type: "text", Text: part.Text})
+			}
+		case ChatMessagePartTypeImageURL:
+			if part.ImageURL == nil {
+				continue
+			}
+			out.Content = append(out.Content, anthropicContentBlock{
+				Type:   "image",
+				Source: newAnthropicImageSource(part.ImageURL.URL),
+			})
+		default:
+			return out, fmt.Errorf("anthropic: unsupported content part type %q", part.Type)
+		}
+	}
+	for _, call := range message.ToolCalls {
+		input := json.RawMessage(call.Function.Arguments)
+		if len(strings.TrimSpace(call.Function.Arguments)) == 0 {
+			input = json.RawMessage("{}")
+		}
+		out.Content = append(out.Content, anthropicContentBlock{
+			Type:  "tool_use",
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Input: input,
+		})
+	}
+	if len(out.Content) == 0 {
+		// The Messages API rejects messages without content blocks.
+		return out, fmt.Errorf("anthropic: %s message has no content", message.Role)
+	}
+	return out, nil
+}
+
+// newAnthropicImageSource converts an image URL, which may be a base64 data
+// URL such as "data:image/png;base64,...", into an image source.
+func newAnthropicImageSource(url string) *anthropicImageSource {
+	if rest, ok := cutPrefix(url, "data:"); ok {
+		if meta, data, found := strings.Cut(rest, ","); found {
+			if mediaType, isBase64 := cutSuffix(meta, ";base64"); isBase64 {
+				return &anthropicImageSource{Type: "base64", MediaType: mediaType, Data: data}
+			}
+		}
+	}
+	return &anthropicImageSource{Type: "url", URL: url}
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+func cutSuffix(s, suffix string) (string, bool) {
+	if !strings.HasSuffix(s, suffix) {
+		return s, false
+	}
+	return s[:len(s)-len(suffix)], true
+}
+
+func newAnthropicToolChoice(toolChoice, parallelToolCalls any) *anthropicToolChoice {
+	var choice *anthropicToolChoice
+	switch tc := toolChoice.(type) {
+	case string:
+		switch tc {
+		case "auto":
+			choice = &anthropicToolChoice{Type: "auto"}
+		case "none":
+			choice = &anthropicToolChoice{Type: "none"}
+		case "required":
+			choice = &anthropicToolChoice{Type: "any"}
+		}
+	case ToolChoice:
+		choice = &anthropicToolChoice{Type: "tool", Name: tc.Function.Name}
+	case *ToolChoice:
+		if tc != nil {
+			choice = &anthropicToolChoice{Type: "tool", Name: tc.Function.Name}
+		}
+	}
+
+	if parallel, ok := parallelToolCalls.(bool); ok && !parallel {
+		if choice == nil {
+			choice = &anthropicToolChoice{Type: "auto"}
+		}
+		if choice.Type != "none" {
+			choice.DisableParallelToolUse = true
+		}
+	}
+	return choice
+}
+
+func (r *anthropicMessagesResponse) toChatCompletionResponse() ChatCompletionResponse {
+	message := ChatCompletionMessage{Role: ChatMessageRoleAssistant}
+	var text, thinking strings.Builder
+	for _, block := range r.Content {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "thinking":
+			thinking.WriteString(block.Thinking)
+		case "tool_use":
+			message.ToolCalls = append(message.ToolCalls, ToolCall{
+				ID:   block.ID,
+				Type: ToolTypeFunction,
+				Function: FunctionCall{
+					Name:      block.Name,
+					Arguments: string(block.Input),
+				},
+			})
+		}
+	}
+	message.Content = text.String()
+	message.ReasoningContent = thinking.String()
+
+	return ChatCompletionResponse{
+		ID:      r.ID,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   r.Model,
+		Choices: []ChatCompletionChoice{{
+			Index:        0,
+			Message:      message,
+			FinishReason: anthropicFinishReason(r.StopReason),
+		}},
+		Usage: r.Usage.toUsage(r.Usage.OutputTokens),
+	}
+}
+
+func decodeAnthropicResponse(body io.Reader, v any) error {
+	var resp anthropicMessagesResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return err
+	}
+	out, ok := v.(*ChatCompletionResponse)
+	if !ok {
+		return fmt.Errorf("anthropic: unexpected response type %T", v)
+	}
+	header := out.httpHeader
+	*out = resp.toChatCompletionResponse()
+	out.httpHeader = header
+	return nil
+}
+
+func (c *Client) newAnthropicRequest(
+	ctx context.Context,
+	operation string,
+	request ChatCompletionRequest,
+) (*http.Request, error) {
+	body, err := newAnthropicMessagesRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	return c.newRequest(
+		ctx,
+		http.MethodPost,
+		c.fullURL(anthropicMessagesSuffix),
+		withBody(body),
+		withOperation(operation, request),
+	)
+}
+
+func (c *Client) createAnthropicChatCompletion(
+	ctx context.Context,
+	request ChatCompletionRequest,
+) (response ChatCompletionResponse, err error) {
+	req, err := c.newAnthropicRequest(ctx, "CreateChatCompletion", request)
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequestWithDecoder(req, &response, decodeAnthropicResponse)
+	return
+}
+
+func (c *Client) createAnthropicChatCompletionStream(
+	ctx context.Context,
+	request ChatCompletionRequest,
+) (*ChatCompletionStream, error) {
+	req, err := c.newAnthropicRequest(ctx, "CreateChatCompletionStream", request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sendRequestStream[ChatCompletionStreamResponse](c, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.translator = &anthropicStreamTranslator{
+		includeUsage: request.StreamOptions != nil && request.StreamOptions.IncludeUsage,
+		toolIndexes:  make(map[int]int),
+	}
+	return &ChatCompletionStream{streamReader: resp}, nil
+}
+
+type anthropicStreamEvent struct {
+	Type         string                     `json:"type"`
+	Index        int                        `json:"index"`
+	Message      *anthropicMessagesResponse `json:"message,omitempty"`
+	ContentBlock *anthropicContentBlock     `json:"content_block,omitempty"`
+	Delta        anthropicStreamDelta       `json:"delta"`
+	Usage        *anthropicUsage            `json:"usage,omitempty"`
+	Error        *APIError                  `json:"error,omitempty"`
+}
+
+type anthropicStreamDelta struct {
+	Type        string `json:"type"`
+	Text        string `json:"text"`
+	PartialJSON string `json:"partial_json"`
+	Thinking    string `json:"thinking"`
+	StopReason  string `json:"stop_reason"`
+}
+
+// anthropicStreamTranslator turns Messages API stream events into chat
+// completion chunks. Events without a chat completion equivalent, such as
+// ping and content_block_stop, are skipped.
+type anthropicStreamTranslator struct {
+	includeUsage bool
+
+	id           string
+	model        string
+	created      int64
+	usage        anthropicUsage
+	outputTokens int
+	// toolIndexes maps content block indexes to tool call indexes.
+	toolIndexes map[int]int
+}
+
+func (t *anthropicStreamTranslator) chunk(delta ChatCompletionStreamChoiceDelta) ChatCompletionStreamResponse {
+	return ChatCompletionStreamResponse{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []ChatCompletionStreamChoice{{Index: 0, Delta: delta}},
+	}
+}
+
+func (t *anthropicStreamTranslator) translate(
+	_ string,
+	raw []byte,
+) (chunk ChatCompletionStreamResponse, ok bool, err error) {
+	var event anthropicStreamEvent
+	if err = json.Unmarshal(raw, &event); err != nil {
+		return
+	}
+
+	switch event.Type {
+	case "message_start":
+		if event.Message != nil {
+			t.id, t.model = event.Message.ID, event.Message.Model
+			t.usage = event.Message.Usage
+			t.outputTokens = event.Message.Usage.OutputTokens
+		}
+		t.created = time.Now().Unix()
+		return t.chunk(ChatCompletionStreamChoiceDelta{Role: ChatMessageRoleAssistant}), true, nil
+	case "content_block_start":
+		if event.ContentBlock == nil || event.ContentBlock.Type != "tool_use" {
+			return
+		}
+		index := len(t.toolIndexes)
+		t.toolIndexes[event.Index] = index
+		return t.chunk(ChatCompletionStreamChoiceDelta{ToolCalls: []ToolCall{{
+			Index:    &index,
+			ID:       event.ContentBlock.ID,
+			Type:     ToolTypeFunction,
+			Function: FunctionCall{Name: event.ContentBlock.Name},
+		}}}), true, nil
+	case "content_block_delta":
+		return t.translateDelta(event)
+	case "message_delta":
+		if event.Usage != nil {
+			t.outputTokens = event.Usage.OutputTokens
+		}
+		chunk = t.chunk(ChatCompletionStreamChoiceDelta{})
+		chunk.Choices[0].FinishReason = anthropicFinishReason(event.Delta.StopReason)
+		return chunk, true, nil
+	case "message_stop":
+		if !t.includeUsage {
+			return chunk, false, io.EOF
+		}
+		usage := t.usage.toUsage(t.outputTokens)
+		chunk = t.chunk(ChatCompletionStreamChoiceDelta{})
+		chunk.Choices = []ChatCompletionStreamChoice{}
+		chunk.Usage = &usage
+		return chunk, true, nil
+	case "error":
+		if event.Error == nil {
+			event.Error = &APIError{Message: string(raw)}
+		}
+		return chunk, false, fmt.Errorf("error, %w", event.Error)
+	default:
+		return
+	}
+}
+
+func (t *anthropicStreamTranslator) translateDelta(
+	event anthropicStreamEvent,
+) (chunk ChatCompletionStreamResponse, ok bool, err error) {
+	switch event.Delta.Type {
+	case "text_delta":
+		return t.chunk(ChatCompletionStreamChoiceDelta{Content: event.Delta.Text}), true, nil
+	case "thinking_delta":
+		return t.chunk(ChatCompletionStreamChoiceDelta{ReasoningContent: event.Delta.Thinking}), true, nil
+	case "input_json_delta":
+		index, known := t.toolIndexes[event.Index]
+		if !known {
+			return
+		}
+		return t.chunk(ChatCompletionStreamChoiceDelta{ToolCalls: []ToolCall{{
+			Index:    &index,
+			Function: FunctionCall{Arguments: event.Delta.PartialJSON},
+		}}}), true, nil
+	default:
+		return
+	}
+}
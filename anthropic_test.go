@@ -0,0 +1,291 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+func setupAnthropicTestServer() (client *openai.Client, server *test.ServerTest, teardown func()) {
+	server = test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	teardown = ts.Close
+	config := openai.DefaultAnthropicConfig(test.GetTestToken(), ts.URL+"/v1")
+	client = openai.NewClientWithConfig(config)
+	return
+}
+
+func TestAnthropicChatCompletion(t *testing.T) {
+	client, server, teardown := setupAnthropicTestServer()
+	defer teardown()
+
+	var body map[string]any
+	server.RegisterHandler("/v1/messages", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(openai.AnthropicAPIKeyHeader) != test.GetTestToken() {
+			t.Errorf("unexpected %s header: %q", openai.AnthropicAPIKeyHeader, r.Header.Get(openai.AnthropicAPIKeyHeader))
+		}
+		if r.Header.Get("anthropic-version") != openai.AnthropicAPIVersion {
+			t.Errorf("unexpected anthropic-version header: %q", r.Header.Get("anthropic-version"))
+		}
+		if r.URL.RawQuery != "" {
+			t.Errorf("unexpected query: %q", r.URL.RawQuery)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		fmt.Fprint(w, `{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4",
+			"content":[{"type":"thinking","thinking":"hmm"},{"type":"text","text":"Checking."},
+				{"type":"tool_use","id":"toolu_2","name":"get_weather","input":{"city":"Paris"}}],
+			"stop_reason":"tool_use",
+			"usage":{"input_tokens":10,"cache_read_input_tokens":5,"output_tokens":7}}`)
+	})
+
+	resp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model: "claude-sonnet-4",
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: "Be brief."},
+			{Role: openai.ChatMessageRoleUser, MultiContent: []openai.ChatMessagePart{
+				{Type: openai.ChatMessagePartTypeText, Text: "What is this?"},
+				{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{
+					URL: "data:image/png;base64,aGVsbG8=",
+				}},
+			}},
+			{Role: openai.ChatMessageRoleAssistant, ToolCalls: []openai.ToolCall{{
+				ID:       "toolu_1",
+				Type:     openai.ToolTypeFunction,
+				Function: openai.FunctionCall{Name: "get_weather", Arguments: `{"city":"Rome"}`},
+			}}},
+			{Role: openai.ChatMessageRoleTool, ToolCallID: "toolu_1", Content: "sunny"},
+			{Role: openai.ChatMessageRoleUser, Content: "And Paris?"},
+		},
+		Tools: []openai.Tool{{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name: "get_weather",
+				Parameters: jsonschema.Definition{
+					Type:       jsonschema.Object,
+					Properties: map[string]jsonschema.Definition{"city": {Type: jsonschema.String}},
+				},
+			},
+		}},
+		ToolChoice: "required",
+		Stop:       []string{"END"},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	if body["system"] != "Be brief." || body["max_tokens"] != float64(openai.AnthropicDefaultMaxTokens) {
+		t.Errorf("unexpected request: %v", body)
+	}
+	if fmt.Sprint(body["stop_sequences"]) != "[END]" || fmt.Sprint(body["tool_choice"]) != "map[type:any]" {
+		t.Errorf("unexpected request: %v", body)
+	}
+	messages, _ := body["messages"].([]any)
+	if len(messages) != 3 {
+		t.Fatalf("expected alternating user, assistant, user messages, got %v", messages)
+	}
+	user, _ := json.Marshal(messages[0])
+	expectedUser := `{"content":[{"text":"What is this?","type":"text"},` +
+		`{"source":{"data":"aGVsbG8=","media_type":"image/png","type":"base64"},"type":"image"}],"role":"user"}`
+	if string(user) != expectedUser {
+		t.Errorf("unexpected user message: %s", user)
+	}
+	assistant, _ := json.Marshal(messages[1])
+	expectedAssistant := `{"content":[{"id":"toolu_1","input":{"city":"Rome"},"name":"get_weather",` +
+		`"type":"tool_use"}],"role":"assistant"}`
+	if string(assistant) != expectedAssistant {
+		t.Errorf("unexpected assistant message: %s", assistant)
+	}
+	result, _ := json.Marshal(messages[2])
+	expectedResult := `{"content":[{"content":"sunny","tool_use_id":"toolu_1","type":"tool_result"},` +
+		`{"text":"And Paris?","type":"text"}],"role":"user"}`
+	if string(result) != expectedResult {
+		t.Errorf("unexpected tool result message: %s", result)
+	}
+
+	if resp.ID != "msg_1" || len(resp.Choices) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	choice := resp.Choices[0]
+	if choice.FinishReason != openai.FinishReasonToolCalls || choice.Message.Content != "Checking." ||
+		choice.Message.ReasoningContent != "hmm" {
+		t.Errorf("unexpected choice: %+v", choice)
+	}
+	if len(choice.Message.ToolCalls) != 1 || choice.Message.ToolCalls[0].ID != "toolu_2" ||
+		choice.Message.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool calls: %+v", choice.Message.ToolCalls)
+	}
+	if resp.Usage.PromptTokens != 15 || resp.Usage.CompletionTokens != 7 || resp.Usage.TotalTokens != 22 ||
+		resp.Usage.PromptTokensDetails.CachedTokens != 5 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestAnthropicChatCompletionError(t *testing.T) {
+	client, server, teardown := setupAnthropicTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/messages", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"type":"error","error":{"type":"invalid_request_error","message":"bad"}}`)
+	})
+
+	_, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    "claude-sonnet-4",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+	})
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "bad" || apiErr.HTTPStatusCode != http.StatusBadRequest {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+}
+
+func TestAnthropicChatCompletionStream(t *testing.T) {
+	client, server, teardown := setupAnthropicTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/messages", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["stream"] != true {
+			t.Errorf("expected a streaming request, got %v (%v)", body, err)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"message_start","message":{"id":"msg_1","model":"claude-sonnet-4",` +
+				`"content":[],"usage":{"input_tokens":9,"output_tokens":1}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"ping"}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hi"}}`,
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1",` +
+				`"name":"get_weather","input":{}}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`,
+			`{"type":"content_block_stop","index":1}`,
+			`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":12}}`,
+			`{"type":"message_stop"}`,
+		}
+		for _, event := range events {
+			var typed struct{ Type string }
+			_ = json.Unmarshal([]byte(event), &typed)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typed.Type, event)
+		}
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:         "claude-sonnet-4",
+		Messages:      []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+		StreamOptions: &openai.StreamOptions{IncludeUsage: true},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	var (
+		content, arguments string
+		finishReason       openai.FinishReason
+		toolCallID         string
+		usage              *openai.Usage
+	)
+	for {
+		chunk, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		if chunk.ID != "msg_1" || chunk.Model != "claude-sonnet-4" {
+			t.Errorf("unexpected chunk: %+v", chunk)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			content += choice.Delta.Content
+			if choice.FinishReason != "" {
+				finishReason = choice.FinishReason
+			}
+			for _, call := range choice.Delta.ToolCalls {
+				if call.Index == nil || *call.Index != 0 {
+					t.Errorf("unexpected tool call index: %v", call.Index)
+				}
+				if call.ID != "" {
+					toolCallID = call.ID
+				}
+				arguments += call.Function.Arguments
+			}
+		}
+	}
+
+	if content != "Hi" || toolCallID != "toolu_1" || arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected stream content %q, tool call %q, arguments %q", content, toolCallID, arguments)
+	}
+	if finishReason != openai.FinishReasonToolCalls {
+		t.Errorf("unexpected finish reason: %s", finishReason)
+	}
+	if usage == nil || usage.PromptTokens != 9 || usage.CompletionTokens != 12 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
+
+func TestAnthropicChatCompletionStreamError(t *testing.T) {
+	client, server, teardown := setupAnthropicTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/messages", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: error\n"+
+			`data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`+"\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    "claude-sonnet-4",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "Overloaded" {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+}
+
+func TestAnthropicChatCompletionUnsupported(t *testing.T) {
+	client, server, teardown := setupAnthropicTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/messages", func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("unexpected request")
+	})
+
+	seed := 1
+	messages := []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}}
+	for field, request := range map[string]openai.ChatCompletionRequest{
+		"response_format": {ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}},
+		"n":          {N: 2},
+		"seed":       {Seed: &seed},
+		"logprobs":   {LogProbs: true},
+		"logit_bias": {LogitBias: map[string]int{"1": 1}},
+	} {
+		request.Model = "claude-sonnet-4"
+		request.Messages = messages
+		_, err := client.CreateChatCompletion(context.Background(), request)
+		if !errors.Is(err, openai.ErrAnthropicUnsupportedField) || !strings.HasSuffix(err.Error(), field) {
+			t.Errorf("%s: expected ErrAnthropicUnsupportedField, got %v", field, err)
+		}
+	}
+
+	_, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    "claude-sonnet-4",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser}},
+	})
+	checks.HasError(t, err, "expected an error for a message without content")
+}
@@ -125,6 +125,11 @@ func (c *Client) callAudioAPI(
 		return AudioResponse{}, err
 	}
 
+	operation := "CreateTranscription"
+	if endpointSuffix == "translations" {
+		operation = "CreateTranslation"
+	}
+
 	urlSuffix := fmt.Sprintf("/audio/%s", endpointSuffix)
 	req, err := c.newRequest(
 		ctx,
@@ -132,6 +137,7 @@ func (c *Client) callAudioAPI(
 		c.fullURL(urlSuffix, withModel(request.Model)),
 		withBody(&formBody),
 		withContentType(builder.FormDataContentType()),
+		withOperation(operation, request),
 	)
 	if err != nil {
 		return AudioResponse{}, err
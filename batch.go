@@ -130,7 +130,8 @@ func (c *Client) CreateBatch(
 		request.CompletionWindow = "24h"
 	}
 
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(batchesSuffix), withBody(request))
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(batchesSuffix), withBody(request),
+		withOperation("CreateBatch", request))
 	if err != nil {
 		return
 	}
@@ -237,7 +238,7 @@ func (c *Client) RetrieveBatch(
 	batchID string,
 ) (response BatchResponse, err error) {
 	urlSuffix := fmt.Sprintf("%s/%s", batchesSuffix, batchID)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("RetrieveBatch", nil))
 	if err != nil {
 		return
 	}
@@ -251,7 +252,7 @@ func (c *Client) CancelBatch(
 	batchID string,
 ) (response BatchResponse, err error) {
 	urlSuffix := fmt.Sprintf("%s/%s/cancel", batchesSuffix, batchID)
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix))
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withOperation("CancelBatch", nil))
 	if err != nil {
 		return
 	}
@@ -283,7 +284,7 @@ func (c *Client) ListBatch(ctx context.Context, after *string, limit *int) (resp
 	}
 
 	urlSuffix := fmt.Sprintf("%s%s", batchesSuffix, encodedValues)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("ListBatch", nil))
 	if err != nil {
 		return
 	}
@@ -517,11 +517,16 @@ func (c *Client) CreateChatCompletion(
 		return
 	}
 
+	if c.config.APIType == APITypeAnthropic {
+		return c.createAnthropicChatCompletion(ctx, request)
+	}
+
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
 		c.fullURL(urlSuffix, withModel(request.Model)),
 		withBody(request),
+		withOperation("CreateChatCompletion", request),
 	)
 	if err != nil {
 		return
@@ -91,11 +91,16 @@ func (c *Client) CreateChatCompletionStream(
 		return
 	}
 
+	if c.config.APIType == APITypeAnthropic {
+		return c.createAnthropicChatCompletionStream(ctx, request)
+	}
+
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
 		c.fullURL(urlSuffix, withModel(request.Model)),
 		withBody(request),
+		withOperation("CreateChatCompletionStream", request),
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,242 @@
+package openai
+
+import (
+	"errors"
+	"io"
+	"sort"
+)
+
+// ChatCompletionStreamAccumulator assembles the chunks of a
+// ChatCompletionStream into the ChatCompletionResponse the same request would
+// have returned without streaming: one choice per index with its content,
+// refusal, reasoning content, tool calls, finish reason and logprobs, and the
+// usage reported by the final chunk when StreamOptions.IncludeUsage is set.
+type ChatCompletionStreamAccumulator struct {
+	// OnToolCallDone, when set, is called with each tool call as soon as its
+	// arguments are complete, i.e. once the stream moves on to the next tool
+	// call or the choice finishes, so that it can be executed while the rest
+	// of the response is still being generated.
+	OnToolCallDone func(choiceIndex int, toolCall ToolCall)
+
+	response ChatCompletionResponse
+	choices  map[int]*accumulatedChoice
+}
+
+type accumulatedChoice struct {
+	choice ChatCompletionChoice
+	// toolCalls maps tool call indexes to positions in choice.Message.ToolCalls.
+	toolCalls map[int]int
+	// openToolCall is the position of the tool call receiving deltas, or -1.
+	openToolCall int
+}
+
+// NewChatCompletionStreamAccumulator creates an empty ChatCompletionStreamAccumulator.
+func NewChatCompletionStreamAccumulator() *ChatCompletionStreamAccumulator {
+	return &ChatCompletionStreamAccumulator{
+		response: ChatCompletionResponse{Object: "chat.completion"},
+		choices:  make(map[int]*accumulatedChoice),
+	}
+}
+
+// Consume reads stream until it ends, accumulating every chunk, and returns
+// the assembled response.
+func (a *ChatCompletionStreamAccumulator) Consume(stream *ChatCompletionStream) (ChatCompletionResponse, error) {
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			a.closeToolCalls()
+			return a.Response(), nil
+		}
+		if err != nil {
+			return a.Response(), err
+		}
+		a.Add(chunk)
+	}
+}
+
+// Add merges a chunk into the response.
+func (a *ChatCompletionStreamAccumulator) Add(chunk ChatCompletionStreamResponse) {
+	if chunk.ID != "" {
+		a.response.ID = chunk.ID
+	}
+	if chunk.Model != "" {
+		a.response.Model = chunk.Model
+	}
+	if chunk.Created != 0 {
+		a.response.Created = chunk.Created
+	}
+	if chunk.SystemFingerprint != "" {
+		a.response.SystemFingerprint = chunk.SystemFingerprint
+	}
+	a.response.PromptFilterResults = append(a.response.PromptFilterResults, chunk.PromptFilterResults...)
+	if chunk.Usage != nil {
+		a.response.Usage = *chunk.Usage
+	}
+
+	for _, delta := range chunk.Choices {
+		a.addChoice(delta)
+	}
+}
+
+func (a *ChatCompletionStreamAccumulator) addChoice(delta ChatCompletionStreamChoice) {
+	acc, ok := a.choices[delta.Index]
+	if !ok {
+		acc = &accumulatedChoice{
+			choice:       ChatCompletionChoice{Index: delta.Index},
+			toolCalls:    make(map[int]int),
+			openToolCall: -1,
+		}
+		a.choices[delta.Index] = acc
+	}
+
+	message := &acc.choice.Message
+	if delta.Delta.Role != "" {
+		message.Role = delta.Delta.Role
+	}
+	message.Content += delta.Delta.Content
+	message.Refusal += delta.Delta.Refusal
+	message.ReasoningContent += delta.Delta.ReasoningContent
+	if call := delta.Delta.FunctionCall; call != nil {
+		if message.FunctionCall == nil {
+			message.FunctionCall = &FunctionCall{}
+		}
+		message.FunctionCall.Name += call.Name
+		message.FunctionCall.Arguments += call.Arguments
+	}
+	for _, toolCall := range delta.Delta.ToolCalls {
+		a.addToolCall(acc, toolCall)
+	}
+
+	if delta.Logprobs != nil {
+		if acc.choice.LogProbs == nil {
+			acc.choice.LogProbs = &LogProbs{}
+		}
+		acc.choice.LogProbs.Content = append(acc.choice.LogProbs.Content, toLogProbs(delta.Logprobs.Content)...)
+	}
+	if delta.ContentFilterResults != (ContentFilterResults{}) {
+		acc.choice.ContentFilterResults = delta.ContentFilterResults
+	}
+	if delta.FinishReason != "" {
+		acc.choice.FinishReason = delta.FinishReason
+		a.closeToolCall(acc)
+	}
+}
+
+func (a *ChatCompletionStreamAccumulator) addToolCall(acc *accumulatedChoice, delta ToolCall) {
+	calls := &acc.choice.Message.ToolCalls
+
+	position := -1
+	switch {
+	case delta.Index != nil:
+		if p, ok := acc.toolCalls[*delta.Index]; ok {
+			position = p
+		}
+	case delta.ID == "" && len(*calls) > 0:
+		// Some providers omit the index; fragments without an ID continue
+		// the latest tool call.
+		position = len(*calls) - 1
+	}
+
+	if position < 0 {
+		position = len(*calls)
+		*calls = append(*calls, ToolCall{})
+		if delta.Index != nil {
+			acc.toolCalls[*delta.Index] = position
+		}
+	}
+	if position != acc.openToolCall {
+		a.closeToolCall(acc)
+		acc.openToolCall = position
+	}
+
+	call := &(*calls)[position]
+	if delta.ID != "" {
+		call.ID = delta.ID
+	}
+	if delta.Type != "" {
+		call.Type = delta.Type
+	}
+	call.Function.Name += delta.Function.Name
+	call.Function.Arguments += delta.Function.Arguments
+}
+
+func (a *ChatCompletionStreamAccumulator) closeToolCall(acc *accumulatedChoice) {
+	if acc.openToolCall < 0 {
+		return
+	}
+	call := acc.choice.Message.ToolCalls[acc.openToolCall]
+	acc.openToolCall = -1
+	if a.OnToolCallDone != nil {
+		a.OnToolCallDone(acc.choice.Index, call)
+	}
+}
+
+func (a *ChatCompletionStreamAccumulator) closeToolCalls() {
+	for _, index := range a.choiceIndexes() {
+		a.closeToolCall(a.choices[index])
+	}
+}
+
+func (a *ChatCompletionStreamAccumulator) choiceIndexes() []int {
+	indexes := make([]int, 0, len(a.choices))
+	for index := range a.choices {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// Response returns the response assembled so far, with the choices ordered by
+// index. The returned value does not change as more chunks are added.
+func (a *ChatCompletionStreamAccumulator) Response() ChatCompletionResponse {
+	response := a.response
+	response.PromptFilterResults = append([]PromptFilterResult(nil), a.response.PromptFilterResults...)
+	response.Choices = make([]ChatCompletionChoice, 0, len(a.choices))
+	for _, index := range a.choiceIndexes() {
+		choice := a.choices[index].choice
+		if choice.Message.ToolCalls != nil {
+			choice.Message.ToolCalls = append([]ToolCall(nil), choice.Message.ToolCalls...)
+		}
+		if choice.Message.FunctionCall != nil {
+			functionCall := *choice.Message.FunctionCall
+			choice.Message.FunctionCall = &functionCall
+		}
+		if choice.LogProbs != nil {
+			choice.LogProbs = &LogProbs{Content: append([]LogProb(nil), choice.LogProbs.Content...)}
+		}
+		response.Choices = append(response.Choices, choice)
+	}
+	return response
+}
+
+func toLogProbs(tokens []ChatCompletionTokenLogprob) []LogProb {
+	logProbs := make([]LogProb, 0, len(tokens))
+	for _, token := range tokens {
+		logProb := LogProb{
+			Token:       token.Token,
+			LogProb:     token.Logprob,
+			Bytes:       toBytes(token.Bytes),
+			TopLogProbs: make([]TopLogProbs, 0, len(token.TopLogprobs)),
+		}
+		for _, top := range token.TopLogprobs {
+			logProb.TopLogProbs = append(logProb.TopLogProbs, TopLogProbs{
+				Token:   top.Token,
+				LogProb: top.Logprob,
+				Bytes:   toBytes(top.Bytes),
+			})
+		}
+		logProbs = append(logProbs, logProb)
+	}
+	return logProbs
+}
+
+func toBytes(values []int64) []byte {
+	if values == nil {
+		return nil
+	}
+	b := make([]byte, len(values))
+	for i, v := range values {
+		b[i] = byte(v)
+	}
+	return b
+}
@@ -0,0 +1,125 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestChatCompletionStreamAccumulator(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"choices":[{"index":0,"delta":{"role":"assistant","content":""}},` +
+				`{"index":1,"delta":{"role":"assistant","content":""}}]}`,
+			`{"choices":[{"index":1,"delta":{"content":"Hel"},"logprobs":{"content":[` +
+				`{"token":"Hel","bytes":[72,101,108],"logprob":-0.1,"top_logprobs":[]}]}}]}`,
+			`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function",` +
+				`"function":{"name":"get_weather","arguments":""}}]}}]}`,
+			`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`,
+			`{"choices":[{"index":1,"delta":{"content":"lo"},"logprobs":{"content":[` +
+				`{"token":"lo","logprob":-0.2,"top_logprobs":[{"token":"lo","logprob":-0.2}]}]}}]}`,
+			`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`,
+			`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function",` +
+				`"function":{"name":"get_time","arguments":"{}"}}]}}]}`,
+			`{"choices":[{"index":1,"delta":{},"finish_reason":"stop"}]}`,
+			`{"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}}`,
+		} {
+			fmt.Fprintf(w, "data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,"+
+				"\"model\":\"gpt-4o\",\"system_fingerprint\":\"fp_1\",%s\n\n", chunk[1:])
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:         openai.GPT4o,
+		N:             2,
+		Messages:      []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+		StreamOptions: &openai.StreamOptions{IncludeUsage: true},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	var closed []string
+	accumulator := openai.NewChatCompletionStreamAccumulator()
+	accumulator.OnToolCallDone = func(choiceIndex int, toolCall openai.ToolCall) {
+		// The response is still streaming when the first tool call closes.
+		closed = append(closed, fmt.Sprintf("%d:%s:%s", choiceIndex, toolCall.ID, toolCall.Function.Arguments))
+	}
+	response, err := accumulator.Consume(stream)
+	checks.NoError(t, err, "Consume error")
+
+	if response.ID != "chatcmpl-1" || response.Object != "chat.completion" || response.Model != "gpt-4o" ||
+		response.SystemFingerprint != "fp_1" || response.Created != 1 {
+		t.Errorf("unexpected response metadata: %+v", response)
+	}
+	if response.Usage.TotalTokens != 30 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+	if len(response.Choices) != 2 {
+		t.Fatalf("expected 2 choices, got %d", len(response.Choices))
+	}
+
+	tools := response.Choices[0]
+	if tools.FinishReason != openai.FinishReasonToolCalls || tools.Message.Role != openai.ChatMessageRoleAssistant {
+		t.Errorf("unexpected tool call choice: %+v", tools)
+	}
+	if len(tools.Message.ToolCalls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %+v", tools.Message.ToolCalls)
+	}
+	first, second := tools.Message.ToolCalls[0], tools.Message.ToolCalls[1]
+	if first.ID != "call_1" || first.Type != openai.ToolTypeFunction || first.Function.Name != "get_weather" ||
+		first.Function.Arguments != `{"city":"Paris"}` || first.Index != nil {
+		t.Errorf("unexpected first tool call: %+v", first)
+	}
+	if second.ID != "call_2" || second.Function.Name != "get_time" || second.Function.Arguments != "{}" {
+		t.Errorf("unexpected second tool call: %+v", second)
+	}
+	if fmt.Sprint(closed) != `[0:call_1:{"city":"Paris"} 0:call_2:{}]` {
+		t.Errorf("unexpected closed tool calls: %v", closed)
+	}
+
+	text := response.Choices[1]
+	if text.Index != 1 || text.Message.Content != "Hello" || text.FinishReason != openai.FinishReasonStop {
+		t.Errorf("unexpected text choice: %+v", text)
+	}
+	if text.LogProbs == nil || len(text.LogProbs.Content) != 2 || string(text.LogProbs.Content[0].Bytes) != "Hel" ||
+		len(text.LogProbs.Content[1].TopLogProbs) != 1 {
+		t.Errorf("unexpected logprobs: %+v", text.LogProbs)
+	}
+}
+
+func TestChatCompletionStreamAccumulatorSnapshot(t *testing.T) {
+	accumulator := openai.NewChatCompletionStreamAccumulator()
+	index := 0
+	accumulator.Add(openai.ChatCompletionStreamResponse{Choices: []openai.ChatCompletionStreamChoice{{
+		Delta: openai.ChatCompletionStreamChoiceDelta{ToolCalls: []openai.ToolCall{{
+			Index:    &index,
+			ID:       "call_1",
+			Function: openai.FunctionCall{Name: "f", Arguments: `{"a":`},
+		}}},
+	}}})
+	snapshot := accumulator.Response()
+
+	accumulator.Add(openai.ChatCompletionStreamResponse{Choices: []openai.ChatCompletionStreamChoice{{
+		Delta: openai.ChatCompletionStreamChoiceDelta{ToolCalls: []openai.ToolCall{{
+			Index:    &index,
+			Function: openai.FunctionCall{Arguments: `1}`},
+		}}},
+		FinishReason: openai.FinishReasonToolCalls,
+	}}})
+
+	if args := snapshot.Choices[0].Message.ToolCalls[0].Function.Arguments; args != `{"a":` {
+		t.Errorf("snapshot should not change, got %q", args)
+	}
+	if args := accumulator.Response().Choices[0].Message.ToolCalls[0].Function.Arguments; args != `{"a":1}` {
+		t.Errorf("unexpected arguments: %q", args)
+	}
+}
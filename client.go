@@ -72,8 +72,9 @@ func NewOrgClient(authToken, org string) *Client {
 }
 
 type requestOptions struct {
-	body   any
-	header http.Header
+	body      any
+	header    http.Header
+	operation *Operation
 }
 
 type requestOption func(*requestOptions)
@@ -123,11 +124,22 @@ func (c *Client) newRequest(ctx context.Context, method, url string, setters ...
 	if err != nil {
 		return nil, err
 	}
-	c.setCommonHeaders(req)
+	if args.operation != nil {
+		req = req.WithContext(context.WithValue(req.Context(), operationContextKey{}, args.operation))
+	}
+	if err = c.setCommonHeaders(req); err != nil {
+		return nil, err
+	}
 	return req, nil
 }
 
 func (c *Client) sendRequest(req *http.Request, v Response) error {
+	return c.sendRequestWithDecoder(req, v, decodeResponse)
+}
+
+// sendRequestWithDecoder is sendRequest with a custom decoder for the
+// response body, used when the wire format differs from the typed response.
+func (c *Client) sendRequestWithDecoder(req *http.Request, v Response, decode func(io.Reader, any) error) error {
 	req.Header.Set("Accept", "application/json")
 
 	// Check whether Content-Type is already set, Upload Files API requires
@@ -137,37 +149,49 @@ func (c *Client) sendRequest(req *http.Request, v Response) error {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	res, err := c.config.HTTPClient.Do(req)
-	if err != nil {
-		return err
-	}
+	return c.runMiddleware(req, v, false, func(req *http.Request) error {
+		res, err := c.doRequest(req)
+		if err != nil {
+			return err
+		}
 
-	defer res.Body.Close()
+		defer res.Body.Close()
 
-	if v != nil {
-		v.SetHeader(res.Header)
-	}
+		if v != nil {
+			v.SetHeader(res.Header)
+		}
 
-	if isFailureStatusCode(res) {
-		return c.handleErrorResp(res)
-	}
+		if isFailureStatusCode(res) {
+			return c.handleErrorResp(res)
+		}
 
-	return decodeResponse(res.Body, v)
+		return decode(res.Body, v)
+	})
 }
 
 func (c *Client) sendRequestRaw(req *http.Request) (response RawResponse, err error) {
-	resp, err := c.config.HTTPClient.Do(req) //nolint:bodyclose // body should be closed by outer function
-	if err != nil {
-		return
-	}
+	err = c.runMiddleware(req, &response, false, func(req *http.Request) error {
+		resp, doErr := c.doRequest(req) //nolint:bodyclose // body should be closed by outer function
+		if doErr != nil {
+			return doErr
+		}
 
-	if isFailureStatusCode(resp) {
-		err = c.handleErrorResp(resp)
-		return
-	}
+		if isFailureStatusCode(resp) {
+			return c.handleErrorResp(resp)
+		}
 
-	response.SetHeader(resp.Header)
-	response.ReadCloser = resp.Body
+		response.SetHeader(resp.Header)
+		response.ReadCloser = resp.Body
+		return nil
+	})
+	if err != nil && response.ReadCloser != nil {
+		// A middleware failed the call after the body was received.
+		response.ReadCloser.Close()
+		response.ReadCloser = nil
+	}
+	if err == nil && response.ReadCloser == nil {
+		err = ErrMiddlewareNoResponse
+	}
 	return
 }
 
@@ -177,13 +201,23 @@ func sendRequestStream[T streamable](client *Client, req *http.Request) (*stream
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Connection", "keep-alive")
 
-	resp, err := client.config.HTTPClient.Do(req) //nolint:bodyclose // body is closed in stream.Close()
+	var resp *http.Response
+	err := client.runMiddleware(req, nil, true, func(req *http.Request) (err error) {
+		resp, err = client.doRequest(req) //nolint:bodyclose // body is closed in stream.Close()
+		if err != nil {
+			return err
+		}
+		if isFailureStatusCode(resp) {
+			return client.handleErrorResp(resp)
+		}
+		return nil
+	})
+	if err == nil && resp == nil {
+		err = ErrMiddlewareNoResponse
+	}
 	if err != nil {
 		return new(streamReader[T]), err
 	}
-	if isFailureStatusCode(resp) {
-		return new(streamReader[T]), client.handleErrorResp(resp)
-	}
 	return &streamReader[T]{
 		emptyMessagesLimit: client.config.EmptyMessagesLimit,
 		reader:             bufio.NewReader(resp.Body),
@@ -191,29 +225,48 @@ func sendRequestStream[T streamable](client *Client, req *http.Request) (*stream
 		errAccumulator:     utils.NewErrorAccumulator(),
 		unmarshaler:        &utils.JSONUnmarshaler{},
 		httpHeader:         httpHeader(resp.Header),
+		observer:           operationFromRequest(req).streamObserver,
 	}, nil
 }
 
-func (c *Client) setCommonHeaders(req *http.Request) {
+func (c *Client) setCommonHeaders(req *http.Request) error {
+	authToken := c.config.authToken
+	if c.config.TokenProvider != nil {
+		token, err := c.config.TokenProvider.Token(req.Context())
+		if err != nil {
+			return fmt.Errorf("error, fetching access token: %w", err)
+		}
+		authToken = token
+	}
+
 	// https://learn.microsoft.com/en-us/azure/cognitive-services/openai/reference#authentication
 	switch c.config.APIType {
 	case APITypeAzure, APITypeCloudflareAzure:
+		if c.config.TokenProvider != nil {
+			// Microsoft Entra ID authentication
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", authToken))
+			break
+		}
 		// Azure API Key authentication
-		req.Header.Set(AzureAPIKeyHeader, c.config.authToken)
+		req.Header.Set(AzureAPIKeyHeader, authToken)
 	case APITypeAnthropic:
 		// https://docs.anthropic.com/en/api/versioning
 		req.Header.Set("anthropic-version", c.config.APIVersion)
+		if authToken != "" {
+			req.Header.Set(AnthropicAPIKeyHeader, authToken)
+		}
 	case APITypeOpenAI, APITypeAzureAD:
 		fallthrough
 	default:
-		if c.config.authToken != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.authToken))
+		if authToken != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", authToken))
 		}
 	}
 
 	if c.config.OrgID != "" {
 		req.Header.Set("OpenAI-Organization", c.config.OrgID)
 	}
+	return nil
 }
 
 func isFailureStatusCode(resp *http.Response) bool {
@@ -278,7 +331,8 @@ func (c *Client) fullURL(suffix string, setters ...fullURLOption) string {
 		baseURL = c.baseURLWithAzureDeployment(baseURL, suffix, args.model)
 	}
 
-	if c.config.APIVersion != "" {
+	// Anthropic takes the API version as a header rather than a query parameter.
+	if c.config.APIVersion != "" && c.config.APIType != APITypeAnthropic {
 		suffix = c.suffixWithAPIVersion(suffix)
 	}
 	return fmt.Sprintf("%s%s", baseURL, suffix)
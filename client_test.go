@@ -52,6 +52,9 @@ func TestSetCommonHeadersAnthropic(t *testing.T) {
 	if got := req.Header.Get("anthropic-version"); got != AnthropicAPIVersion {
 		t.Errorf("Expected anthropic-version header to be %q, got %q", AnthropicAPIVersion, got)
 	}
+	if got := req.Header.Get(AnthropicAPIKeyHeader); got != "mock-token" {
+		t.Errorf("Expected %s header to be %q, got %q", AnthropicAPIKeyHeader, "mock-token", got)
+	}
 }
 
 func TestDecodeResponse(t *testing.T) {
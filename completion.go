@@ -336,6 +336,7 @@ func (c *Client) CreateCompletion(
 		http.MethodPost,
 		c.fullURL(urlSuffix, withModel(request.Model)),
 		withBody(request),
+		withOperation("CreateCompletion", request),
 	)
 	if err != nil {
 		return
@@ -10,6 +10,8 @@ const (
 	openaiAPIURLv1                 = "https://api.openai.com/v1"
 	defaultEmptyMessagesLimit uint = 300
 
+	defaultResponseStreamMaxReconnects = 3
+
 	azureAPIPrefix         = "openai"
 	azureDeploymentsPrefix = "deployments"
 
@@ -28,6 +30,8 @@ const (
 
 const AzureAPIKeyHeader = "api-key"
 
+const AnthropicAPIKeyHeader = "x-api-key"
+
 const defaultAssistantVersion = "v2" // upgrade to v2 to support vector store
 
 type HTTPDoer interface {
@@ -45,6 +49,20 @@ type ClientConfig struct {
 	AssistantVersion     string
 	AzureModelMapperFunc func(model string) string // replace model to azure deployment name func
 	HTTPClient           HTTPDoer
+	// TokenProvider supplies the bearer token for every request, overriding the static token.
+	TokenProvider TokenProvider
+	// RetryPolicy enables automatic retries of failed requests. Nil disables retries.
+	RetryPolicy *RetryPolicy
+	// RateLimiter keeps calls within the per-model rate limits reported by the API.
+	RateLimiter *RateLimiter
+	// Middlewares wrap every typed API call, outermost first.
+	Middlewares []Middleware
+	// Instrumentation receives tracing spans and metrics for every API call.
+	Instrumentation Instrumentation
+	// ResponseStreamMaxReconnects limits the consecutive attempts to resume
+	// the stream of a background response after the connection dropped. Zero
+	// means the default of 3 attempts, and a negative value disables resuming.
+	ResponseStreamMaxReconnects int
 
 	EmptyMessagesLimit uint
 }
@@ -0,0 +1,240 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const conversationsSuffix = "/conversations"
+
+// Conversation holds the state of a multi-turn interaction on the server.
+// Pass its ID as CreateResponseRequest.Conversation to add the input and
+// output of a response to the conversation.
+type Conversation struct {
+	ID        string         `json:"id"`
+	Object    string         `json:"object"`
+	CreatedAt int64          `json:"created_at"`
+	Metadata  map[string]any `json:"metadata"`
+
+	httpHeader
+}
+
+// ConversationRequest is the request to create a conversation. Items, of which
+// at most 20 can be given, start the conversation and are typically built
+// with a ResponseInputBuilder.
+type ConversationRequest struct {
+	Items    []ResponseInputItem `json:"items,omitempty"`
+	Metadata map[string]any      `json:"metadata,omitempty"`
+}
+
+// ModifyConversationRequest is the request to update a conversation.
+type ModifyConversationRequest struct {
+	Metadata map[string]any `json:"metadata"`
+}
+
+// ConversationDeleteResponse is returned after deleting a conversation.
+type ConversationDeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+
+	httpHeader
+}
+
+// ConversationItem is an item of a conversation. Messages keep the role and
+// content of input messages as well; items of other input types leave every
+// variant nil and are available in Raw.
+type ConversationItem struct {
+	ResponseOutput
+
+	httpHeader
+}
+
+// ConversationItemList is a page of conversation items.
+type ConversationItemList struct {
+	Object  string             `json:"object"`
+	Data    []ConversationItem `json:"data"`
+	FirstID string             `json:"first_id"`
+	LastID  string             `json:"last_id"`
+	HasMore bool               `json:"has_more"`
+
+	httpHeader
+}
+
+// ConversationItemsListOptions controls pagination for ListConversationItems.
+// To fetch the next page, set After to the LastID of the previous page.
+type ConversationItemsListOptions struct {
+	After   string
+	Include []ResponseInclude
+	Limit   int
+	Order   string
+}
+
+// CreateConversation creates a conversation.
+func (c *Client) CreateConversation(
+	ctx context.Context,
+	request ConversationRequest,
+) (response Conversation, err error) {
+	if err = validateResponseInputItems(request.Items); err != nil {
+		return response, err
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(conversationsSuffix), withBody(request),
+		withOperation("CreateConversation", request))
+	if err != nil {
+		return response, err
+	}
+	err = c.sendRequest(req, &response)
+	return response, err
+}
+
+// RetrieveConversation retrieves a conversation.
+func (c *Client) RetrieveConversation(ctx context.Context, conversationID string) (response Conversation, err error) {
+	urlSuffix := conversationResourceSuffix(conversationID, "", nil)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("RetrieveConversation", nil))
+	if err != nil {
+		return response, err
+	}
+	err = c.sendRequest(req, &response)
+	return response, err
+}
+
+// ModifyConversation updates the metadata of a conversation.
+func (c *Client) ModifyConversation(
+	ctx context.Context,
+	conversationID string,
+	request ModifyConversationRequest,
+) (response Conversation, err error) {
+	urlSuffix := conversationResourceSuffix(conversationID, "", nil)
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request),
+		withOperation("ModifyConversation", request))
+	if err != nil {
+		return response, err
+	}
+	err = c.sendRequest(req, &response)
+	return response, err
+}
+
+// DeleteConversation deletes a conversation. Its items are not deleted.
+func (c *Client) DeleteConversation(
+	ctx context.Context,
+	conversationID string,
+) (response ConversationDeleteResponse, err error) {
+	urlSuffix := conversationResourceSuffix(conversationID, "", nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(urlSuffix), withOperation("DeleteConversation", nil))
+	if err != nil {
+		return response, err
+	}
+	err = c.sendRequest(req, &response)
+	return response, err
+}
+
+// ListConversationItems lists the items of a conversation.
+func (c *Client) ListConversationItems(
+	ctx context.Context,
+	conversationID string,
+	options ...ConversationItemsListOptions,
+) (response ConversationItemList, err error) {
+	values := url.Values{}
+	if len(options) > 0 {
+		if options[0].After != "" {
+			values.Set("after", options[0].After)
+		}
+		for _, include := range options[0].Include {
+			values.Add("include", string(include))
+		}
+		if options[0].Limit != 0 {
+			values.Set("limit", strconv.Itoa(options[0].Limit))
+		}
+		if options[0].Order != "" {
+			values.Set("order", options[0].Order)
+		}
+	}
+
+	urlSuffix := conversationResourceSuffix(conversationID, "items", values)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("ListConversationItems", nil))
+	if err != nil {
+		return response, err
+	}
+	err = c.sendRequest(req, &response)
+	return response, err
+}
+
+// CreateConversationItems adds up to 20 items to a conversation and returns
+// the added items.
+func (c *Client) CreateConversationItems(
+	ctx context.Context,
+	conversationID string,
+	items []ResponseInputItem,
+	include ...ResponseInclude,
+) (response ConversationItemList, err error) {
+	if err = validateResponseInputItems(items); err != nil {
+		return response, err
+	}
+	request := struct {
+		Items []ResponseInputItem `json:"items"`
+	}{items}
+
+	urlSuffix := conversationResourceSuffix(conversationID, "items", includeValues(include))
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request),
+		withOperation("CreateConversationItems", request))
+	if err != nil {
+		return response, err
+	}
+	err = c.sendRequest(req, &response)
+	return response, err
+}
+
+// RetrieveConversationItem retrieves an item of a conversation.
+func (c *Client) RetrieveConversationItem(
+	ctx context.Context,
+	conversationID string,
+	itemID string,
+	include ...ResponseInclude,
+) (response ConversationItem, err error) {
+	action := "items/" + url.PathEscape(itemID)
+	urlSuffix := conversationResourceSuffix(conversationID, action, includeValues(include))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("RetrieveConversationItem", nil))
+	if err != nil {
+		return response, err
+	}
+	err = c.sendRequest(req, &response)
+	return response, err
+}
+
+// DeleteConversationItem deletes an item of a conversation and returns the
+// conversation.
+func (c *Client) DeleteConversationItem(
+	ctx context.Context,
+	conversationID string,
+	itemID string,
+) (response Conversation, err error) {
+	urlSuffix := conversationResourceSuffix(conversationID, "items/"+url.PathEscape(itemID), nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(urlSuffix), withOperation("DeleteConversationItem", nil))
+	if err != nil {
+		return response, err
+	}
+	err = c.sendRequest(req, &response)
+	return response, err
+}
+
+func conversationResourceSuffix(conversationID, action string, values url.Values) string {
+	suffix := fmt.Sprintf("%s/%s", conversationsSuffix, url.PathEscape(conversationID))
+	if action != "" {
+		suffix += "/" + action
+	}
+	if len(values) != 0 {
+		suffix += "?" + values.Encode()
+	}
+	return suffix
+}
+
+func includeValues(include []ResponseInclude) url.Values {
+	values := url.Values{}
+	for _, item := range include {
+		values.Add("include", string(item))
+	}
+	return values
+}
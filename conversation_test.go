@@ -0,0 +1,143 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const testConversation = `{"id":"conv_1","object":"conversation","created_at":1741900000,"metadata":{"topic":"demo"}}`
+
+func TestConversations(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/conversations", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		items, _ := request["items"].([]any)
+		if r.Method != http.MethodPost || len(items) != 1 || request["metadata"] == nil {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, testConversation)
+	})
+	server.RegisterHandler("/v1/conversations/conv_1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodPost:
+			fmt.Fprint(w, testConversation)
+		case http.MethodDelete:
+			fmt.Fprint(w, `{"id":"conv_1","object":"conversation.deleted","deleted":true}`)
+		}
+	})
+
+	ctx := context.Background()
+	items, err := openai.NewResponseInputBuilder().User("Hello!").Build()
+	checks.NoError(t, err, "Build error")
+	conversation, err := client.CreateConversation(ctx, openai.ConversationRequest{
+		Items:    items,
+		Metadata: map[string]any{"topic": "demo"},
+	})
+	checks.NoError(t, err, "CreateConversation error")
+	if conversation.ID != "conv_1" || conversation.Metadata["topic"] != "demo" {
+		t.Errorf("unexpected conversation: %+v", conversation)
+	}
+
+	_, err = client.RetrieveConversation(ctx, "conv_1")
+	checks.NoError(t, err, "RetrieveConversation error")
+	_, err = client.ModifyConversation(ctx, "conv_1", openai.ModifyConversationRequest{
+		Metadata: map[string]any{"topic": "demo"},
+	})
+	checks.NoError(t, err, "ModifyConversation error")
+	deleted, err := client.DeleteConversation(ctx, "conv_1")
+	checks.NoError(t, err, "DeleteConversation error")
+	if !deleted.Deleted {
+		t.Errorf("unexpected delete response: %+v", deleted)
+	}
+
+	_, err = client.CreateConversation(ctx, openai.ConversationRequest{
+		Items: []openai.ResponseInputItem{openai.ResponseInputMessage{Role: "user"}},
+	})
+	if !errors.Is(err, openai.ErrInvalidResponseInput) {
+		t.Errorf("expected ErrInvalidResponseInput, got %v", err)
+	}
+}
+
+func TestConversationItems(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var queries []string
+	server.RegisterHandler("/v1/conversations/conv_1/items", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.Method+" "+r.URL.RawQuery)
+		if r.Method == http.MethodPost {
+			var request struct {
+				Items []map[string]any `json:"items"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			if len(request.Items) != 1 || request.Items[0]["type"] != "function_call_output" {
+				http.Error(w, "unexpected request", http.StatusBadRequest)
+				return
+			}
+		}
+		fmt.Fprint(w, `{"object":"list","data":[`+
+			`{"type":"message","id":"msg_1","role":"user","status":"completed",`+
+			`"content":[{"type":"input_text","text":"Hello!"}]},`+
+			`{"type":"function_call_output","id":"fco_1","call_id":"call_1","output":"sunny"}],`+
+			`"first_id":"msg_1","last_id":"fco_1","has_more":true}`)
+	})
+	server.RegisterHandler("/v1/conversations/conv_1/items/msg_1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			fmt.Fprint(w, testConversation)
+			return
+		}
+		fmt.Fprint(w, `{"type":"message","id":"msg_1","role":"assistant","status":"completed",`+
+			`"content":[{"type":"output_text","text":"Hi!","annotations":[]}]}`)
+	})
+
+	ctx := context.Background()
+	page, err := client.ListConversationItems(ctx, "conv_1", openai.ConversationItemsListOptions{
+		After: "msg_0",
+		Limit: 2,
+		Order: "asc",
+	})
+	checks.NoError(t, err, "ListConversationItems error")
+	if len(page.Data) != 2 || !page.HasMore || page.LastID != "fco_1" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	if message := page.Data[0].Message; message == nil || message.Role != "user" || message.Content[0].Text != "Hello!" {
+		t.Errorf("unexpected message item: %+v", page.Data[0])
+	}
+	if item := page.Data[1]; item.Type != "function_call_output" || len(item.Raw) == 0 {
+		t.Errorf("unexpected function call output item: %+v", item)
+	}
+
+	items, err := openai.NewResponseInputBuilder().FunctionCallOutput("call_1", "sunny").Build()
+	checks.NoError(t, err, "Build error")
+	_, err = client.CreateConversationItems(ctx, "conv_1", items,
+		openai.ResponseIncludeMessageOutputTextLogprobs)
+	checks.NoError(t, err, "CreateConversationItems error")
+
+	item, err := client.RetrieveConversationItem(ctx, "conv_1", "msg_1")
+	checks.NoError(t, err, "RetrieveConversationItem error")
+	if item.Message == nil || item.Message.Content[0].Text != "Hi!" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	conversation, err := client.DeleteConversationItem(ctx, "conv_1", "msg_1")
+	checks.NoError(t, err, "DeleteConversationItem error")
+	if conversation.ID != "conv_1" {
+		t.Errorf("unexpected conversation: %+v", conversation)
+	}
+
+	want := []string{
+		"GET after=msg_0&limit=2&order=asc",
+		"POST include=message.output_text.logprobs",
+	}
+	if fmt.Sprint(queries) != fmt.Sprint(want) {
+		t.Errorf("unexpected item requests: %q", queries)
+	}
+}
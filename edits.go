@@ -43,6 +43,7 @@ func (c *Client) Edits(ctx context.Context, request EditsRequest) (response Edit
 		http.MethodPost,
 		c.fullURL("/edits", withModel(fmt.Sprint(request.Model))),
 		withBody(request),
+		withOperation("Edits", request),
 	)
 	if err != nil {
 		return
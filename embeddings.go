@@ -276,6 +276,7 @@ func (c *Client) CreateEmbeddings(
 		c.fullURL("/embeddings", withModel(string(baseReq.Model))),
 		withBody(body),           // Main request body.
 		withExtraBody(extraBody), // Merge ExtraBody fields.
+		withOperation("CreateEmbeddings", conv),
 	)
 	if err != nil {
 		return
@@ -78,7 +78,8 @@ func (c *Client) CreateFileBytes(ctx context.Context, request FileBytesRequest)
 	}
 
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL("/files"),
-		withBody(&b), withContentType(builder.FormDataContentType()))
+		withBody(&b), withContentType(builder.FormDataContentType()),
+		withOperation("CreateFileBytes", request))
 	if err != nil {
 		return
 	}
@@ -115,7 +116,8 @@ func (c *Client) CreateFile(ctx context.Context, request FileRequest) (file File
 	}
 
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL("/files"),
-		withBody(&b), withContentType(builder.FormDataContentType()))
+		withBody(&b), withContentType(builder.FormDataContentType()),
+		withOperation("CreateFile", request))
 	if err != nil {
 		return
 	}
@@ -126,7 +128,7 @@ func (c *Client) CreateFile(ctx context.Context, request FileRequest) (file File
 
 // DeleteFile deletes an existing file.
 func (c *Client) DeleteFile(ctx context.Context, fileID string) (err error) {
-	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL("/files/"+fileID))
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL("/files/"+fileID), withOperation("DeleteFile", nil))
 	if err != nil {
 		return
 	}
@@ -138,7 +140,7 @@ func (c *Client) DeleteFile(ctx context.Context, fileID string) (err error) {
 // ListFiles Lists the currently available files,
 // and provides basic information about each file such as the file name and purpose.
 func (c *Client) ListFiles(ctx context.Context) (files FilesList, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL("/files"))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL("/files"), withOperation("ListFiles", nil))
 	if err != nil {
 		return
 	}
@@ -151,7 +153,7 @@ func (c *Client) ListFiles(ctx context.Context) (files FilesList, err error) {
 // such as the file name and purpose.
 func (c *Client) GetFile(ctx context.Context, fileID string) (file File, err error) {
 	urlSuffix := fmt.Sprintf("/files/%s", fileID)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("GetFile", nil))
 	if err != nil {
 		return
 	}
@@ -162,7 +164,7 @@ func (c *Client) GetFile(ctx context.Context, fileID string) (file File, err err
 
 func (c *Client) GetFileContent(ctx context.Context, fileID string) (content RawResponse, err error) {
 	urlSuffix := fmt.Sprintf("/files/%s/content", fileID)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("GetFileContent", nil))
 	if err != nil {
 		return
 	}
@@ -101,7 +101,8 @@ type FineTuneDeleteResponse struct {
 // OpenAI recommends to migrate to the new fine tuning API implemented in fine_tuning_job.go.
 func (c *Client) CreateFineTune(ctx context.Context, request FineTuneRequest) (response FineTune, err error) {
 	urlSuffix := "/fine-tunes"
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request))
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request),
+		withOperation("CreateFineTune", request))
 	if err != nil {
 		return
 	}
@@ -116,7 +117,8 @@ func (c *Client) CreateFineTune(ctx context.Context, request FineTuneRequest) (r
 // This API will be officially deprecated on January 4th, 2024.
 // OpenAI recommends to migrate to the new fine tuning API implemented in fine_tuning_job.go.
 func (c *Client) CancelFineTune(ctx context.Context, fineTuneID string) (response FineTune, err error) {
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL("/fine-tunes/"+fineTuneID+"/cancel")) //nolint:lll //this method is deprecated
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL("/fine-tunes/"+fineTuneID+"/cancel"), //nolint:lll //this method is deprecated
+		withOperation("CancelFineTune", nil))
 	if err != nil {
 		return
 	}
@@ -129,7 +131,7 @@ func (c *Client) CancelFineTune(ctx context.Context, fineTuneID string) (respons
 // This API will be officially deprecated on January 4th, 2024.
 // OpenAI recommends to migrate to the new fine tuning API implemented in fine_tuning_job.go.
 func (c *Client) ListFineTunes(ctx context.Context) (response FineTuneList, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL("/fine-tunes"))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL("/fine-tunes"), withOperation("ListFineTunes", nil))
 	if err != nil {
 		return
 	}
@@ -143,7 +145,7 @@ func (c *Client) ListFineTunes(ctx context.Context) (response FineTuneList, err
 // OpenAI recommends to migrate to the new fine tuning API implemented in fine_tuning_job.go.
 func (c *Client) GetFineTune(ctx context.Context, fineTuneID string) (response FineTune, err error) {
 	urlSuffix := fmt.Sprintf("/fine-tunes/%s", fineTuneID)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("GetFineTune", nil))
 	if err != nil {
 		return
 	}
@@ -156,7 +158,8 @@ func (c *Client) GetFineTune(ctx context.Context, fineTuneID string) (response F
 // This API will be officially deprecated on January 4th, 2024.
 // OpenAI recommends to migrate to the new fine tuning API implemented in fine_tuning_job.go.
 func (c *Client) DeleteFineTune(ctx context.Context, fineTuneID string) (response FineTuneDeleteResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL("/fine-tunes/"+fineTuneID))
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL("/fine-tunes/"+fineTuneID),
+		withOperation("DeleteFineTune", nil))
 	if err != nil {
 		return
 	}
@@ -169,7 +172,8 @@ func (c *Client) DeleteFineTune(ctx context.Context, fineTuneID string) (respons
 // This API will be officially deprecated on January 4th, 2024.
 // OpenAI recommends to migrate to the new fine tuning API implemented in fine_tuning_job.go.
 func (c *Client) ListFineTuneEvents(ctx context.Context, fineTuneID string) (response FineTuneEventList, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL("/fine-tunes/"+fineTuneID+"/events"))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL("/fine-tunes/"+fineTuneID+"/events"),
+		withOperation("ListFineTuneEvents", nil))
 	if err != nil {
 		return
 	}
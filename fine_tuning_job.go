@@ -63,7 +63,8 @@ func (c *Client) CreateFineTuningJob(
 	request FineTuningJobRequest,
 ) (response FineTuningJob, err error) {
 	urlSuffix := "/fine_tuning/jobs"
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request))
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request),
+		withOperation("CreateFineTuningJob", request))
 	if err != nil {
 		return
 	}
@@ -74,7 +75,8 @@ func (c *Client) CreateFineTuningJob(
 
 // CancelFineTuningJob cancel a fine tuning job.
 func (c *Client) CancelFineTuningJob(ctx context.Context, fineTuningJobID string) (response FineTuningJob, err error) {
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL("/fine_tuning/jobs/"+fineTuningJobID+"/cancel"))
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL("/fine_tuning/jobs/"+fineTuningJobID+"/cancel"),
+		withOperation("CancelFineTuningJob", nil))
 	if err != nil {
 		return
 	}
@@ -89,7 +91,7 @@ func (c *Client) RetrieveFineTuningJob(
 	fineTuningJobID string,
 ) (response FineTuningJob, err error) {
 	urlSuffix := fmt.Sprintf("/fine_tuning/jobs/%s", fineTuningJobID)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("RetrieveFineTuningJob", nil))
 	if err != nil {
 		return
 	}
@@ -149,6 +151,7 @@ func (c *Client) ListFineTuningJobEvents(
 		ctx,
 		http.MethodGet,
 		c.fullURL("/fine_tuning/jobs/"+fineTuningJobID+"/events"+encodedValues),
+		withOperation("ListFineTuningJobEvents", nil),
 	)
 	if err != nil {
 		return
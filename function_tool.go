@@ -0,0 +1,139 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+var (
+	// ErrInvalidToolArguments is returned by the handlers of tools created with
+	// NewFunctionTool when the arguments do not match the parameters schema.
+	ErrInvalidToolArguments = errors.New("invalid tool arguments")
+	// ErrToolParametersNotObject is returned by NewFunctionTool when the
+	// arguments type does not reflect into an object schema.
+	ErrToolParametersNotObject = errors.New("tool arguments type must be a struct")
+)
+
+// invalidToolArgumentsError reports arguments that failed validation. It is
+// ErrInvalidToolArguments and unwraps to the validation error, so that both
+// errors.Is and errors.As with jsonschema.ValidationErrors work.
+type invalidToolArgumentsError struct {
+	err error
+}
+
+func (e *invalidToolArgumentsError) Error() string {
+	return ErrInvalidToolArguments.Error() + ": " + e.err.Error()
+}
+
+func (e *invalidToolArgumentsError) Is(target error) bool {
+	return target == ErrInvalidToolArguments
+}
+
+func (e *invalidToolArgumentsError) Unwrap() error {
+	return e.err
+}
+
+// ToolHandler executes a function tool call. It receives the call's arguments
+// as a JSON string and returns the output sent back to the model.
+type ToolHandler func(ctx context.Context, arguments string) (string, error)
+
+// FunctionTool is a function definition together with the handler that
+// executes its calls.
+type FunctionTool struct {
+	Definition FunctionDefinition
+	Handler    ToolHandler
+}
+
+// Tool returns the chat completion tool describing the function.
+func (t FunctionTool) Tool() Tool {
+	definition := t.Definition
+	return Tool{Type: ToolTypeFunction, Function: &definition}
+}
+
+// Call executes a tool call of the function and returns the tool message
+// answering it.
+func (t FunctionTool) Call(ctx context.Context, call ToolCall) (ChatCompletionMessage, error) {
+	if call.Function.Name != t.Definition.Name {
+		return ChatCompletionMessage{}, fmt.Errorf("unknown tool %q", call.Function.Name)
+	}
+	output, err := t.Handler(ctx, call.Function.Arguments)
+	if err != nil {
+		return ChatCompletionMessage{}, err
+	}
+	return ChatCompletionMessage{
+		Role:       ChatMessageRoleTool,
+		Content:    output,
+		Name:       call.Function.Name,
+		ToolCallID: call.ID,
+	}, nil
+}
+
+// NewFunctionTool creates a FunctionTool from a Go function. The parameters
+// schema is reflected from Args, which must be a struct, with
+// jsonschema.GenerateSchemaForType and rewritten with jsonschema.MakeStrict,
+// so that optional fields become nullable. The definition is marked Strict
+// unless the schema cannot be used in strict mode.
+//
+// The handler validates the call's arguments against the schema and decodes
+// them into Args before calling fn. A string Result is sent to the model as
+// is; any other Result is encoded as JSON.
+func NewFunctionTool[Args, Result any](
+	name, description string,
+	fn func(ctx context.Context, args Args) (Result, error),
+) (FunctionTool, error) {
+	var args Args
+	schema, err := jsonschema.GenerateSchemaForType(args)
+	if err != nil {
+		return FunctionTool{}, err
+	}
+	if schema.Type != jsonschema.Object {
+		return FunctionTool{}, ErrToolParametersNotObject
+	}
+	schema, strict := strictSchema(schema)
+
+	handler := func(ctx context.Context, arguments string) (string, error) {
+		if arguments == "" {
+			arguments = "{}"
+		}
+		var args Args
+		if err := schema.Unmarshal(arguments, &args); err != nil {
+			return "", &invalidToolArgumentsError{err: err}
+		}
+		result, err := fn(ctx, args)
+		if err != nil {
+			return "", err
+		}
+		if output, ok := any(result).(string); ok {
+			return output, nil
+		}
+		output, err := json.Marshal(result)
+		if err != nil {
+			return "", err
+		}
+		return string(output), nil
+	}
+
+	return FunctionTool{
+		Definition: FunctionDefinition{
+			Name:        name,
+			Description: description,
+			Strict:      strict,
+			Parameters:  schema,
+		},
+		Handler: handler,
+	}, nil
+}
+
+// strictSchema returns schema rewritten into strict form, or schema itself
+// and false when it cannot be used in strict mode.
+func strictSchema(schema *jsonschema.Definition) (*jsonschema.Definition, bool) {
+	strict, err := jsonschema.MakeStrict(*schema)
+	if err != nil {
+		return schema, false
+	}
+	return &strict, true
+}
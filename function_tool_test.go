@@ -0,0 +1,166 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+type weatherArgs struct {
+	City string `json:"city" description:"City name"`
+	Unit string `json:"unit" enum:"celsius,fahrenheit"`
+}
+
+type weatherResult struct {
+	Temperature int    `json:"temperature"`
+	Unit        string `json:"unit"`
+}
+
+func TestNewFunctionTool(t *testing.T) {
+	tool, err := openai.NewFunctionTool("get_weather", "Get the weather",
+		func(_ context.Context, args weatherArgs) (weatherResult, error) {
+			if args.City != "Paris" {
+				return weatherResult{}, errors.New("unknown city")
+			}
+			return weatherResult{Temperature: 21, Unit: args.Unit}, nil
+		})
+	checks.NoError(t, err, "NewFunctionTool error")
+
+	definition := tool.Tool().Function
+	if definition.Name != "get_weather" || definition.Description != "Get the weather" || !definition.Strict {
+		t.Errorf("unexpected definition: %+v", definition)
+	}
+	parameters, err := json.Marshal(definition.Parameters)
+	checks.NoError(t, err, "marshal error")
+	var schema map[string]any
+	checks.NoError(t, json.Unmarshal(parameters, &schema), "unmarshal error")
+	if schema["type"] != "object" || schema["additionalProperties"] != false || len(schema["required"].([]any)) != 2 {
+		t.Errorf("unexpected parameters: %s", parameters)
+	}
+
+	message, err := tool.Call(context.Background(), openai.ToolCall{
+		ID:       "call_1",
+		Function: openai.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris","unit":"celsius"}`},
+	})
+	checks.NoError(t, err, "Call error")
+	if message.Role != openai.ChatMessageRoleTool || message.ToolCallID != "call_1" ||
+		message.Content != `{"temperature":21,"unit":"celsius"}` {
+		t.Errorf("unexpected tool message: %+v", message)
+	}
+
+	_, err = tool.Handler(context.Background(), `{"city":"Paris","unit":"kelvin"}`)
+	if !errors.Is(err, openai.ErrInvalidToolArguments) {
+		t.Errorf("expected ErrInvalidToolArguments, got %v", err)
+	}
+	var validationErrs jsonschema.ValidationErrors
+	if !errors.As(err, &validationErrs) || len(validationErrs) != 1 || validationErrs[0].InstancePath != "/unit" {
+		t.Errorf("expected the validation errors of unit, got %v", err)
+	}
+	_, err = tool.Handler(context.Background(), `{"city":"Rome","unit":"celsius"}`)
+	if err == nil || err.Error() != "unknown city" {
+		t.Errorf("expected handler error, got %v", err)
+	}
+	_, err = tool.Call(context.Background(), openai.ToolCall{Function: openai.FunctionCall{Name: "other"}})
+	checks.HasError(t, err, "Call should reject other tools")
+}
+
+func TestNewFunctionToolOptionalFields(t *testing.T) {
+	type searchArgs struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit,omitempty" description:"Maximum number of results"`
+	}
+	tool, err := openai.NewFunctionTool("search", "",
+		func(_ context.Context, args searchArgs) (string, error) {
+			return fmt.Sprintf("%s:%d", args.Query, args.Limit), nil
+		})
+	checks.NoError(t, err, "NewFunctionTool error")
+	if !tool.Definition.Strict {
+		t.Error("optional fields should be made nullable for strict mode")
+	}
+	parameters, err := json.Marshal(tool.Definition.Parameters)
+	checks.NoError(t, err, "marshal error")
+	if !strings.Contains(string(parameters), `"required":["query","limit"]`) ||
+		!strings.Contains(string(parameters), `{"description":"Maximum number of results",`+
+			`"anyOf":[{"type":"integer"},{"type":"null"}]}`) {
+		t.Errorf("unexpected parameters: %s", parameters)
+	}
+
+	output, err := tool.Handler(context.Background(), `{"query":"go","limit":null}`)
+	checks.NoError(t, err, "handler error")
+	if output != "go:0" {
+		t.Errorf("string results should be sent as is, got %q", output)
+	}
+}
+
+func TestNewFunctionToolNotStrict(t *testing.T) {
+	type tagArgs struct {
+		Tags map[string]string `json:"tags"`
+	}
+	tool, err := openai.NewFunctionTool("tag", "",
+		func(_ context.Context, args tagArgs) (string, error) {
+			return args.Tags["env"], nil
+		})
+	checks.NoError(t, err, "NewFunctionTool error")
+	if tool.Definition.Strict {
+		t.Error("schemas with free-form objects cannot be strict")
+	}
+	output, err := tool.Handler(context.Background(), `{"tags":{"env":"prod"}}`)
+	checks.NoError(t, err, "handler error")
+	if output != "prod" {
+		t.Errorf("unexpected output %q", output)
+	}
+	_, err = tool.Handler(context.Background(), `{"tags":{"env":1}}`)
+	if !errors.Is(err, openai.ErrInvalidToolArguments) {
+		t.Errorf("expected ErrInvalidToolArguments, got %v", err)
+	}
+}
+
+func TestNewFunctionToolInvalidArgs(t *testing.T) {
+	_, err := openai.NewFunctionTool("f", "", func(context.Context, string) (string, error) {
+		return "", nil
+	})
+	if !errors.Is(err, openai.ErrToolParametersNotObject) {
+		t.Errorf("expected ErrToolParametersNotObject, got %v", err)
+	}
+	_, err = openai.NewFunctionTool("f", "", func(context.Context, struct{ C chan int }) (string, error) {
+		return "", nil
+	})
+	checks.HasError(t, err, "unsupported types should be rejected")
+}
+
+func TestNewFunctionToolWithRunner(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []openai.ChatCompletionRequest
+	registerChatResponses(server, &requests,
+		toolCallResponse(functionCall("call_1", "get_weather", `{"city":"Paris","unit":"celsius"}`)),
+		answerResponse("21 degrees."),
+	)
+
+	tool, err := openai.NewFunctionTool("get_weather", "",
+		func(_ context.Context, args weatherArgs) (weatherResult, error) {
+			return weatherResult{Temperature: 21, Unit: args.Unit}, nil
+		})
+	checks.NoError(t, err, "NewFunctionTool error")
+	result, err := openai.NewChatToolRunner(client, openai.ToolRunnerConfig{}, tool).Run(
+		context.Background(),
+		openai.ChatCompletionRequest{
+			Model:    openai.GPT4o,
+			Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Weather?"}},
+		},
+	)
+	checks.NoError(t, err, "Run error")
+	if result.Messages[2].Content != `{"temperature":21,"unit":"celsius"}` {
+		t.Errorf("unexpected tool output: %+v", result.Messages[2])
+	}
+	if !requests[0].Tools[0].Function.Strict {
+		t.Errorf("strict flag should be sent: %+v", requests[0].Tools[0].Function)
+	}
+}
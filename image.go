@@ -127,6 +127,7 @@ func (c *Client) CreateImage(ctx context.Context, request ImageRequest) (respons
 		http.MethodPost,
 		c.fullURL(urlSuffix, withModel(request.Model)),
 		withBody(request),
+		withOperation("CreateImage", request),
 	)
 	if err != nil {
 		return
@@ -246,6 +247,7 @@ func (c *Client) CreateEditImage(ctx context.Context, request ImageEditRequest)
 		c.fullURL("/images/edits", withModel(request.Model)),
 		withBody(body),
 		withContentType(builder.FormDataContentType()),
+		withOperation("CreateEditImage", request),
 	)
 	if err != nil {
 		return
@@ -304,6 +306,7 @@ func (c *Client) CreateVariImage(ctx context.Context, request ImageVariRequest)
 		c.fullURL("/images/variations", withModel(request.Model)),
 		withBody(body),
 		withContentType(builder.FormDataContentType()),
+		withOperation("CreateVariImage", request),
 	)
 	if err != nil {
 		return
@@ -0,0 +1,413 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Attribute and metric names follow the OpenTelemetry semantic conventions for
+// generative AI clients: https://opentelemetry.io/docs/specs/semconv/gen-ai/
+const (
+	AttrGenAIOperationName         = "gen_ai.operation.name"
+	AttrGenAIProviderName          = "gen_ai.provider.name"
+	AttrGenAIRequestModel          = "gen_ai.request.model"
+	AttrGenAIResponseModel         = "gen_ai.response.model"
+	AttrGenAIResponseID            = "gen_ai.response.id"
+	AttrGenAIResponseFinishReasons = "gen_ai.response.finish_reasons"
+	AttrGenAIUsageInputTokens      = "gen_ai.usage.input_tokens"
+	AttrGenAIUsageOutputTokens     = "gen_ai.usage.output_tokens"
+	AttrGenAITokenType             = "gen_ai.token.type"
+	AttrErrorType                  = "error.type"
+
+	MetricGenAIClientOperationDuration = "gen_ai.client.operation.duration"
+	MetricGenAIClientTokenUsage        = "gen_ai.client.token.usage"
+	MetricGenAIClientTimeToFirstChunk  = "gen_ai.client.operation.time_to_first_chunk"
+
+	GenAITokenTypeInput  = "input"
+	GenAITokenTypeOutput = "output"
+)
+
+// Attribute is a key-value pair attached to a span or a metric measurement.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Span is a single traced API call.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Instrumentation receives a span and metrics for every API call made by the
+// client. It is deliberately small so that it can be backed by OpenTelemetry
+// or any other telemetry library without the client depending on it.
+//
+// Durations are reported in seconds and token counts as plain numbers.
+type Instrumentation interface {
+	StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+	RecordMetric(ctx context.Context, name string, value float64, attrs ...Attribute)
+}
+
+var genAIOperationNames = map[string]string{
+	"CreateChatCompletion":       "chat",
+	"CreateChatCompletionStream": "chat",
+	"CreateCompletion":           "text_completion",
+	"CreateCompletionStream":     "text_completion",
+	"CreateEmbeddings":           "embeddings",
+	"CreateResponse":             "chat",
+	"CreateResponseStream":       "chat",
+}
+
+func genAIProviderName(apiType APIType) string {
+	switch apiType {
+	case APITypeAzure, APITypeAzureAD, APITypeCloudflareAzure:
+		return "azure.ai.openai"
+	case APITypeAnthropic:
+		return "anthropic"
+	case APITypeOpenAI:
+		fallthrough
+	default:
+		return "openai"
+	}
+}
+
+// requestModel returns the Model field of a typed request, if it has one.
+func requestModel(request any) string {
+	v := reflect.ValueOf(request)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	field := v.FieldByName("Model")
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return ""
+	}
+	return field.String()
+}
+
+func errorType(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.HTTPStatusCode != 0 {
+		return strconv.Itoa(apiErr.HTTPStatusCode)
+	}
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) && reqErr.HTTPStatusCode != 0 {
+		return strconv.Itoa(reqErr.HTTPStatusCode)
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// telemetry collects the response details reported on a span.
+type telemetry struct {
+	id            string
+	model         string
+	finishReasons []string
+	inputTokens   int
+	outputTokens  int
+	hasUsage      bool
+}
+
+func (t *telemetry) setUsage(input, output int) {
+	t.inputTokens = input
+	t.outputTokens = output
+	t.hasUsage = true
+}
+
+// observe records the details carried by a response or a stream chunk.
+//
+//nolint:gocognit // flat type switch over the response types
+func (t *telemetry) observe(response any) {
+	switch r := response.(type) {
+	case *ChatCompletionResponse:
+		t.id, t.model = r.ID, r.Model
+		for _, choice := range r.Choices {
+			t.finishReasons = append(t.finishReasons, string(choice.FinishReason))
+		}
+		t.setUsage(r.Usage.PromptTokens, r.Usage.CompletionTokens)
+	case *ChatCompletionStreamResponse:
+		t.id, t.model = r.ID, r.Model
+		for _, choice := range r.Choices {
+			if choice.FinishReason != "" {
+				t.finishReasons = append(t.finishReasons, string(choice.FinishReason))
+			}
+		}
+		if r.Usage != nil {
+			t.setUsage(r.Usage.PromptTokens, r.Usage.CompletionTokens)
+		}
+	case *CompletionResponse:
+		t.id, t.model = r.ID, r.Model
+		for _, choice := range r.Choices {
+			if choice.FinishReason != "" {
+				t.finishReasons = append(t.finishReasons, choice.FinishReason)
+			}
+		}
+		if r.Usage != nil {
+			t.setUsage(r.Usage.PromptTokens, r.Usage.CompletionTokens)
+		}
+	case *EmbeddingResponse:
+		t.model = string(r.Model)
+		t.setUsage(r.Usage.PromptTokens, 0)
+	case *CreateResponseResponse:
+		t.id, t.model = r.ID, r.Model
+		if r.Status != "" {
+			reason := string(r.Status)
+			if r.IncompleteDetails != nil && r.IncompleteDetails.Reason != "" {
+				reason = r.IncompleteDetails.Reason
+			}
+			t.finishReasons = []string{reason}
+		}
+		if r.Usage != nil {
+			t.setUsage(r.Usage.InputTokens, r.Usage.OutputTokens)
+		}
+	case *ResponseStreamEvent:
+		if r.Response != nil {
+			t.observe(r.Response)
+		}
+	}
+}
+
+func (t *telemetry) attributes() []Attribute {
+	var attrs []Attribute
+	if t.id != "" {
+		attrs = append(attrs, Attribute{AttrGenAIResponseID, t.id})
+	}
+	if t.model != "" {
+		attrs = append(attrs, Attribute{AttrGenAIResponseModel, t.model})
+	}
+	if len(t.finishReasons) > 0 {
+		attrs = append(attrs, Attribute{AttrGenAIResponseFinishReasons, t.finishReasons})
+	}
+	if t.hasUsage {
+		attrs = append(attrs,
+			Attribute{AttrGenAIUsageInputTokens, t.inputTokens},
+			Attribute{AttrGenAIUsageOutputTokens, t.outputTokens},
+		)
+	}
+	return attrs
+}
+
+// withAttributes returns a copy of attrs extended with extra.
+func withAttributes(attrs []Attribute, extra ...Attribute) []Attribute {
+	out := make([]Attribute, 0, len(attrs)+len(extra))
+	out = append(out, attrs...)
+	return append(out, extra...)
+}
+
+// instrumentedCall tracks a single API call from start to end.
+type instrumentedCall struct {
+	instrumentation Instrumentation
+	ctx             context.Context
+	span            Span
+	attrs           []Attribute
+	start           time.Time
+
+	mu         sync.Mutex
+	telemetry  telemetry
+	firstChunk bool
+	ended      bool
+}
+
+func (call *instrumentedCall) observeChunk(chunk any) {
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	if !call.firstChunk {
+		call.firstChunk = true
+		call.instrumentation.RecordMetric(call.ctx, MetricGenAIClientTimeToFirstChunk,
+			time.Since(call.start).Seconds(), call.attrs...)
+	}
+	call.telemetry.observe(chunk)
+}
+
+func (call *instrumentedCall) end(err error) {
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	if call.ended {
+		return
+	}
+	call.ended = true
+
+	var errAttrs []Attribute
+	if err != nil {
+		errAttrs = []Attribute{{AttrErrorType, errorType(err)}}
+		call.span.RecordError(err)
+	}
+	call.span.SetAttributes(append(errAttrs, call.telemetry.attributes()...)...)
+
+	attrs := withAttributes(call.attrs, errAttrs...)
+	call.instrumentation.RecordMetric(call.ctx, MetricGenAIClientOperationDuration,
+		time.Since(call.start).Seconds(), attrs...)
+	if call.telemetry.hasUsage {
+		call.instrumentation.RecordMetric(call.ctx, MetricGenAIClientTokenUsage, float64(call.telemetry.inputTokens),
+			withAttributes(attrs, Attribute{AttrGenAITokenType, GenAITokenTypeInput})...)
+		call.instrumentation.RecordMetric(call.ctx, MetricGenAIClientTokenUsage, float64(call.telemetry.outputTokens),
+			withAttributes(attrs, Attribute{AttrGenAITokenType, GenAITokenTypeOutput})...)
+	}
+	call.span.End()
+}
+
+// instrumentationMiddleware opens a span around every operation. Unary calls
+// are finished when the handler returns; streaming calls are finished when
+// the stream ends or is closed.
+func (c *Client) instrumentationMiddleware(next Handler) Handler {
+	return func(ctx context.Context, op *Operation) error {
+		operationName, ok := genAIOperationNames[op.Name]
+		if !ok {
+			operationName = op.Name
+		}
+		model := requestModel(op.Request)
+		attrs := []Attribute{
+			{AttrGenAIOperationName, operationName},
+			{AttrGenAIProviderName, genAIProviderName(c.config.APIType)},
+		}
+		spanName := operationName
+		if model != "" {
+			attrs = append(attrs, Attribute{AttrGenAIRequestModel, model})
+			spanName += " " + model
+		}
+
+		call := &instrumentedCall{
+			instrumentation: c.config.Instrumentation,
+			attrs:           attrs,
+			start:           time.Now(),
+		}
+		ctx, call.span = call.instrumentation.StartSpan(ctx, spanName, attrs...)
+		call.ctx = ctx
+
+		err := next(ctx, op)
+		if err == nil && op.Stream && !op.sent {
+			// A middleware short-circuited the stream, which has no response to
+			// observe; sendRequestStream fails it the same way.
+			err = ErrMiddlewareNoResponse
+		}
+		if err == nil && op.Stream {
+			op.streamObserver = call
+			return nil
+		}
+		if err == nil {
+			call.telemetry.observe(op.Response)
+		}
+		call.end(err)
+		return err
+	}
+}
+
+// InMemoryInstrumentation is an Instrumentation that keeps every span and
+// metric in memory. It is intended for tests.
+type InMemoryInstrumentation struct {
+	mu      sync.Mutex
+	spans   []*RecordedSpan
+	metrics []RecordedMetric
+}
+
+// RecordedSpan is a span captured by InMemoryInstrumentation.
+type RecordedSpan struct {
+	Name       string
+	Attributes map[string]any
+	Errors     []error
+	Ended      bool
+}
+
+// RecordedMetric is a metric measurement captured by InMemoryInstrumentation.
+type RecordedMetric struct {
+	Name       string
+	Value      float64
+	Attributes map[string]any
+}
+
+// NewInMemoryInstrumentation creates an empty in-memory recorder.
+func NewInMemoryInstrumentation() *InMemoryInstrumentation {
+	return &InMemoryInstrumentation{}
+}
+
+func attributeMap(attrs []Attribute) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		m[attr.Key] = attr.Value
+	}
+	return m
+}
+
+func (r *InMemoryInstrumentation) StartSpan(
+	ctx context.Context,
+	name string,
+	attrs ...Attribute,
+) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	span := &RecordedSpan{Name: name, Attributes: attributeMap(attrs)}
+	r.spans = append(r.spans, span)
+	return ctx, &inMemorySpan{recorder: r, span: span}
+}
+
+func (r *InMemoryInstrumentation) RecordMetric(_ context.Context, name string, value float64, attrs ...Attribute) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, RecordedMetric{Name: name, Value: value, Attributes: attributeMap(attrs)})
+}
+
+// Spans returns a snapshot of the recorded spans in the order they were started.
+func (r *InMemoryInstrumentation) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	spans := make([]RecordedSpan, 0, len(r.spans))
+	for _, span := range r.spans {
+		s := *span
+		s.Attributes = make(map[string]any, len(span.Attributes))
+		for k, v := range span.Attributes {
+			s.Attributes[k] = v
+		}
+		s.Errors = append([]error(nil), span.Errors...)
+		spans = append(spans, s)
+	}
+	return spans
+}
+
+// Metrics returns the recorded metric measurements with the given name.
+func (r *InMemoryInstrumentation) Metrics(name string) []RecordedMetric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var metrics []RecordedMetric
+	for _, metric := range r.metrics {
+		if metric.Name == name {
+			metrics = append(metrics, metric)
+		}
+	}
+	return metrics
+}
+
+type inMemorySpan struct {
+	recorder *InMemoryInstrumentation
+	span     *RecordedSpan
+}
+
+func (s *inMemorySpan) SetAttributes(attrs ...Attribute) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	for _, attr := range attrs {
+		s.span.Attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *inMemorySpan) RecordError(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.span.Errors = append(s.span.Errors, err)
+}
+
+func (s *inMemorySpan) End() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.span.Ended = true
+}
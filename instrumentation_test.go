@@ -0,0 +1,232 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func setupInstrumentedTestServer() (
+	*openai.Client,
+	*openai.InMemoryInstrumentation,
+	*test.ServerTest,
+	func(),
+) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	recorder := openai.NewInMemoryInstrumentation()
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.Instrumentation = recorder
+	return openai.NewClientWithConfig(config), recorder, server, ts.Close
+}
+
+func tokenUsage(metrics []openai.RecordedMetric) map[string]float64 {
+	usage := make(map[string]float64)
+	for _, metric := range metrics {
+		tokenType, _ := metric.Attributes[openai.AttrGenAITokenType].(string)
+		usage[tokenType] = metric.Value
+	}
+	return usage
+}
+
+func TestInstrumentationChatCompletion(t *testing.T) {
+	client, recorder, server, teardown := setupInstrumentedTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"chatcmpl-1","model":"gpt-4o-mini-2024-07-18",
+			"choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],
+			"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`)
+	})
+
+	_, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	spans := recorder.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "chat gpt-4o-mini" || !span.Ended || len(span.Errors) != 0 {
+		t.Fatalf("unexpected span: %+v", span)
+	}
+	expected := map[string]any{
+		openai.AttrGenAIOperationName:     "chat",
+		openai.AttrGenAIProviderName:      "openai",
+		openai.AttrGenAIRequestModel:      openai.GPT4oMini,
+		openai.AttrGenAIResponseModel:     "gpt-4o-mini-2024-07-18",
+		openai.AttrGenAIResponseID:        "chatcmpl-1",
+		openai.AttrGenAIUsageInputTokens:  12,
+		openai.AttrGenAIUsageOutputTokens: 3,
+	}
+	for key, value := range expected {
+		if span.Attributes[key] != value {
+			t.Errorf("attribute %s = %v, expected %v", key, span.Attributes[key], value)
+		}
+	}
+	if reasons := fmt.Sprint(span.Attributes[openai.AttrGenAIResponseFinishReasons]); reasons != "[stop]" {
+		t.Errorf("unexpected finish reasons: %s", reasons)
+	}
+
+	if n := len(recorder.Metrics(openai.MetricGenAIClientOperationDuration)); n != 1 {
+		t.Errorf("expected 1 duration measurement, got %d", n)
+	}
+	usage := tokenUsage(recorder.Metrics(openai.MetricGenAIClientTokenUsage))
+	if usage[openai.GenAITokenTypeInput] != 12 || usage[openai.GenAITokenTypeOutput] != 3 {
+		t.Errorf("unexpected token usage: %v", usage)
+	}
+}
+
+func TestInstrumentationRecordsErrors(t *testing.T) {
+	client, recorder, server, teardown := setupInstrumentedTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"error":{"message":"boom","type":"server_error"}}`)
+	})
+
+	_, err := client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
+		Input: []string{"hello"},
+		Model: openai.SmallEmbedding3,
+	})
+	checks.HasError(t, err, "CreateEmbeddings should fail")
+
+	spans := recorder.Spans()
+	if len(spans) != 1 || spans[0].Name != "embeddings text-embedding-3-small" {
+		t.Fatalf("unexpected spans: %+v", spans)
+	}
+	if len(spans[0].Errors) != 1 || spans[0].Attributes[openai.AttrErrorType] != "500" {
+		t.Fatalf("span should record the error: %+v", spans[0])
+	}
+	durations := recorder.Metrics(openai.MetricGenAIClientOperationDuration)
+	if len(durations) != 1 || durations[0].Attributes[openai.AttrErrorType] != "500" {
+		t.Fatalf("unexpected duration measurements: %+v", durations)
+	}
+	if n := len(recorder.Metrics(openai.MetricGenAIClientTokenUsage)); n != 0 {
+		t.Fatalf("failed calls should not report token usage, got %d measurements", n)
+	}
+}
+
+func TestInstrumentationChatCompletionStream(t *testing.T) {
+	client, recorder, server, teardown := setupInstrumentedTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","model":"gpt-4o-mini","choices":[{"index":0,"delta":{"content":"hi"}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","model":"gpt-4o-mini","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","model":"gpt-4o-mini","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":1}}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:         openai.GPT4oMini,
+		Messages:      []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+		StreamOptions: &openai.StreamOptions{IncludeUsage: true},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	if spans := recorder.Spans(); len(spans) != 1 || spans[0].Ended {
+		t.Fatalf("stream span should stay open until the stream ends: %+v", spans)
+	}
+	for {
+		_, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		checks.NoError(t, err, "Recv error")
+	}
+
+	span := recorder.Spans()[0]
+	if !span.Ended || span.Attributes[openai.AttrGenAIUsageOutputTokens] != 1 {
+		t.Fatalf("unexpected span: %+v", span)
+	}
+	if reasons := fmt.Sprint(span.Attributes[openai.AttrGenAIResponseFinishReasons]); reasons != "[stop]" {
+		t.Errorf("unexpected finish reasons: %s", reasons)
+	}
+	if n := len(recorder.Metrics(openai.MetricGenAIClientTimeToFirstChunk)); n != 1 {
+		t.Errorf("expected 1 time to first chunk measurement, got %d", n)
+	}
+	usage := tokenUsage(recorder.Metrics(openai.MetricGenAIClientTokenUsage))
+	if usage[openai.GenAITokenTypeInput] != 5 || usage[openai.GenAITokenTypeOutput] != 1 {
+		t.Errorf("unexpected token usage: %v", usage)
+	}
+
+	stream.Close()
+	if n := len(recorder.Metrics(openai.MetricGenAIClientOperationDuration)); n != 1 {
+		t.Errorf("span should be ended once, got %d duration measurements", n)
+	}
+}
+
+func TestInstrumentationResponseStream(t *testing.T) {
+	client, recorder, server, teardown := setupInstrumentedTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"type":"response.output_text.delta","delta":"hi"}`+"\n\n")
+		fmt.Fprint(w, `data: {"type":"response.completed","response":{"id":"resp_1","model":"gpt-4o",`+
+			`"status":"completed","usage":{"input_tokens":7,"output_tokens":2}}}`+"\n\n")
+	})
+
+	stream, err := client.CreateResponseStream(context.Background(), openai.CreateResponseRequest{
+		Model: openai.GPT4o,
+		Input: "hello",
+	})
+	checks.NoError(t, err, "CreateResponseStream error")
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+	}
+	stream.Close()
+
+	span := recorder.Spans()[0]
+	if span.Name != "chat gpt-4o" || span.Attributes[openai.AttrGenAIResponseID] != "resp_1" {
+		t.Fatalf("unexpected span: %+v", span)
+	}
+	usage := tokenUsage(recorder.Metrics(openai.MetricGenAIClientTokenUsage))
+	if usage[openai.GenAITokenTypeInput] != 7 || usage[openai.GenAITokenTypeOutput] != 2 {
+		t.Errorf("unexpected token usage: %v", usage)
+	}
+}
+
+func TestInstrumentationShortCircuitedStream(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+	recorder := openai.NewInMemoryInstrumentation()
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.Instrumentation = recorder
+	config.Middlewares = []openai.Middleware{func(openai.Handler) openai.Handler {
+		return func(context.Context, *openai.Operation) error { return nil }
+	}}
+	client := openai.NewClientWithConfig(config)
+
+	_, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+	})
+	checks.ErrorIs(t, err, openai.ErrMiddlewareNoResponse, "CreateChatCompletionStream should fail")
+
+	spans := recorder.Spans()
+	if len(spans) != 1 || !spans[0].Ended || len(spans[0].Errors) != 1 {
+		t.Fatalf("the span should end with the error: %+v", spans)
+	}
+	if n := len(recorder.Metrics(openai.MetricGenAIClientOperationDuration)); n != 1 {
+		t.Fatalf("expected 1 duration measurement, got %d", n)
+	}
+}
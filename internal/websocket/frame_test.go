@@ -0,0 +1,62 @@
+package websocket //nolint:testpackage // testing frames that Conn does not write
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"testing"
+)
+
+func pipeConns() (client, server *Conn) {
+	clientEnd, serverEnd := net.Pipe()
+	return newConn(clientEnd, bufio.NewReader(clientEnd), true), newConn(serverEnd, bufio.NewReader(serverEnd), false)
+}
+
+func TestReadMessageFragmentsAndControlFrames(t *testing.T) {
+	client, server := pipeConns()
+	defer client.closeConn()
+	defer server.closeConn()
+
+	go func() {
+		// A text message in two fragments, with a ping in between.
+		_, _ = server.rwc.Write([]byte{byte(TextMessage), 3, 'H', 'e', 'l'})
+		_ = server.writeFrame(opPing, []byte("hi"))
+		_, _ = server.rwc.Write([]byte{finalBit | opContinuation, 2, 'l', 'o'})
+	}()
+
+	pong := make(chan []byte, 1)
+	go func() {
+		// The server reads the pong, which ReadMessage skips, then the close.
+		_, _, payload, _ := server.readFrame()
+		pong <- payload
+		_, _, _ = server.ReadMessage()
+	}()
+
+	messageType, message, err := client.ReadMessage()
+	if err != nil || messageType != TextMessage || string(message) != "Hello" {
+		t.Fatalf("unexpected message %q of type %d: %v", message, messageType, err)
+	}
+	if payload := <-pong; string(payload) != "hi" {
+		t.Errorf("expected the ping to be answered, got %q", payload)
+	}
+}
+
+func TestReadMessageProtocolErrors(t *testing.T) {
+	for name, frame := range map[string][]byte{
+		"unexpected continuation": {finalBit | opContinuation, 0},
+		"reserved bits":           {finalBit | reservedBits | byte(TextMessage), 0},
+		"masked server frame":     {finalBit | byte(TextMessage), maskBit},
+		"fragmented control":      {opPing, 0},
+		"unknown opcode":          {finalBit | 3, 0},
+	} {
+		client, server := pipeConns()
+		go func(frame []byte) {
+			_, _ = server.rwc.Write(frame)
+			_, _, _ = server.ReadMessage()
+		}(frame)
+		if _, _, err := client.ReadMessage(); !errors.Is(err, ErrProtocol) {
+			t.Errorf("%s: expected ErrProtocol, got %v", name, err)
+		}
+		server.closeConn()
+	}
+}
@@ -0,0 +1,421 @@
+// Package websocket implements the subset of RFC 6455 needed by the Realtime
+// API: the opening handshake over net/http, and unextended text, binary and
+// control frames for both the client and the server side.
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // mandated by RFC 6455 for the handshake
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// MessageType is the opcode of a data message.
+type MessageType int
+
+const (
+	TextMessage   MessageType = 1
+	BinaryMessage MessageType = 2
+)
+
+const (
+	opContinuation = 0
+	opClose        = 8
+	opPing         = 9
+	opPong         = 10
+
+	finalBit     = 0x80
+	reservedBits = 0x70
+	opcodeBits   = 0x0f
+	maskBit      = 0x80
+
+	maxControlPayload = 125
+	payloadLength16   = 126
+	payloadLength64   = 127
+	maxFrameHeader    = 14
+	keyLength         = 16
+	closeCodeLength   = 2
+	maxHandshakeBody  = 64 << 10
+	// maxMessageSize bounds the memory used by a single message.
+	maxMessageSize = 32 << 20
+
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// Close status codes.
+const (
+	CloseNormalClosure   = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseNoStatus        = 1005
+	CloseAbnormalClosure = 1006
+	CloseMessageTooBig   = 1009
+)
+
+var (
+	// ErrClosed is returned when writing to a connection that was closed.
+	ErrClosed = errors.New("websocket: connection closed")
+	// ErrProtocol is returned when the peer violates the protocol.
+	ErrProtocol = errors.New("websocket: protocol error")
+	// ErrMessageTooBig is returned for messages larger than 32 MiB.
+	ErrMessageTooBig = errors.New("websocket: message too big")
+)
+
+// CloseError is returned by ReadMessage once the peer has closed the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket: closed with status %d", e.Code)
+	}
+	return fmt.Sprintf("websocket: closed with status %d: %s", e.Code, e.Reason)
+}
+
+// HandshakeError is returned by Dial when the server does not switch protocols.
+type HandshakeError struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *HandshakeError) Error() string {
+	return fmt.Sprintf("websocket: handshake failed with status %d", e.StatusCode)
+}
+
+// Conn is a WebSocket connection. ReadMessage must not be called
+// concurrently; writes may be made from any goroutine.
+type Conn struct {
+	rwc    io.ReadWriteCloser
+	reader *bufio.Reader
+	client bool
+
+	writeMu   sync.Mutex
+	closeSent bool
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Dial opens a connection to rawURL, which may use the ws, wss, http or https
+// scheme, through client. ctx only bounds the handshake.
+func Dial(ctx context.Context, client *http.Client, rawURL string, header http.Header) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("websocket: unsupported URL scheme %q", u.Scheme)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	nonce := make([]byte, keyLength)
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxHandshakeBody))
+		return nil, &HandshakeError{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}
+	}
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: the HTTP client does not support protocol upgrades", ErrProtocol)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") ||
+		resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		rwc.Close()
+		return nil, fmt.Errorf("%w: invalid handshake response", ErrProtocol)
+	}
+	return newConn(rwc, bufio.NewReader(rwc), true), nil
+}
+
+// Accept completes the handshake of a WebSocket request on the server side.
+func Accept(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || key == "" || !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: not a websocket handshake", ErrProtocol)
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("%w: the response writer cannot be hijacked", ErrProtocol)
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	_, err = fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	return newConn(netConn, rw.Reader, false), nil
+}
+
+func newConn(rwc io.ReadWriteCloser, reader *bufio.Reader, client bool) *Conn {
+	return &Conn{rwc: rwc, reader: reader, client: client}
+}
+
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID)) //nolint:gosec // mandated by RFC 6455 for the handshake
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// ReadMessage returns the next data message. Pings are answered while
+// reading. Once the peer closes the connection, the close is acknowledged
+// and a *CloseError is returned.
+func (c *Conn) ReadMessage() (MessageType, []byte, error) {
+	var (
+		messageType MessageType
+		message     []byte
+	)
+	for {
+		final, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err = c.writeFrame(opPong, payload); err != nil && !errors.Is(err, ErrClosed) {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			closeErr := parseClose(payload)
+			code := closeErr.Code
+			if code == CloseNoStatus {
+				code = CloseNormalClosure
+			}
+			_ = c.writeClose(code, "")
+			c.closeConn()
+			return 0, nil, closeErr
+		case opContinuation:
+			if messageType == 0 {
+				return 0, nil, c.fail(CloseProtocolError, fmt.Errorf("%w: unexpected continuation frame", ErrProtocol))
+			}
+		case int(TextMessage), int(BinaryMessage):
+			if messageType != 0 {
+				return 0, nil, c.fail(CloseProtocolError, fmt.Errorf("%w: interleaved data frames", ErrProtocol))
+			}
+			messageType = MessageType(opcode)
+		default:
+			return 0, nil, c.fail(CloseProtocolError, fmt.Errorf("%w: unknown opcode %d", ErrProtocol, opcode))
+		}
+
+		if len(message)+len(payload) > maxMessageSize {
+			return 0, nil, c.fail(CloseMessageTooBig, ErrMessageTooBig)
+		}
+		message = append(message, payload...)
+		if final {
+			return messageType, message, nil
+		}
+	}
+}
+
+func (c *Conn) readFrame() (final bool, opcode int, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, c.readErr(err)
+	}
+	final = header[0]&finalBit != 0
+	opcode = int(header[0] & opcodeBits)
+	masked := header[1]&maskBit != 0
+	if header[0]&reservedBits != 0 || masked == c.client {
+		return false, 0, nil, c.fail(CloseProtocolError, fmt.Errorf("%w: invalid frame header", ErrProtocol))
+	}
+
+	length := uint64(header[1] &^ maskBit)
+	switch length {
+	case payloadLength16:
+		var extended [2]byte
+		if _, err = io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, c.readErr(err)
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case payloadLength64:
+		var extended [8]byte
+		if _, err = io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, c.readErr(err)
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if opcode >= opClose && (length > maxControlPayload || !final) {
+		return false, 0, nil, c.fail(CloseProtocolError, fmt.Errorf("%w: invalid control frame", ErrProtocol))
+	}
+	if length > maxMessageSize {
+		return false, 0, nil, c.fail(CloseMessageTooBig, ErrMessageTooBig)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.reader, mask[:]); err != nil {
+			return false, 0, nil, c.readErr(err)
+		}
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, c.readErr(err)
+	}
+	if masked {
+		maskBytes(mask, payload)
+	}
+	return final, opcode, payload, nil
+}
+
+// readErr reports a connection that ended without a close frame as an
+// abnormal closure.
+func (c *Conn) readErr(err error) error {
+	c.closeConn()
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &CloseError{Code: CloseAbnormalClosure}
+	}
+	return err
+}
+
+// fail closes the connection with code after a protocol violation.
+func (c *Conn) fail(code int, err error) error {
+	_ = c.writeClose(code, "")
+	c.closeConn()
+	return err
+}
+
+func parseClose(payload []byte) *CloseError {
+	if len(payload) < closeCodeLength {
+		return &CloseError{Code: CloseNoStatus}
+	}
+	return &CloseError{Code: int(binary.BigEndian.Uint16(payload)), Reason: string(payload[closeCodeLength:])}
+}
+
+// WriteMessage sends a data message in a single frame.
+func (c *Conn) WriteMessage(messageType MessageType, data []byte) error {
+	return c.writeFrame(int(messageType), data)
+}
+
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closeSent {
+		return ErrClosed
+	}
+	if opcode == opClose {
+		c.closeSent = true
+	}
+
+	frame := make([]byte, 0, maxFrameHeader+len(payload))
+	frame = append(frame, finalBit|byte(opcode))
+	var lengthBits byte
+	if c.client {
+		lengthBits = maskBit
+	}
+	switch length := len(payload); {
+	case length <= maxControlPayload:
+		frame = append(frame, lengthBits|byte(length))
+	case length <= math.MaxUint16:
+		var extended [2]byte
+		binary.BigEndian.PutUint16(extended[:], uint16(length))
+		frame = append(append(frame, lengthBits|payloadLength16), extended[:]...)
+	default:
+		var extended [8]byte
+		binary.BigEndian.PutUint64(extended[:], uint64(length))
+		frame = append(append(frame, lengthBits|payloadLength64), extended[:]...)
+	}
+
+	if !c.client {
+		_, err := c.rwc.Write(append(frame, payload...))
+		return err
+	}
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	start := len(frame)
+	frame = append(frame, payload...)
+	maskBytes(mask, frame[start:])
+	_, err := c.rwc.Write(frame)
+	return err
+}
+
+func (c *Conn) writeClose(code int, reason string) error {
+	payload := make([]byte, closeCodeLength, closeCodeLength+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	if len(payload) > maxControlPayload {
+		payload = payload[:maxControlPayload]
+	}
+	return c.writeFrame(opClose, payload)
+}
+
+// Close sends a close frame with the given status and closes the
+// connection without waiting for the peer to acknowledge it.
+func (c *Conn) Close(code int, reason string) error {
+	err := c.writeClose(code, reason)
+	if errors.Is(err, ErrClosed) {
+		err = nil
+	}
+	c.closeConn()
+	if err == nil {
+		err = c.closeErr
+	}
+	return err
+}
+
+func (c *Conn) closeConn() {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.rwc.Close()
+	})
+}
+
+func maskBytes(mask [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= mask[i%len(mask)]
+	}
+}
@@ -0,0 +1,99 @@
+package websocket_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/internal/websocket"
+)
+
+// echoServer echoes every message until the client closes the connection.
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close(websocket.CloseNormalClosure, "")
+		for {
+			messageType, message, readErr := conn.ReadMessage()
+			if readErr != nil {
+				return
+			}
+			if string(message) == "close" {
+				_ = conn.Close(websocket.CloseGoingAway, "bye")
+				return
+			}
+			if writeErr := conn.WriteMessage(messageType, message); writeErr != nil {
+				return
+			}
+		}
+	}))
+}
+
+func TestEcho(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, err := websocket.Dial(context.Background(), nil, url, nil)
+	checks.NoError(t, err, "Dial error")
+	defer conn.Close(websocket.CloseNormalClosure, "")
+
+	// The sizes cover the 7-bit, 16-bit and 64-bit payload length encodings.
+	for _, size := range []int{0, 5, 125, 126, 70000} {
+		message := bytes.Repeat([]byte("a"), size)
+		checks.NoError(t, conn.WriteMessage(websocket.BinaryMessage, message), "WriteMessage error")
+		messageType, echoed, readErr := conn.ReadMessage()
+		checks.NoError(t, readErr, "ReadMessage error")
+		if messageType != websocket.BinaryMessage || !bytes.Equal(echoed, message) {
+			t.Errorf("unexpected echo of %d bytes: type %d, %d bytes", size, messageType, len(echoed))
+		}
+	}
+
+	checks.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("close")), "WriteMessage error")
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseGoingAway || closeErr.Reason != "bye" {
+		t.Errorf("expected a close error, got %v", err)
+	}
+	if err = conn.WriteMessage(websocket.TextMessage, []byte("late")); !errors.Is(err, websocket.ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestDialHandshakeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":{"message":"Unauthorized"}}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := websocket.Dial(context.Background(), server.Client(), server.URL, nil)
+	var handshakeErr *websocket.HandshakeError
+	if !errors.As(err, &handshakeErr) || handshakeErr.StatusCode != http.StatusUnauthorized ||
+		!strings.Contains(string(handshakeErr.Body), "Unauthorized") {
+		t.Errorf("expected a handshake error, got %v", err)
+	}
+
+	_, err = websocket.Dial(context.Background(), nil, "ftp://example.com", nil)
+	checks.HasError(t, err, "unsupported schemes should be rejected")
+}
+
+func TestAcceptRejectsPlainRequests(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	checks.NoError(t, err, "Get error")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
+	}
+}
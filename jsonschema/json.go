@@ -2,14 +2,23 @@
 // (nested) struct. This struct can be used with the chat completion "function call" feature.
 // For more complicated schemas, it is recommended to use a dedicated JSON schema library
 // and/or pass in the schema in []byte format.
+//
+// Validate, ValidateDetailed and VerifySchemaAndUnmarshal follow JSON Schema
+// semantics: a Definition without a Type constrains values only through
+// Const, Ref and the AnyOf, OneOf and AllOf combinators, so the empty schema
+// accepts any value, and AdditionalProperties set to false rejects properties
+// not listed in Properties. Earlier versions rejected every value of a schema
+// without a Type and ignored AdditionalProperties.
 package jsonschema
 
 import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type DataType string
@@ -48,6 +57,37 @@ type Definition struct {
 	AdditionalProperties any `json:"additionalProperties,omitempty"`
 	// Whether the schema is nullable or not.
 	Nullable bool `json:"nullable,omitempty"`
+	// AnyOf requires the value to match at least one of the given schemas.
+	AnyOf []Definition `json:"anyOf,omitempty"`
+	// OneOf requires the value to match exactly one of the given schemas.
+	OneOf []Definition `json:"oneOf,omitempty"`
+	// AllOf requires the value to match all of the given schemas.
+	AllOf []Definition `json:"allOf,omitempty"`
+	// Const restricts the value to a single constant.
+	Const any `json:"const,omitempty"`
+	// Default is the default value. It is an annotation and is not validated.
+	Default any `json:"default,omitempty"`
+
+	// Pattern is a regular expression a string value must match.
+	Pattern string `json:"pattern,omitempty"`
+	// Format is the semantic format of a string value, e.g. "date-time" or "email".
+	Format string `json:"format,omitempty"`
+	// MinLength and MaxLength bound the number of characters of a string value.
+	MinLength *int `json:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty"`
+
+	// Minimum and Maximum are inclusive bounds of a numeric value.
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+	// ExclusiveMinimum and ExclusiveMaximum are exclusive bounds of a numeric value.
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	// MultipleOf requires a numeric value to be a multiple of the given number.
+	MultipleOf *float64 `json:"multipleOf,omitempty"`
+
+	// MinItems and MaxItems bound the number of items of an array value.
+	MinItems *int `json:"minItems,omitempty"`
+	MaxItems *int `json:"maxItems,omitempty"`
 
 	// Ref Reference to a definition in $defs or external schema.
 	Ref string `json:"$ref,omitempty"`
@@ -55,6 +95,19 @@ type Definition struct {
 	Defs map[string]Definition `json:"$defs,omitempty"`
 }
 
+// SchemaProvider is implemented by types that supply their own schema to
+// GenerateSchemaForType instead of having it derived from their fields.
+type SchemaProvider interface {
+	JSONSchema() Definition
+}
+
+var (
+	schemaProviderType = reflect.TypeOf((*SchemaProvider)(nil)).Elem()
+	timeType           = reflect.TypeOf(time.Time{})
+	jsonNumberType     = reflect.TypeOf(json.Number(""))
+	rawMessageType     = reflect.TypeOf(json.RawMessage(nil))
+)
+
 func (d *Definition) MarshalJSON() ([]byte, error) {
 	if d.Properties == nil {
 		d.Properties = make(map[string]Definition)
@@ -67,6 +120,14 @@ func (d *Definition) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// subschemas returns the schemas combined by AnyOf, OneOf and AllOf.
+func (d *Definition) subschemas() []Definition {
+	subschemas := make([]Definition, 0, len(d.AnyOf)+len(d.OneOf)+len(d.AllOf))
+	subschemas = append(subschemas, d.AnyOf...)
+	subschemas = append(subschemas, d.OneOf...)
+	return append(subschemas, d.AllOf...)
+}
+
 func (d *Definition) Unmarshal(content string, v any) error {
 	return VerifySchemaAndUnmarshal(*d, []byte(content), v)
 }
@@ -103,6 +164,9 @@ func GenerateSchemaForType(v any) (*Definition, error) {
 }
 
 func reflectSchema(t reflect.Type, defs map[string]Definition) (*Definition, error) {
+	if d, ok := reflectSpecialSchema(t); ok {
+		return d, nil
+	}
 	var d Definition
 	switch t.Kind() {
 	case reflect.String:
@@ -121,6 +185,18 @@ func reflectSchema(t reflect.Type, defs map[string]Definition) (*Definition, err
 			return nil, err
 		}
 		d.Items = items
+	case reflect.Map:
+		if !isMapKeyKind(t.Key().Kind()) {
+			return nil, fmt.Errorf("unsupported map key type: %s", t.Key().Kind().String())
+		}
+		values, err := reflectSchema(t.Elem(), defs)
+		if err != nil {
+			return nil, err
+		}
+		d.Type = Object
+		d.AdditionalProperties = *values
+	case reflect.Interface:
+		// Any value is accepted, which the empty schema expresses.
 	case reflect.Struct:
 		if t.Name() != "" {
 			if _, ok := defs[t.Name()]; !ok {
@@ -147,14 +223,52 @@ func reflectSchema(t reflect.Type, defs map[string]Definition) (*Definition, err
 		}
 		d = *definition
 	case reflect.Invalid, reflect.Uintptr, reflect.Complex64, reflect.Complex128,
-		reflect.Chan, reflect.Func, reflect.Interface, reflect.Map,
-		reflect.UnsafePointer:
+		reflect.Chan, reflect.Func, reflect.UnsafePointer:
 		return nil, fmt.Errorf("unsupported type: %s", t.Kind().String())
 	default:
 	}
 	return &d, nil
 }
 
+// reflectSpecialSchema returns the schema of types that are not described by
+// their kind: schema providers and the standard library types with a custom
+// JSON encoding.
+func reflectSpecialSchema(t reflect.Type) (*Definition, bool) {
+	switch t {
+	case timeType:
+		return &Definition{Type: String, Format: "date-time"}, true
+	case jsonNumberType:
+		return &Definition{Type: Number}, true
+	case rawMessageType:
+		return &Definition{}, true
+	}
+	if t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface {
+		return nil, false
+	}
+	if !t.Implements(schemaProviderType) && !reflect.PointerTo(t).Implements(schemaProviderType) {
+		return nil, false
+	}
+	provider, ok := reflect.New(t).Interface().(SchemaProvider)
+	if !ok {
+		return nil, false
+	}
+	d := provider.JSONSchema()
+	return &d, true
+}
+
+// isMapKeyKind reports whether encoding/json encodes map keys of kind k as
+// object property names.
+func isMapKeyKind(k reflect.Kind) bool {
+	switch k { //nolint:exhaustive // all other kinds are unsupported
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
 func reflectSchemaObject(t reflect.Type, defs map[string]Definition) (*Definition, error) {
 	var d = Definition{
 		Type:                 Object,
@@ -162,22 +276,38 @@ func reflectSchemaObject(t reflect.Type, defs map[string]Definition) (*Definitio
 	}
 	properties := make(map[string]Definition)
 	var requiredFields []string
+	// Fields of the struct itself take precedence over the fields promoted
+	// from embedded structs, like in encoding/json.
+	own := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		if name, ok := fieldName(t.Field(i)); ok && embeddedStruct(t.Field(i)) == nil {
+			own[name] = true
+		}
+	}
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		if !field.IsExported() {
+		if embedded := embeddedStruct(field); embedded != nil && embedded != t {
+			object, err := reflectSchemaObject(embedded, defs)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range sortedKeys(object.Properties) {
+				if _, ok := properties[name]; !ok && !own[name] {
+					properties[name] = object.Properties[name]
+				}
+			}
+			for _, name := range object.Required {
+				if !own[name] && !contains(requiredFields, name) {
+					requiredFields = append(requiredFields, name)
+				}
+			}
 			continue
 		}
-		jsonTag := field.Tag.Get("json")
-		var required = true
-		switch {
-		case jsonTag == "-":
+		jsonTag, ok := fieldName(field)
+		if !ok {
 			continue
-		case jsonTag == "":
-			jsonTag = field.Name
-		case strings.HasSuffix(jsonTag, ",omitempty"):
-			jsonTag = strings.TrimSuffix(jsonTag, ",omitempty")
-			required = false
 		}
+		required := !strings.HasSuffix(field.Tag.Get("json"), ",omitempty")
 
 		item, err := reflectSchema(field.Type, defs)
 		if err != nil {
@@ -196,6 +326,9 @@ func reflectSchemaObject(t reflect.Type, defs map[string]Definition) (*Definitio
 			nullable, _ := strconv.ParseBool(n)
 			item.Nullable = nullable
 		}
+		if err = applyKeywordTags(item, field); err != nil {
+			return nil, err
+		}
 
 		properties[jsonTag] = *item
 
@@ -211,6 +344,137 @@ func reflectSchemaObject(t reflect.Type, defs map[string]Definition) (*Definitio
 	return &d, nil
 }
 
+// fieldName returns the property name of a struct field, or false if the
+// field is not encoded.
+func fieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	jsonTag := field.Tag.Get("json")
+	switch {
+	case jsonTag == "-":
+		return "", false
+	case jsonTag == "":
+		return field.Name, true
+	case strings.HasSuffix(jsonTag, ",omitempty"):
+		return strings.TrimSuffix(jsonTag, ",omitempty"), true
+	}
+	return jsonTag, true
+}
+
+// embeddedStruct returns the struct type of an embedded field whose fields
+// are promoted to the parent object, or nil if the field is not one.
+func embeddedStruct(field reflect.StructField) reflect.Type {
+	if !field.Anonymous || field.Tag.Get("json") != "" {
+		return nil
+	}
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	if _, ok := reflectSpecialSchema(t); ok {
+		return nil
+	}
+	return t
+}
+
+// applyKeywordTags sets the validation keywords given as struct tags, such as
+// `minimum:"0"` or `pattern:"^[a-z]+$"`, on the schema of field.
+func applyKeywordTags(d *Definition, field reflect.StructField) error {
+	for _, tag := range []struct {
+		name  string
+		value **float64
+	}{
+		{"minimum", &d.Minimum},
+		{"maximum", &d.Maximum},
+		{"exclusiveMinimum", &d.ExclusiveMinimum},
+		{"exclusiveMaximum", &d.ExclusiveMaximum},
+		{"multipleOf", &d.MultipleOf},
+	} {
+		if s, ok := field.Tag.Lookup(tag.name); ok {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s tag on field %s: %w", tag.name, field.Name, err)
+			}
+			*tag.value = &f
+		}
+	}
+
+	for _, tag := range []struct {
+		name  string
+		value **int
+	}{
+		{"minLength", &d.MinLength},
+		{"maxLength", &d.MaxLength},
+		{"minItems", &d.MinItems},
+		{"maxItems", &d.MaxItems},
+	} {
+		if s, ok := field.Tag.Lookup(tag.name); ok {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return fmt.Errorf("invalid %s tag on field %s: %w", tag.name, field.Name, err)
+			}
+			*tag.value = &n
+		}
+	}
+
+	if pattern, ok := field.Tag.Lookup("pattern"); ok {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern tag on field %s: %w", field.Name, err)
+		}
+		d.Pattern = pattern
+	}
+	if format, ok := field.Tag.Lookup("format"); ok {
+		d.Format = format
+	}
+
+	for _, tag := range []struct {
+		name  string
+		value *any
+	}{
+		{"default", &d.Default},
+		{"const", &d.Const},
+	} {
+		if s, ok := field.Tag.Lookup(tag.name); ok {
+			v, err := parseTagValue(s, field.Type)
+			if err != nil {
+				return fmt.Errorf("invalid %s tag on field %s: %w", tag.name, field.Name, err)
+			}
+			*tag.value = v
+		}
+	}
+	return nil
+}
+
+// parseTagValue parses a default or const tag as a value of type t. Values of
+// composite types are written as JSON.
+func parseTagValue(s string, t reflect.Type) (any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return s, nil
+	case reflect.Bool:
+		return strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(s, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(s, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(s, 64)
+	default:
+		var v any
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
 func containsRef(def Definition, targetRef string) bool {
 	if def.Ref == targetRef {
 		return true
@@ -231,5 +495,11 @@ func containsRef(def Definition, targetRef string) bool {
 	if def.Items != nil && containsRef(*def.Items, targetRef) {
 		return true
 	}
+
+	for _, sub := range def.subschemas() {
+		if containsRef(sub, targetRef) {
+			return true
+		}
+	}
 	return false
 }
@@ -16,6 +16,8 @@ func TestGenerateSchemaForType_ErrorPaths(t *testing.T) {
 		{"slice", []chan int{}},
 		{"anon struct", anon{}},
 		{"pointer", (*chan int)(nil)},
+		{"map key", map[bool]string{}},
+		{"map value", map[string]chan int{}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,171 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestGenerateSchemaForTypeKeywordTags(t *testing.T) {
+	type Order struct {
+		ID       string   `json:"id" pattern:"^ord_[a-z0-9]+$" minLength:"5" maxLength:"32"`
+		Email    string   `json:"email" format:"email"`
+		Quantity int      `json:"quantity" minimum:"1" maximum:"100" default:"1"`
+		Discount float64  `json:"discount" exclusiveMinimum:"0" exclusiveMaximum:"1" multipleOf:"0.05"`
+		Tags     []string `json:"tags" minItems:"1" maxItems:"3" default:"[\"new\"]"`
+		Kind     string   `json:"kind" const:"order"`
+		Express  bool     `json:"express" default:"false"`
+	}
+	schema, err := jsonschema.GenerateSchemaForType(Order{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"object","properties":{` +
+		`"discount":{"type":"number","exclusiveMinimum":0,"exclusiveMaximum":1,"multipleOf":0.05},` +
+		`"email":{"type":"string","format":"email"},` +
+		`"express":{"type":"boolean","default":false},` +
+		`"id":{"type":"string","pattern":"^ord_[a-z0-9]+$","minLength":5,"maxLength":32},` +
+		`"kind":{"type":"string","const":"order"},` +
+		`"quantity":{"type":"integer","default":1,"minimum":1,"maximum":100},` +
+		`"tags":{"type":"array","items":{"type":"string"},"default":["new"],"minItems":1,"maxItems":3}},` +
+		`"required":["id","email","quantity","discount","tags","kind","express"],"additionalProperties":false}`
+	if string(got) != want {
+		t.Errorf("unexpected schema:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestGenerateSchemaForTypeInvalidKeywordTags(t *testing.T) {
+	for name, v := range map[string]any{
+		"minimum": struct {
+			N int `minimum:"one"`
+		}{},
+		"maxItems": struct {
+			S []int `maxItems:"1.5"`
+		}{},
+		"pattern": struct {
+			S string `pattern:"("`
+		}{},
+		"default": struct {
+			B bool `default:"maybe"`
+		}{},
+		"const": struct {
+			M []int `const:"[1,"`
+		}{},
+	} {
+		if _, err := jsonschema.GenerateSchemaForType(v); err == nil {
+			t.Errorf("expected error for invalid %s tag", name)
+		}
+	}
+}
+
+func TestValidateKeywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema jsonschema.Definition
+		data   any
+		want   bool
+	}{
+		{"minimum", jsonschema.Definition{Type: jsonschema.Integer, Minimum: ptr(1.0)}, 1.0, true},
+		{"below minimum", jsonschema.Definition{Type: jsonschema.Integer, Minimum: ptr(1.0)}, 0.0, false},
+		{"maximum", jsonschema.Definition{Type: jsonschema.Number, Maximum: ptr(1.5)}, 2, false},
+		{"exclusive minimum", jsonschema.Definition{Type: jsonschema.Number, ExclusiveMinimum: ptr(0.0)}, 0.0, false},
+		{"exclusive maximum", jsonschema.Definition{Type: jsonschema.Number, ExclusiveMaximum: ptr(1.0)}, 0.5, true},
+		{"multiple of", jsonschema.Definition{Type: jsonschema.Number, MultipleOf: ptr(0.05)}, 0.15, true},
+		{"not multiple of", jsonschema.Definition{Type: jsonschema.Number, MultipleOf: ptr(0.05)}, 0.17, false},
+		{"min length", jsonschema.Definition{Type: jsonschema.String, MinLength: ptr(3)}, "héé", true},
+		{"max length", jsonschema.Definition{Type: jsonschema.String, MaxLength: ptr(2)}, "abc", false},
+		{"pattern", jsonschema.Definition{Type: jsonschema.String, Pattern: "^a+$"}, "aaa", true},
+		{"pattern mismatch", jsonschema.Definition{Type: jsonschema.String, Pattern: "^a+$"}, "ab", false},
+		{"invalid pattern", jsonschema.Definition{Type: jsonschema.String, Pattern: "("}, "a", false},
+		{"pattern items", jsonschema.Definition{Type: jsonschema.Array, Items: &jsonschema.Definition{
+			Type: jsonschema.String, Pattern: "^a+$",
+		}}, []any{"a", "aa", "ab"}, false},
+		{"pattern in branches", jsonschema.Definition{AnyOf: []jsonschema.Definition{
+			{Type: jsonschema.String, Pattern: "^a+$"}, {Type: jsonschema.String, Pattern: "^b+$"},
+		}}, "bb", true},
+		{"date-time", jsonschema.Definition{Type: jsonschema.String, Format: "date-time"}, "2024-01-02T03:04:05Z", true},
+		{"bad date-time", jsonschema.Definition{Type: jsonschema.String, Format: "date-time"}, "2024-01-02", false},
+		{"date", jsonschema.Definition{Type: jsonschema.String, Format: "date"}, "2024-01-02", true},
+		{"time", jsonschema.Definition{Type: jsonschema.String, Format: "time"}, "03:04:05+01:00", true},
+		{"email", jsonschema.Definition{Type: jsonschema.String, Format: "email"}, "ann@example.com", true},
+		{"bad email", jsonschema.Definition{Type: jsonschema.String, Format: "email"}, "Ann <ann@example.com>", false},
+		{"uuid", jsonschema.Definition{Type: jsonschema.String, Format: "uuid"},
+			"123e4567-e89b-12d3-a456-426614174000", true},
+		{"ipv4", jsonschema.Definition{Type: jsonschema.String, Format: "ipv4"}, "10.0.0.1", true},
+		{"ipv4 given ipv6", jsonschema.Definition{Type: jsonschema.String, Format: "ipv4"}, "::1", false},
+		{"ipv6", jsonschema.Definition{Type: jsonschema.String, Format: "ipv6"}, "::1", true},
+		{"hostname", jsonschema.Definition{Type: jsonschema.String, Format: "hostname"}, "api.openai.com", true},
+		{"bad hostname", jsonschema.Definition{Type: jsonschema.String, Format: "hostname"}, "-bad-.com", false},
+		{"duration", jsonschema.Definition{Type: jsonschema.String, Format: "duration"}, "P1DT2H30M", true},
+		{"bad duration", jsonschema.Definition{Type: jsonschema.String, Format: "duration"}, "PT", false},
+		{"uri", jsonschema.Definition{Type: jsonschema.String, Format: "uri"}, "relative/path", false},
+		{"unknown format", jsonschema.Definition{Type: jsonschema.String, Format: "color"}, "red", true},
+		{"min items", jsonschema.Definition{Type: jsonschema.Array, MinItems: ptr(1)}, []any{}, false},
+		{"max items", jsonschema.Definition{Type: jsonschema.Array, MaxItems: ptr(1),
+			Items: &jsonschema.Definition{Type: jsonschema.Integer}}, []any{1.0}, true},
+		{"const", jsonschema.Definition{Type: jsonschema.String, Const: "order"}, "order", true},
+		{"const mismatch", jsonschema.Definition{Const: 1}, 2.0, false},
+		{"untyped const", jsonschema.Definition{Const: 1}, 1.0, true},
+		{"one of", jsonschema.Definition{OneOf: []jsonschema.Definition{
+			{Type: jsonschema.Integer}, {Type: jsonschema.String},
+		}}, "a", true},
+		{"one of matches twice", jsonschema.Definition{OneOf: []jsonschema.Definition{
+			{Type: jsonschema.Integer}, {Type: jsonschema.Number},
+		}}, 1.0, false},
+		{"all of", jsonschema.Definition{AllOf: []jsonschema.Definition{
+			{Type: jsonschema.Integer, Minimum: ptr(1.0)}, {Type: jsonschema.Integer, Maximum: ptr(3.0)},
+		}}, 4.0, false},
+		{"any of", jsonschema.Definition{AnyOf: []jsonschema.Definition{
+			{Type: jsonschema.String}, {Type: jsonschema.Null},
+		}}, nil, true},
+		{"additional properties", jsonschema.Definition{
+			Type:                 jsonschema.Object,
+			Properties:           map[string]jsonschema.Definition{"a": {Type: jsonschema.String}},
+			AdditionalProperties: false,
+		}, map[string]any{"a": "x", "b": "y"}, false},
+		{"typed additional properties", jsonschema.Definition{
+			Type:                 jsonschema.Object,
+			AdditionalProperties: jsonschema.Definition{Type: jsonschema.Integer},
+		}, map[string]any{"a": 1.0, "b": "y"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonschema.Validate(tt.schema, tt.data); got != tt.want {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckStrictKeywords(t *testing.T) {
+	schema := jsonschema.Definition{
+		Type:                 jsonschema.Object,
+		AdditionalProperties: false,
+		Properties: map[string]jsonschema.Definition{
+			"name": {Type: jsonschema.String, MinLength: ptr(1), Pattern: "^[A-Z]"},
+			"when": {Type: jsonschema.String, Format: "date-time"},
+			"url":  {Type: jsonschema.String, Format: "uri"},
+			"pick": {OneOf: []jsonschema.Definition{{Type: jsonschema.String}, {Type: jsonschema.Integer}}},
+		},
+		Required: []string{"name", "when", "url", "pick"},
+	}
+	var got []string
+	for _, violation := range jsonschema.CheckStrict(schema) {
+		got = append(got, violation.String())
+	}
+	want := `[/properties/name: minLength is not supported /properties/pick: oneOf is not supported ` +
+		`/properties/url: format "uri" is not supported]`
+	if fmt.Sprint(got) != want {
+		t.Errorf("unexpected violations: %s", fmt.Sprint(got))
+	}
+}
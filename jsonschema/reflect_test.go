@@ -0,0 +1,90 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+type money struct {
+	Amount   int64
+	Currency string
+}
+
+func (money) JSONSchema() jsonschema.Definition {
+	return jsonschema.Definition{Type: jsonschema.String, Pattern: `^\d+ [A-Z]{3}$`}
+}
+
+type timestamps struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id" description:"embedded id"`
+}
+
+type metadata struct {
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func TestGenerateSchemaForTypeSpecialTypes(t *testing.T) {
+	type Invoice struct {
+		timestamps
+		*metadata
+		ID      string          `json:"id"`
+		Total   money           `json:"total"`
+		Counts  map[int]int     `json:"counts"`
+		Rate    json.Number     `json:"rate"`
+		Extra   json.RawMessage `json:"extra"`
+		Payload any             `json:"payload"`
+		Due     *time.Time      `json:"due,omitempty"`
+	}
+	schema, err := jsonschema.GenerateSchemaForType(Invoice{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"object","properties":{` +
+		`"counts":{"type":"object","additionalProperties":{"type":"integer"}},` +
+		`"created_at":{"type":"string","format":"date-time"},` +
+		`"due":{"type":"string","format":"date-time"},` +
+		`"extra":{},` +
+		`"id":{"type":"string"},` +
+		`"labels":{"type":"object","additionalProperties":{"type":"string"}},` +
+		`"payload":{},` +
+		`"rate":{"type":"number"},` +
+		`"total":{"type":"string","pattern":"^\\d+ [A-Z]{3}$"}},` +
+		`"required":["created_at","id","total","counts","rate","extra","payload"],"additionalProperties":false}`
+	if string(got) != want {
+		t.Errorf("unexpected schema:\n got: %s\nwant: %s", got, want)
+	}
+
+	data := `{"created_at":"2024-01-02T03:04:05Z","id":"inv_1","total":"100 EUR","counts":{"1":2},` +
+		`"rate":0.5,"extra":[1,"a"],"payload":{"any":true},"labels":{"team":"billing"}}`
+	var invoice struct {
+		ID     string            `json:"id"`
+		Labels map[string]string `json:"labels"`
+	}
+	if err = schema.Unmarshal(data, &invoice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if invoice.ID != "inv_1" || invoice.Labels["team"] != "billing" {
+		t.Errorf("unexpected invoice: %+v", invoice)
+	}
+	if err = schema.Unmarshal(`{"created_at":"yesterday","id":"inv_1","total":"100 EUR","counts":{"1":"2"},`+
+		`"rate":0.5,"extra":null,"payload":null}`, &invoice); err == nil {
+		t.Error("expected validation error")
+	}
+}
+
+func TestGenerateSchemaForTypeSchemaProviderPointer(t *testing.T) {
+	schema, err := jsonschema.GenerateSchemaForType(&money{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Type != jsonschema.String || schema.Pattern == "" {
+		t.Errorf("unexpected schema: %+v", schema)
+	}
+}
@@ -0,0 +1,266 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// StrictMaxProperties is the maximum number of object properties a strict
+	// schema may have in total.
+	StrictMaxProperties = 5000
+	// StrictMaxDepth is the maximum object nesting depth of a strict schema.
+	StrictMaxDepth = 10
+)
+
+// StrictViolation is a part of a schema that OpenAI strict mode rejects.
+type StrictViolation struct {
+	// Path is the JSON pointer of the offending schema, e.g. "/properties/address".
+	Path    string
+	Message string
+}
+
+func (v StrictViolation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// StrictError reports the violations of a schema that cannot be used in strict mode.
+type StrictError struct {
+	Violations []StrictViolation
+}
+
+func (e *StrictError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.String()
+	}
+	return "schema is not strict mode compliant: " + strings.Join(messages, "; ")
+}
+
+// CheckStrict checks a schema against the rules of OpenAI strict mode, used by
+// structured outputs and strict function calling, and returns every violation.
+// It returns nil when the schema is compliant.
+//
+// In strict mode the root must be an object, every object must set
+// additionalProperties to false and list all of its properties as required,
+// arrays must describe their items, and keywords outside the supported subset,
+// such as nullable, oneOf, allOf, default, minLength and maxLength, are
+// rejected. Optional values are expressed as unions
+// with null instead, which MakeStrict does automatically.
+func CheckStrict(schema Definition) []StrictViolation {
+	c := strictChecker{}
+	if schema.Type != Object {
+		c.report("", "root schema must be an object")
+	}
+	c.check(schema, "", 0)
+	if c.properties > StrictMaxProperties {
+		c.report("", fmt.Sprintf("schema has %d properties, more than the maximum of %d",
+			c.properties, StrictMaxProperties))
+	}
+	return c.violations
+}
+
+type strictChecker struct {
+	violations []StrictViolation
+	properties int
+}
+
+func (c *strictChecker) report(path, message string) {
+	c.violations = append(c.violations, StrictViolation{Path: path, Message: message})
+}
+
+func (c *strictChecker) check(def Definition, path string, depth int) {
+	if def.Type == "" && def.Ref == "" && len(def.subschemas()) == 0 {
+		c.report(path, "schema must have a type, a $ref or anyOf")
+	}
+	if def.Nullable {
+		c.report(path, "nullable is not supported, use a union with null instead")
+	}
+	c.checkKeywords(def, path)
+
+	switch def.Type {
+	case Object:
+		depth++
+		c.checkObject(def, path, depth)
+	case Array:
+		if def.Items == nil {
+			c.report(path, "array schema must define items")
+		}
+	default:
+	}
+
+	for _, name := range sortedKeys(def.Properties) {
+		c.properties++
+		c.check(def.Properties[name], path+"/properties/"+escapePointer(name), depth)
+	}
+	if def.Items != nil {
+		c.check(*def.Items, path+"/items", depth)
+	}
+	for i, sub := range def.AnyOf {
+		c.check(sub, fmt.Sprintf("%s/anyOf/%d", path, i), depth)
+	}
+	for i, sub := range def.OneOf {
+		c.check(sub, fmt.Sprintf("%s/oneOf/%d", path, i), depth)
+	}
+	for i, sub := range def.AllOf {
+		c.check(sub, fmt.Sprintf("%s/allOf/%d", path, i), depth)
+	}
+	for _, name := range sortedKeys(def.Defs) {
+		c.check(def.Defs[name], path+"/$defs/"+escapePointer(name), 0)
+	}
+}
+
+// strictFormats are the string formats supported in strict mode.
+var strictFormats = []string{
+	"date-time", "time", "date", "duration", "email", "hostname", "ipv4", "ipv6", "uuid",
+}
+
+func (c *strictChecker) checkKeywords(def Definition, path string) {
+	for _, keyword := range []struct {
+		name string
+		set  bool
+	}{
+		{"oneOf", len(def.OneOf) > 0},
+		{"allOf", len(def.AllOf) > 0},
+		{"default", def.Default != nil},
+		{"minLength", def.MinLength != nil},
+		{"maxLength", def.MaxLength != nil},
+	} {
+		if keyword.set {
+			c.report(path, keyword.name+" is not supported")
+		}
+	}
+	if def.Format != "" && !contains(strictFormats, def.Format) {
+		c.report(path, fmt.Sprintf("format %q is not supported", def.Format))
+	}
+}
+
+func (c *strictChecker) checkObject(def Definition, path string, depth int) {
+	if depth > StrictMaxDepth {
+		c.report(path, fmt.Sprintf("objects are nested deeper than the maximum of %d levels", StrictMaxDepth))
+	}
+	if additional, ok := def.AdditionalProperties.(bool); !ok || additional {
+		c.report(path, "additionalProperties must be false")
+	}
+	for _, name := range sortedKeys(def.Properties) {
+		if !contains(def.Required, name) {
+			c.report(path+"/properties/"+escapePointer(name), "property must be required")
+		}
+	}
+	for _, name := range def.Required {
+		if _, ok := def.Properties[name]; !ok {
+			c.report(path+"/required", fmt.Sprintf("required property %q is not defined", name))
+		}
+	}
+}
+
+// MakeStrict returns a copy of schema rewritten into strict form: objects get
+// additionalProperties false, optional properties become required properties
+// whose values may also be null, and nullable schemas become unions with null.
+// Violations that cannot be rewritten, such as objects with typed additional
+// properties, are returned as a *StrictError.
+func MakeStrict(schema Definition) (Definition, error) {
+	strict := makeStrict(schema)
+	if violations := CheckStrict(strict); len(violations) > 0 {
+		return strict, &StrictError{Violations: violations}
+	}
+	return strict, nil
+}
+
+func makeStrict(def Definition) Definition {
+	if def.Properties != nil {
+		properties := make(map[string]Definition, len(def.Properties))
+		for name, property := range def.Properties {
+			properties[name] = makeStrict(property)
+		}
+		def.Properties = properties
+	}
+	if def.Items != nil {
+		items := makeStrict(*def.Items)
+		def.Items = &items
+	}
+	def.AnyOf = makeStrictAll(def.AnyOf)
+	def.OneOf = makeStrictAll(def.OneOf)
+	def.AllOf = makeStrictAll(def.AllOf)
+	if def.Defs != nil {
+		defs := make(map[string]Definition, len(def.Defs))
+		for name, sub := range def.Defs {
+			defs[name] = makeStrict(sub)
+		}
+		def.Defs = defs
+	}
+
+	if def.Type == Object {
+		if def.AdditionalProperties == nil {
+			def.AdditionalProperties = false
+		}
+		required := append([]string(nil), def.Required...)
+		for _, name := range sortedKeys(def.Properties) {
+			if !contains(required, name) {
+				required = append(required, name)
+				def.Properties[name] = nullable(def.Properties[name])
+			}
+		}
+		def.Required = required
+	}
+	if def.Nullable {
+		def.Nullable = false
+		def = nullable(def)
+	}
+	return def
+}
+
+func makeStrictAll(defs []Definition) []Definition {
+	if defs == nil {
+		return nil
+	}
+	strict := make([]Definition, len(defs))
+	for i, def := range defs {
+		strict[i] = makeStrict(def)
+	}
+	return strict
+}
+
+// nullable returns a schema that also accepts null. The description stays on
+// the outer schema, where it describes the value as a whole.
+func nullable(def Definition) Definition {
+	if acceptsNull(def) {
+		return def
+	}
+	description := def.Description
+	def.Description = ""
+	return Definition{
+		Description: description,
+		AnyOf:       []Definition{def, {Type: Null}},
+	}
+}
+
+func acceptsNull(def Definition) bool {
+	if def.Type == Null {
+		return true
+	}
+	for _, sub := range def.AnyOf {
+		if acceptsNull(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]Definition) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapePointer escapes a reference token of a JSON pointer (RFC 6901).
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
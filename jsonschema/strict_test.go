@@ -0,0 +1,159 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+func TestCheckStrict(t *testing.T) {
+	schema := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"name": {Type: jsonschema.String},
+			"tags": {Type: jsonschema.Array},
+			"address": {
+				Type:                 jsonschema.Object,
+				AdditionalProperties: false,
+				Properties: map[string]jsonschema.Definition{
+					"a/b": {Type: jsonschema.String, Nullable: true},
+				},
+				Required: []string{"a/b", "zip"},
+			},
+			"any": {},
+		},
+		Required: []string{"name", "tags", "address", "any"},
+	}
+
+	var got []string
+	for _, violation := range jsonschema.CheckStrict(schema) {
+		got = append(got, violation.String())
+	}
+	want := []string{
+		"additionalProperties must be false",
+		`/properties/address/required: required property "zip" is not defined`,
+		"/properties/address/properties/a~1b: nullable is not supported, use a union with null instead",
+		"/properties/any: schema must have a type, a $ref or anyOf",
+		"/properties/tags: array schema must define items",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected violations:\n got: %q\nwant: %q", got, want)
+	}
+
+	strict := jsonschema.Definition{
+		Type:                 jsonschema.Object,
+		AdditionalProperties: false,
+		Properties:           map[string]jsonschema.Definition{"name": {Type: jsonschema.String}},
+		Required:             []string{"name"},
+	}
+	if violations := jsonschema.CheckStrict(strict); violations != nil {
+		t.Errorf("expected no violations, got %v", violations)
+	}
+	if violations := jsonschema.CheckStrict(jsonschema.Definition{Type: jsonschema.String}); len(violations) != 1 ||
+		violations[0].Message != "root schema must be an object" {
+		t.Errorf("unexpected root violations: %v", violations)
+	}
+}
+
+func TestCheckStrictLimits(t *testing.T) {
+	schema := jsonschema.Definition{Type: jsonschema.Object, AdditionalProperties: false}
+	for i := 0; i < jsonschema.StrictMaxDepth; i++ {
+		schema = jsonschema.Definition{
+			Type:                 jsonschema.Object,
+			AdditionalProperties: false,
+			Properties:           map[string]jsonschema.Definition{"child": schema},
+			Required:             []string{"child"},
+		}
+	}
+	violations := jsonschema.CheckStrict(schema)
+	if len(violations) != 1 || violations[0].Message != "objects are nested deeper than the maximum of 10 levels" {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+}
+
+func TestMakeStrict(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip,omitempty"`
+	}
+	type Person struct {
+		Name     string   `json:"name" description:"Full name"`
+		Nickname *string  `json:"nickname,omitempty" description:"Optional nickname"`
+		Home     Address  `json:"home,omitempty"`
+		Emails   []string `json:"emails" nullable:"true"`
+	}
+	schema, err := jsonschema.GenerateSchemaForType(Person{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	strict, err := jsonschema.MakeStrict(*schema)
+	if err != nil {
+		t.Fatalf("MakeStrict error: %v", err)
+	}
+
+	if schema.Properties["nickname"].Description != "Optional nickname" || len(schema.Required) != 2 {
+		t.Error("MakeStrict should not modify its argument")
+	}
+	if !reflect.DeepEqual(strict.Required, []string{"name", "emails", "home", "nickname"}) {
+		t.Errorf("unexpected required: %v", strict.Required)
+	}
+	nickname := strict.Properties["nickname"]
+	if nickname.Description != "Optional nickname" || len(nickname.AnyOf) != 2 ||
+		nickname.AnyOf[0].Type != jsonschema.String || nickname.AnyOf[0].Description != "" ||
+		nickname.AnyOf[1].Type != jsonschema.Null {
+		t.Errorf("unexpected nickname schema: %+v", nickname)
+	}
+	emails := strict.Properties["emails"]
+	if emails.Nullable || len(emails.AnyOf) != 2 || emails.AnyOf[0].Type != jsonschema.Array {
+		t.Errorf("nullable should become a union with null: %+v", emails)
+	}
+	if home := strict.Properties["home"]; len(home.AnyOf) != 2 || home.AnyOf[0].Ref != "#/$defs/Address" {
+		t.Errorf("unexpected home schema: %+v", home)
+	}
+	if address := strict.Defs["Address"]; !reflect.DeepEqual(address.Required, []string{"city", "zip"}) {
+		t.Errorf("definitions should be made strict: %+v", address)
+	}
+
+	var person Person
+	err = strict.Unmarshal(`{"name":"Ann","nickname":null,"home":{"city":"Paris","zip":null},"emails":null}`,
+		&person)
+	if err != nil {
+		t.Fatalf("strict schema should accept nulls: %v", err)
+	}
+	if person.Name != "Ann" || person.Nickname != nil || person.Home.City != "Paris" {
+		t.Errorf("unexpected person: %+v", person)
+	}
+	if err = strict.Unmarshal(`{"name":"Ann","home":null,"emails":null}`, &person); err == nil {
+		t.Error("strict schema should require all properties")
+	}
+
+	data, err := json.Marshal(&strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roundTrip jsonschema.Definition
+	if err = json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	if violations := jsonschema.CheckStrict(roundTrip); violations != nil {
+		t.Errorf("unexpected violations after round trip: %v", violations)
+	}
+}
+
+func TestMakeStrictError(t *testing.T) {
+	schema := jsonschema.Definition{
+		Type:                 jsonschema.Object,
+		AdditionalProperties: jsonschema.Definition{Type: jsonschema.String},
+	}
+	_, err := jsonschema.MakeStrict(schema)
+	var strictErr *jsonschema.StrictError
+	if !errors.As(err, &strictErr) || len(strictErr.Violations) != 1 {
+		t.Fatalf("expected StrictError, got %v", err)
+	}
+	if err.Error() != "schema is not strict mode compliant: additionalProperties must be false" {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
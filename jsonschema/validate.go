@@ -2,7 +2,16 @@ package jsonschema
 
 import (
 	"encoding/json"
-	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 func CollectDefs(def Definition) map[string]Definition {
@@ -23,16 +32,27 @@ func collectDefsRecursive(def Definition, result map[string]Definition, prefix s
 	if def.Items != nil {
 		collectDefsRecursive(*def.Items, result, prefix)
 	}
+	for i, sub := range def.AnyOf {
+		collectDefsRecursive(sub, result, fmt.Sprintf("%s/anyOf/%d", prefix, i))
+	}
+	for i, sub := range def.OneOf {
+		collectDefsRecursive(sub, result, fmt.Sprintf("%s/oneOf/%d", prefix, i))
+	}
+	for i, sub := range def.AllOf {
+		collectDefsRecursive(sub, result, fmt.Sprintf("%s/allOf/%d", prefix, i))
+	}
 }
 
+// VerifySchemaAndUnmarshal validates content against schema and decodes it
+// into v. Validation failures are reported as ValidationErrors.
 func VerifySchemaAndUnmarshal(schema Definition, content []byte, v any) error {
 	var data any
 	err := json.Unmarshal(content, &data)
 	if err != nil {
 		return err
 	}
-	if !Validate(schema, data, WithDefs(CollectDefs(schema))) {
-		return errors.New("data validation failed against the provided schema")
+	if errs := ValidateDetailed(schema, data, WithDefs(CollectDefs(schema))); len(errs) > 0 {
+		return fmt.Errorf("data validation failed against the provided schema: %w", errs)
 	}
 	return json.Unmarshal(content, &v)
 }
@@ -49,7 +69,15 @@ func WithDefs(defs map[string]Definition) ValidateOption {
 	}
 }
 
+// Validate reports whether data, as decoded by encoding/json into an any,
+// matches schema.
 func Validate(schema Definition, data any, opts ...ValidateOption) bool {
+	return len(ValidateDetailed(schema, data, opts...)) == 0
+}
+
+// ValidateDetailed validates data like Validate and returns every violation
+// found, or nil when data matches schema.
+func ValidateDetailed(schema Definition, data any, opts ...ValidateOption) ValidationErrors {
 	args := validateArgs{}
 	for _, opt := range opts {
 		opt(&args)
@@ -57,77 +85,493 @@ func Validate(schema Definition, data any, opts ...ValidateOption) bool {
 	if len(opts) == 0 {
 		args.Defs = CollectDefs(schema)
 	}
+	v := validator{defs: args.Defs, patterns: map[string]*regexp.Regexp{}}
+	v.validate(schema, data, "", "")
+	return v.errors
+}
+
+type validator struct {
+	defs map[string]Definition
+	// patterns caches compiled patterns for the duration of a validation,
+	// with nil for patterns that do not compile.
+	patterns map[string]*regexp.Regexp
+	errors   ValidationErrors
+}
+
+func (v *validator) report(err ValidationError) {
+	v.errors = append(v.errors, err)
+}
+
+// check validates data in a separate validator and returns its errors.
+func (v *validator) check(schema Definition, data any, instancePath, schemaPath string) ValidationErrors {
+	sub := validator{defs: v.defs, patterns: v.patterns}
+	sub.validate(schema, data, instancePath, schemaPath)
+	return sub.errors
+}
+
+func (v *validator) validate(schema Definition, data any, instancePath, schemaPath string) {
+	if data == nil && schema.Nullable {
+		return
+	}
+	v.validateCombinators(schema, data, instancePath, schemaPath)
+	if schema.Const != nil && !jsonEqual(schema.Const, data) {
+		v.report(ValidationError{
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath + "/const",
+			Keyword:      "const",
+			Expected:     schema.Const,
+			Actual:       data,
+			Message:      fmt.Sprintf("must be %s", formatValue(schema.Const)),
+		})
+	}
+
+	if schema.Type == "" {
+		// A schema without a type is satisfied by its other keywords, if
+		// any, so the empty schema accepts any value.
+		if schema.Ref != "" {
+			v.validateRef(schema.Ref, data, instancePath, schemaPath)
+		}
+		return
+	}
+	if !hasType(schema.Type, data) {
+		v.report(ValidationError{
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath + "/type",
+			Keyword:      "type",
+			Expected:     schema.Type,
+			Actual:       data,
+			Message:      fmt.Sprintf("must be %s, got %s", typeName(schema.Type), jsonTypeName(data)),
+		})
+		return
+	}
 	switch schema.Type {
 	case Object:
-		return validateObject(schema, data, args.Defs)
+		v.validateObject(schema, data.(map[string]any), instancePath, schemaPath)
 	case Array:
-		return validateArray(schema, data, args.Defs)
+		v.validateArray(schema, data.([]any), instancePath, schemaPath)
 	case String:
-		v, ok := data.(string)
-		if ok && len(schema.Enum) > 0 {
-			return contains(schema.Enum, v)
+		v.validateString(schema, data.(string), instancePath, schemaPath)
+	case Number, Integer:
+		num, _ := toNumber(data)
+		v.validateNumber(schema, num, instancePath, schemaPath)
+	case Boolean, Null:
+	}
+}
+
+func (v *validator) validateRef(ref string, data any, instancePath, schemaPath string) {
+	if def, ok := v.defs[ref]; ok {
+		v.validate(def, data, instancePath, strings.TrimPrefix(ref, "#"))
+		return
+	}
+	v.report(ValidationError{
+		InstancePath: instancePath,
+		SchemaPath:   schemaPath + "/$ref",
+		Keyword:      "$ref",
+		Expected:     ref,
+		Actual:       data,
+		Message:      fmt.Sprintf("schema reference %q cannot be resolved", ref),
+	})
+}
+
+func (v *validator) validateCombinators(schema Definition, data any, instancePath, schemaPath string) {
+	for i, sub := range schema.AllOf {
+		v.validate(sub, data, instancePath, fmt.Sprintf("%s/allOf/%d", schemaPath, i))
+	}
+	if len(schema.AnyOf) > 0 {
+		branches := v.checkBranches(schema.AnyOf, data, instancePath, schemaPath+"/anyOf")
+		if matchCount(branches) == 0 {
+			v.reportBranches(branches, ValidationError{
+				InstancePath: instancePath,
+				SchemaPath:   schemaPath + "/anyOf",
+				Keyword:      "anyOf",
+				Actual:       data,
+				Message:      "must match at least one schema in anyOf",
+			})
 		}
-		return ok
-	case Number: // float64 and int
-		_, ok := data.(float64)
-		if !ok {
-			_, ok = data.(int)
+	}
+	if len(schema.OneOf) > 0 {
+		branches := v.checkBranches(schema.OneOf, data, instancePath, schemaPath+"/oneOf")
+		switch matches := matchCount(branches); matches {
+		case 0:
+			v.reportBranches(branches, ValidationError{
+				InstancePath: instancePath,
+				SchemaPath:   schemaPath + "/oneOf",
+				Keyword:      "oneOf",
+				Actual:       data,
+				Message:      "must match exactly one schema in oneOf, matched none",
+			})
+		case 1:
+		default:
+			v.report(ValidationError{
+				InstancePath: instancePath,
+				SchemaPath:   schemaPath + "/oneOf",
+				Keyword:      "oneOf",
+				Expected:     1,
+				Actual:       data,
+				Message:      fmt.Sprintf("must match exactly one schema in oneOf, matched %d", matches),
+			})
 		}
-		return ok
-	case Boolean:
-		_, ok := data.(bool)
-		return ok
-	case Integer:
-		// Golang unmarshals all numbers as float64, so we need to check if the float64 is an integer
-		if num, ok := data.(float64); ok {
-			return num == float64(int64(num))
+	}
+}
+
+func (v *validator) checkBranches(schemas []Definition, data any, instancePath, schemaPath string) []ValidationErrors {
+	branches := make([]ValidationErrors, len(schemas))
+	for i, sub := range schemas {
+		branches[i] = v.check(sub, data, instancePath, fmt.Sprintf("%s/%d", schemaPath, i))
+	}
+	return branches
+}
+
+func matchCount(branches []ValidationErrors) int {
+	matches := 0
+	for _, errs := range branches {
+		if len(errs) == 0 {
+			matches++
 		}
-		_, ok := data.(int)
-		return ok
-	case Null:
-		return data == nil
-	default:
-		if schema.Ref != "" && args.Defs != nil {
-			if v, ok := args.Defs[schema.Ref]; ok {
-				return Validate(v, data, WithDefs(args.Defs))
+	}
+	return matches
+}
+
+// reportBranches reports why data matches none of the branches. When exactly
+// one branch accepts the type of data, e.g. the object branch of a nullable
+// object, its errors are more useful than the summary.
+func (v *validator) reportBranches(branches []ValidationErrors, summary ValidationError) {
+	var candidate ValidationErrors
+	candidates := 0
+	for _, errs := range branches {
+		if !errs.hasTypeErrorAt(summary.InstancePath) {
+			candidate = errs
+			candidates++
+		}
+	}
+	if candidates == 1 {
+		v.errors = append(v.errors, candidate...)
+		return
+	}
+	var expected []string
+	for _, errs := range branches {
+		for _, err := range errs {
+			if t, ok := err.Expected.(DataType); ok && err.Keyword == "type" && err.InstancePath == summary.InstancePath {
+				expected = append(expected, typeName(t))
 			}
 		}
-		return false
 	}
+	if len(expected) == len(branches) {
+		summary.Expected = expected
+		summary.Message = fmt.Sprintf("must be %s, got %s", strings.Join(expected, " or "), jsonTypeName(summary.Actual))
+	}
+	v.report(summary)
 }
 
-func validateObject(schema Definition, data any, defs map[string]Definition) bool {
-	dataMap, ok := data.(map[string]any)
-	if !ok {
-		return false
+func (v *validator) validateObject(schema Definition, data map[string]any, instancePath, schemaPath string) {
+	for _, name := range schema.Required {
+		if _, exists := data[name]; !exists {
+			v.report(ValidationError{
+				InstancePath: instancePath,
+				SchemaPath:   schemaPath + "/required",
+				Keyword:      "required",
+				Expected:     name,
+				Message:      fmt.Sprintf("missing required property %q", name),
+			})
+		}
 	}
-	for _, field := range schema.Required {
-		if _, exists := dataMap[field]; !exists {
-			return false
+	for _, name := range sortedKeys(schema.Properties) {
+		if value, exists := data[name]; exists {
+			v.validate(schema.Properties[name], value, instancePath+"/"+escapePointer(name),
+				schemaPath+"/properties/"+escapePointer(name))
 		}
 	}
-	for key, valueSchema := range schema.Properties {
-		value, exists := dataMap[key]
-		if exists && !Validate(valueSchema, value, WithDefs(defs)) {
-			return false
-		} else if !exists && contains(schema.Required, key) {
-			return false
+
+	additional, isSchema := additionalPropertiesSchema(schema.AdditionalProperties)
+	names := make([]string, 0, len(data))
+	for name := range data {
+		if _, defined := schema.Properties[name]; !defined {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := instancePath + "/" + escapePointer(name)
+		switch {
+		case isSchema:
+			v.validate(additional, data[name], path, schemaPath+"/additionalProperties")
+		case schema.AdditionalProperties == false:
+			v.report(ValidationError{
+				InstancePath: path,
+				SchemaPath:   schemaPath + "/additionalProperties",
+				Keyword:      "additionalProperties",
+				Actual:       data[name],
+				Message:      fmt.Sprintf("property %q is not allowed", name),
+			})
+		}
+	}
+}
+
+// additionalPropertiesSchema returns the schema of additional properties, if
+// AdditionalProperties holds one rather than a boolean.
+func additionalPropertiesSchema(additionalProperties any) (Definition, bool) {
+	switch v := additionalProperties.(type) {
+	case Definition:
+		return v, true
+	case *Definition:
+		if v != nil {
+			return *v, true
+		}
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return Definition{}, false
 		}
+		var d Definition
+		if json.Unmarshal(data, &d) == nil {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+func (v *validator) validateArray(schema Definition, data []any, instancePath, schemaPath string) {
+	if schema.MinItems != nil && len(data) < *schema.MinItems {
+		v.report(ValidationError{
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath + "/minItems",
+			Keyword:      "minItems",
+			Expected:     *schema.MinItems,
+			Actual:       len(data),
+			Message:      fmt.Sprintf("must have at least %d items, got %d", *schema.MinItems, len(data)),
+		})
+	}
+	if schema.MaxItems != nil && len(data) > *schema.MaxItems {
+		v.report(ValidationError{
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath + "/maxItems",
+			Keyword:      "maxItems",
+			Expected:     *schema.MaxItems,
+			Actual:       len(data),
+			Message:      fmt.Sprintf("must have at most %d items, got %d", *schema.MaxItems, len(data)),
+		})
+	}
+	if schema.Items == nil {
+		return
+	}
+	for i, item := range data {
+		v.validate(*schema.Items, item, fmt.Sprintf("%s/%d", instancePath, i), schemaPath+"/items")
 	}
-	return true
 }
 
-func validateArray(schema Definition, data any, defs map[string]Definition) bool {
-	dataArray, ok := data.([]any)
+func (v *validator) validateString(schema Definition, data, instancePath, schemaPath string) {
+	if len(schema.Enum) > 0 && !contains(schema.Enum, data) {
+		v.report(ValidationError{
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath + "/enum",
+			Keyword:      "enum",
+			Expected:     schema.Enum,
+			Actual:       data,
+			Message:      fmt.Sprintf("must be one of %s, got %q", formatValue(schema.Enum), data),
+		})
+	}
+	length := utf8.RuneCountInString(data)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		v.report(ValidationError{
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath + "/minLength",
+			Keyword:      "minLength",
+			Expected:     *schema.MinLength,
+			Actual:       length,
+			Message:      fmt.Sprintf("must be at least %d characters long, got %d", *schema.MinLength, length),
+		})
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.report(ValidationError{
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath + "/maxLength",
+			Keyword:      "maxLength",
+			Expected:     *schema.MaxLength,
+			Actual:       length,
+			Message:      fmt.Sprintf("must be at most %d characters long, got %d", *schema.MaxLength, length),
+		})
+	}
+	if schema.Pattern != "" {
+		re := v.pattern(schema.Pattern)
+		if re == nil || !re.MatchString(data) {
+			v.report(ValidationError{
+				InstancePath: instancePath,
+				SchemaPath:   schemaPath + "/pattern",
+				Keyword:      "pattern",
+				Expected:     schema.Pattern,
+				Actual:       data,
+				Message:      fmt.Sprintf("must match pattern %q, got %q", schema.Pattern, data),
+			})
+		}
+	}
+	if !validateFormat(schema.Format, data) {
+		v.report(ValidationError{
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath + "/format",
+			Keyword:      "format",
+			Expected:     schema.Format,
+			Actual:       data,
+			Message:      fmt.Sprintf("must be a valid %s, got %q", schema.Format, data),
+		})
+	}
+}
+
+const hostnameLabel = `[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?`
+
+// pattern returns the compiled pattern, or nil if it does not compile.
+func (v *validator) pattern(pattern string) *regexp.Regexp {
+	re, ok := v.patterns[pattern]
 	if !ok {
-		return false
+		re, _ = regexp.Compile(pattern)
+		v.patterns[pattern] = re
 	}
-	for _, item := range dataArray {
-		if !Validate(*schema.Items, item, WithDefs(defs)) {
+	return re
+}
+
+var formatPatterns = map[string]*regexp.Regexp{
+	"uuid":     regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+	"hostname": regexp.MustCompile(`^` + hostnameLabel + `(\.` + hostnameLabel + `)*$`),
+	// An ISO 8601 duration with at least one component.
+	"duration": regexp.MustCompile(`^P(\d+W|\d+Y(\d+M)?(\d+D)?|\d+M(\d+D)?|\d+D)?` +
+		`(T(\d+H(\d+M)?(\d+(\.\d+)?S)?|\d+M(\d+(\.\d+)?S)?|\d+(\.\d+)?S))?$`),
+}
+
+// validateFormat checks the formats supported by structured outputs. Unknown
+// formats are treated as annotations.
+func validateFormat(format, v string) bool {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, v)
+	case "date":
+		_, err = time.Parse("2006-01-02", v)
+	case "time":
+		_, err = time.Parse("15:04:05Z07:00", v)
+		if err != nil {
+			_, err = time.Parse("15:04:05", v)
+		}
+	case "email":
+		var address *mail.Address
+		address, err = mail.ParseAddress(v)
+		if err == nil && address.Address != v {
 			return false
 		}
+	case "ipv4":
+		ip := net.ParseIP(v)
+		return ip != nil && ip.To4() != nil && !strings.Contains(v, ":")
+	case "ipv6":
+		ip := net.ParseIP(v)
+		return ip != nil && strings.Contains(v, ":")
+	case "uri":
+		var u *url.URL
+		u, err = url.Parse(v)
+		if err == nil && u.Scheme == "" {
+			return false
+		}
+	case "uuid", "hostname":
+		return formatPatterns[format].MatchString(v)
+	case "duration":
+		return v != "P" && formatPatterns[format].MatchString(v)
+	}
+	return err == nil
+}
+
+func (v *validator) validateNumber(schema Definition, num float64, instancePath, schemaPath string) {
+	if schema.Type == Integer && num != math.Trunc(num) {
+		v.report(ValidationError{
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath + "/type",
+			Keyword:      "type",
+			Expected:     Integer,
+			Actual:       num,
+			Message:      fmt.Sprintf("must be an integer, got %s", formatValue(num)),
+		})
+	}
+	for _, bound := range []struct {
+		keyword string
+		limit   *float64
+		ok      func(limit float64) bool
+		message string
+	}{
+		{"minimum", schema.Minimum, func(limit float64) bool { return num >= limit }, "must be >= %s, got %s"},
+		{"maximum", schema.Maximum, func(limit float64) bool { return num <= limit }, "must be <= %s, got %s"},
+		{"exclusiveMinimum", schema.ExclusiveMinimum, func(limit float64) bool { return num > limit },
+			"must be > %s, got %s"},
+		{"exclusiveMaximum", schema.ExclusiveMaximum, func(limit float64) bool { return num < limit },
+			"must be < %s, got %s"},
+		{"multipleOf", schema.MultipleOf, func(limit float64) bool { return isMultipleOf(num, limit) },
+			"must be a multiple of %s, got %s"},
+	} {
+		if bound.limit != nil && !bound.ok(*bound.limit) {
+			v.report(ValidationError{
+				InstancePath: instancePath,
+				SchemaPath:   schemaPath + "/" + bound.keyword,
+				Keyword:      bound.keyword,
+				Expected:     *bound.limit,
+				Actual:       num,
+				Message:      fmt.Sprintf(bound.message, formatValue(*bound.limit), formatValue(num)),
+			})
+		}
+	}
+}
+
+func isMultipleOf(num, divisor float64) bool {
+	if divisor == 0 {
+		return true
+	}
+	quotient := num / divisor
+	return math.Abs(quotient-math.Round(quotient)) <= 1e-9
+}
+
+func hasType(t DataType, data any) bool {
+	switch t {
+	case Object:
+		_, ok := data.(map[string]any)
+		return ok
+	case Array:
+		_, ok := data.([]any)
+		return ok
+	case String:
+		_, ok := data.(string)
+		return ok
+	case Number, Integer: // float64 and int
+		_, ok := toNumber(data)
+		return ok
+	case Boolean:
+		_, ok := data.(bool)
+		return ok
+	case Null:
+		return data == nil
+	default:
+		return false
+	}
+}
+
+func toNumber(data any) (float64, bool) {
+	switch v := data.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// jsonEqual reports whether a and b have the same JSON encoding, so that e.g.
+// the int 1 equals the float64 1 decoded from JSON.
+func jsonEqual(a, b any) bool {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false
 	}
-	return true
+	return string(aJSON) == string(bJSON)
 }
 
 func contains[S ~[]E, E comparable](s S, v E) bool {
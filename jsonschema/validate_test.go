@@ -171,6 +171,35 @@ func Test_Validate(t *testing.T) {
 	}
 }
 
+func TestValidateJSONSchemaSemantics(t *testing.T) {
+	// A schema without a type used to reject every value.
+	for _, data := range []any{"abc", 1.5, true, nil, []any{1}, map[string]any{"a": 1}} {
+		if !jsonschema.Validate(jsonschema.Definition{}, data) {
+			t.Errorf("the empty schema should accept %v", data)
+		}
+	}
+	if jsonschema.Validate(jsonschema.Definition{Const: "a"}, "b") {
+		t.Error("a schema without a type should still apply const")
+	}
+
+	// AdditionalProperties false used to be ignored.
+	schema := jsonschema.Definition{
+		Type:                 jsonschema.Object,
+		Properties:           map[string]jsonschema.Definition{"name": {Type: jsonschema.String}},
+		AdditionalProperties: false,
+	}
+	if !jsonschema.Validate(schema, map[string]any{"name": "a"}) {
+		t.Error("listed properties should be accepted")
+	}
+	if jsonschema.Validate(schema, map[string]any{"name": "a", "extra": 1}) {
+		t.Error("AdditionalProperties false should reject unlisted properties")
+	}
+	var v map[string]any
+	if err := jsonschema.VerifySchemaAndUnmarshal(schema, []byte(`{"name":"a","extra":1}`), &v); err == nil {
+		t.Error("VerifySchemaAndUnmarshal should reject unlisted properties")
+	}
+}
+
 func TestUnmarshal(t *testing.T) {
 	type args struct {
 		schema  jsonschema.Definition
@@ -0,0 +1,89 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a value that does not match its schema.
+type ValidationError struct {
+	// InstancePath is the JSON pointer of the offending value, "" for the root.
+	InstancePath string
+	// SchemaPath is the JSON pointer of the failing keyword in the schema.
+	// Keywords reached through a $ref are located in the referenced schema.
+	SchemaPath string
+	// Keyword is the failing keyword, e.g. "required" or "minimum".
+	Keyword string
+	// Expected is the value of the keyword, such as the required property
+	// name or the minimum, when it has one.
+	Expected any
+	// Actual is the offending value, or the measured length or count for the
+	// length and item count keywords.
+	Actual any
+	// Message describes the violation.
+	Message string
+}
+
+// Error formats the violation as the value's location followed by its
+// message, e.g. `/quantity: must be >= 1, got 0`.
+func (e ValidationError) Error() string {
+	path := e.InstancePath
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + e.Message
+}
+
+// ValidationErrors lists every violation found by ValidateDetailed. Its
+// message is suitable for telling a model what to fix in its output.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e ValidationErrors) hasTypeErrorAt(instancePath string) bool {
+	for _, err := range e {
+		if err.Keyword == "type" && err.InstancePath == instancePath {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(t DataType) string {
+	return string(t)
+}
+
+func jsonTypeName(data any) string {
+	switch data.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, int, json.Number:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", data)
+	}
+}
+
+// formatValue formats a value as JSON for messages.
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
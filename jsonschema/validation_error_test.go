@@ -0,0 +1,126 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+func TestValidateDetailed(t *testing.T) {
+	schema := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"quantity": {Type: jsonschema.Integer, Minimum: ptr(1.0)},
+			"status":   {Type: jsonschema.String, Enum: []string{"open", "closed"}},
+			"customer": {Ref: "#/$defs/Customer"},
+			"items":    {Type: jsonschema.Array, Items: &jsonschema.Definition{Type: jsonschema.String}},
+		},
+		Required:             []string{"quantity", "status", "customer", "note"},
+		AdditionalProperties: false,
+		Defs: map[string]jsonschema.Definition{
+			"Customer": {
+				Type:       jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{"name": {Type: jsonschema.String, MinLength: ptr(2)}},
+			},
+		},
+	}
+	data := map[string]any{
+		"quantity": 0.0,
+		"status":   "pending",
+		"customer": map[string]any{"name": "A"},
+		"items":    []any{"a", 1.0},
+		"extra":    true,
+	}
+
+	got := jsonschema.ValidateDetailed(schema, data)
+	want := jsonschema.ValidationErrors{
+		{
+			SchemaPath: "/required", Keyword: "required", Expected: "note",
+			Message: `missing required property "note"`,
+		},
+		{
+			InstancePath: "/customer/name", SchemaPath: "/$defs/Customer/properties/name/minLength",
+			Keyword: "minLength", Expected: 2, Actual: 1, Message: "must be at least 2 characters long, got 1",
+		},
+		{
+			InstancePath: "/items/1", SchemaPath: "/properties/items/items/type",
+			Keyword: "type", Expected: jsonschema.String, Actual: 1.0, Message: "must be string, got number",
+		},
+		{
+			InstancePath: "/quantity", SchemaPath: "/properties/quantity/minimum",
+			Keyword: "minimum", Expected: 1.0, Actual: 0.0, Message: "must be >= 1, got 0",
+		},
+		{
+			InstancePath: "/status", SchemaPath: "/properties/status/enum",
+			Keyword: "enum", Expected: []string{"open", "closed"}, Actual: "pending",
+			Message: `must be one of ["open","closed"], got "pending"`,
+		},
+		{
+			InstancePath: "/extra", SchemaPath: "/additionalProperties",
+			Keyword: "additionalProperties", Actual: true, Message: `property "extra" is not allowed`,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected errors:\n got: %+v\nwant: %+v", got, want)
+	}
+	if jsonschema.Validate(schema, data) {
+		t.Error("Validate should agree with ValidateDetailed")
+	}
+
+	wantMessage := `(root): missing required property "note"; ` +
+		`/customer/name: must be at least 2 characters long, got 1; /items/1: must be string, got number; ` +
+		`/quantity: must be >= 1, got 0; /status: must be one of ["open","closed"], got "pending"; ` +
+		`/extra: property "extra" is not allowed`
+	if got.Error() != wantMessage {
+		t.Errorf("unexpected message:\n got: %s\nwant: %s", got.Error(), wantMessage)
+	}
+}
+
+func TestValidateDetailedUnions(t *testing.T) {
+	address := jsonschema.Definition{
+		Type:       jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{"city": {Type: jsonschema.String}},
+		Required:   []string{"city"},
+	}
+	nullableAddress := jsonschema.Definition{AnyOf: []jsonschema.Definition{address, {Type: jsonschema.Null}}}
+
+	// The errors of the only branch accepting an object are reported.
+	errs := jsonschema.ValidateDetailed(nullableAddress, map[string]any{})
+	if len(errs) != 1 || errs[0].Keyword != "required" || errs[0].SchemaPath != "/anyOf/0/required" {
+		t.Errorf("unexpected errors: %+v", errs)
+	}
+
+	errs = jsonschema.ValidateDetailed(nullableAddress, "Paris")
+	if len(errs) != 1 || errs[0].Keyword != "anyOf" || errs[0].Message != "must be object or null, got string" {
+		t.Errorf("unexpected errors: %+v", errs)
+	}
+
+	oneOf := jsonschema.Definition{OneOf: []jsonschema.Definition{{Type: jsonschema.Number}, {Type: jsonschema.Integer}}}
+	errs = jsonschema.ValidateDetailed(oneOf, 1.0)
+	if len(errs) != 1 || errs[0].Message != "must match exactly one schema in oneOf, matched 2" {
+		t.Errorf("unexpected errors: %+v", errs)
+	}
+	if errs = jsonschema.ValidateDetailed(oneOf, 1.5); errs != nil {
+		t.Errorf("expected no errors, got %+v", errs)
+	}
+}
+
+func TestVerifySchemaAndUnmarshalValidationErrors(t *testing.T) {
+	schema := jsonschema.Definition{
+		Type:       jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{"age": {Type: jsonschema.Integer}},
+	}
+	var v struct {
+		Age int `json:"age"`
+	}
+	err := jsonschema.VerifySchemaAndUnmarshal(schema, []byte(`{"age":1.5}`), &v)
+	var errs jsonschema.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].InstancePath != "/age" {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	if err.Error() != "data validation failed against the provided schema: /age: must be an integer, got 1.5" {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
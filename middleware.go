@@ -0,0 +1,100 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrMiddlewareNoResponse is returned when the middleware chain of a streaming
+// or raw operation returns without error but without a response to hand out,
+// i.e. a middleware short-circuited it without calling next.
+var ErrMiddlewareNoResponse = errors.New("openai: middleware returned without a response")
+
+// Operation describes a typed API call as it passes through the middleware chain.
+type Operation struct {
+	// Name is the name of the client method being called, e.g. "CreateChatCompletion".
+	Name string
+	// Request is the typed request value passed to the client method, or nil for
+	// calls that only take identifiers. The request has already been encoded into
+	// HTTPRequest, so changes to it are not sent to the API.
+	Request any
+	// Response points to the typed response value being populated, e.g.
+	// *ChatCompletionResponse. It is filled in once the next handler returns
+	// without error, and is nil for streaming calls.
+	Response any
+	// Stream is true for calls that return a server-sent event stream.
+	Stream bool
+	// HTTPRequest is the outgoing HTTP request.
+	HTTPRequest *http.Request
+
+	// streamObserver, when set by the handler chain, is notified of every
+	// chunk received on the resulting stream.
+	streamObserver streamObserver
+	// sent is set once the innermost handler has received a response.
+	sent bool
+}
+
+// Handler performs an Operation.
+type Handler func(ctx context.Context, op *Operation) error
+
+// Middleware wraps the handling of every typed API call. A middleware may
+// inspect or annotate the operation, short-circuit it by returning an error or
+// by populating op.Response without calling next, or observe the outcome
+// after next returns.
+//
+// Streaming operations have no op.Response to populate, so they can only be
+// short-circuited by returning an error. Raw operations, whose op.Response is
+// a *RawResponse, must set its ReadCloser when short-circuited. Otherwise the
+// call fails with ErrMiddlewareNoResponse.
+type Middleware func(next Handler) Handler
+
+type operationContextKey struct{}
+
+// withOperation names the API call so that it can be exposed to middleware.
+func withOperation(name string, request any) requestOption {
+	return func(args *requestOptions) {
+		args.operation = &Operation{Name: name, Request: request}
+	}
+}
+
+func operationFromRequest(req *http.Request) *Operation {
+	if op, ok := req.Context().Value(operationContextKey{}).(*Operation); ok {
+		return op
+	}
+	return &Operation{}
+}
+
+// runMiddleware sends req through the configured middleware chain, with send
+// as the innermost handler. The request handed to send carries the context
+// produced by the chain.
+func (c *Client) runMiddleware(
+	req *http.Request,
+	response any,
+	stream bool,
+	send func(req *http.Request) error,
+) error {
+	middlewares := c.config.Middlewares
+	if c.config.Instrumentation != nil {
+		middlewares = append([]Middleware{c.instrumentationMiddleware}, middlewares...)
+	}
+	if len(middlewares) == 0 {
+		return send(req)
+	}
+
+	op := operationFromRequest(req)
+	op.Response = response
+	op.Stream = stream
+	op.HTTPRequest = req
+
+	handler := func(ctx context.Context, op *Operation) error {
+		op.HTTPRequest = op.HTTPRequest.WithContext(ctx)
+		err := send(op.HTTPRequest)
+		op.sent = err == nil
+		return err
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler(req.Context(), op)
+}
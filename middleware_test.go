@@ -0,0 +1,245 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func setupMiddlewareTestServer(middlewares ...openai.Middleware) (*openai.Client, *test.ServerTest, func()) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.Middlewares = middlewares
+	return openai.NewClientWithConfig(config), server, ts.Close
+}
+
+func TestMiddlewareSeesTypedOperation(t *testing.T) {
+	var (
+		order    []string
+		seen     *openai.Operation
+		response *openai.EmbeddingResponse
+	)
+	trace := func(name string) openai.Middleware {
+		return func(next openai.Handler) openai.Handler {
+			return func(ctx context.Context, op *openai.Operation) error {
+				order = append(order, name+":before")
+				err := next(ctx, op)
+				order = append(order, name+":after")
+				return err
+			}
+		}
+	}
+	capture := func(next openai.Handler) openai.Handler {
+		return func(ctx context.Context, op *openai.Operation) error {
+			err := next(ctx, op)
+			seen = op
+			response, _ = op.Response.(*openai.EmbeddingResponse)
+			return err
+		}
+	}
+
+	client, server, teardown := setupMiddlewareTestServer(trace("outer"), trace("inner"), capture)
+	defer teardown()
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"object":"list","model":"text-embedding-3-small","data":[{"embedding":[0.5]}],"usage":{"prompt_tokens":3}}`)
+	})
+
+	request := openai.EmbeddingRequest{Input: []string{"hello"}, Model: openai.SmallEmbedding3}
+	_, err := client.CreateEmbeddings(context.Background(), request)
+	checks.NoError(t, err, "CreateEmbeddings error")
+
+	expectedOrder := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	if fmt.Sprint(order) != fmt.Sprint(expectedOrder) {
+		t.Fatalf("unexpected middleware order: %v", order)
+	}
+	if seen.Name != "CreateEmbeddings" || seen.Stream {
+		t.Fatalf("unexpected operation: %+v", seen)
+	}
+	if req, ok := seen.Request.(openai.EmbeddingRequest); !ok || req.Model != openai.SmallEmbedding3 {
+		t.Fatalf("unexpected request: %#v", seen.Request)
+	}
+	if response == nil || response.Usage.PromptTokens != 3 {
+		t.Fatalf("unexpected response: %#v", seen.Response)
+	}
+	if seen.HTTPRequest == nil || seen.HTTPRequest.URL.Path != "/v1/embeddings" {
+		t.Fatalf("unexpected HTTP request: %v", seen.HTTPRequest)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	errBlocked := errors.New("blocked by policy")
+	cache := func(next openai.Handler) openai.Handler {
+		return func(ctx context.Context, op *openai.Operation) error {
+			switch op.Name {
+			case "GetModel":
+				model, _ := op.Response.(*openai.Model)
+				model.ID = "cached-model"
+				return nil
+			case "DeleteFile":
+				return errBlocked
+			}
+			return next(ctx, op)
+		}
+	}
+
+	client, server, teardown := setupMiddlewareTestServer(cache)
+	defer teardown()
+	server.RegisterHandler("/v1/.*", func(_ http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+
+	model, err := client.GetModel(context.Background(), "gpt-4o")
+	checks.NoError(t, err, "GetModel error")
+	if model.ID != "cached-model" {
+		t.Fatalf("expected cached model, got %q", model.ID)
+	}
+
+	err = client.DeleteFile(context.Background(), "file-1")
+	checks.ErrorIs(t, err, errBlocked, "DeleteFile should be blocked")
+}
+
+func TestMiddlewareObservesErrors(t *testing.T) {
+	var observed error
+	observe := func(next openai.Handler) openai.Handler {
+		return func(ctx context.Context, op *openai.Operation) error {
+			observed = next(ctx, op)
+			return observed
+		}
+	}
+
+	client, server, teardown := setupMiddlewareTestServer(observe)
+	defer teardown()
+	server.RegisterHandler("/v1/models/gpt-4o", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"message":"not found","type":"invalid_request_error"}}`)
+	})
+
+	_, err := client.GetModel(context.Background(), "gpt-4o")
+	var apiErr *openai.APIError
+	if !errors.As(observed, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+		t.Fatalf("middleware should observe the API error, got %v", observed)
+	}
+	checks.ErrorIs(t, err, observed, "client should return the middleware error")
+}
+
+type middlewareContextKey struct{}
+
+func TestMiddlewareStreamOperation(t *testing.T) {
+	var seen *openai.Operation
+	annotate := func(next openai.Handler) openai.Handler {
+		return func(ctx context.Context, op *openai.Operation) error {
+			seen = op
+			return next(context.WithValue(ctx, middlewareContextKey{}, "annotated"), op)
+		}
+	}
+
+	client, server, teardown := setupMiddlewareTestServer(annotate)
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	if seen.Name != "CreateChatCompletionStream" || !seen.Stream || seen.Response != nil {
+		t.Fatalf("unexpected operation: %+v", seen)
+	}
+	if seen.HTTPRequest.Context().Value(middlewareContextKey{}) != "annotated" {
+		t.Fatal("handler should send the request with the middleware context")
+	}
+}
+
+func TestMiddlewareShortCircuitWithoutResponse(t *testing.T) {
+	skip := func(openai.Handler) openai.Handler {
+		return func(context.Context, *openai.Operation) error {
+			return nil
+		}
+	}
+
+	client, server, teardown := setupMiddlewareTestServer(skip)
+	defer teardown()
+	server.RegisterHandler("/v1/.*", func(_ http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+
+	_, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+	})
+	checks.ErrorIs(t, err, openai.ErrMiddlewareNoResponse, "CreateChatCompletionStream should fail")
+
+	_, err = client.GetFileContent(context.Background(), "file-1")
+	checks.ErrorIs(t, err, openai.ErrMiddlewareNoResponse, "GetFileContent should fail")
+}
+
+func TestMiddlewareShortCircuitRaw(t *testing.T) {
+	cache := func(openai.Handler) openai.Handler {
+		return func(_ context.Context, op *openai.Operation) error {
+			raw, _ := op.Response.(*openai.RawResponse)
+			raw.ReadCloser = io.NopCloser(strings.NewReader("cached"))
+			return nil
+		}
+	}
+
+	client, _, teardown := setupMiddlewareTestServer(cache)
+	defer teardown()
+
+	content, err := client.GetFileContent(context.Background(), "file-1")
+	checks.NoError(t, err, "GetFileContent error")
+	defer content.Close()
+	data, err := io.ReadAll(content)
+	checks.NoError(t, err, "ReadAll error")
+	if string(data) != "cached" {
+		t.Fatalf("expected cached content, got %q", data)
+	}
+}
+
+func TestMiddlewareErrorClosesRawBody(t *testing.T) {
+	errRejected := errors.New("rejected after download")
+	var body io.ReadCloser
+	reject := func(next openai.Handler) openai.Handler {
+		return func(ctx context.Context, op *openai.Operation) error {
+			if err := next(ctx, op); err != nil {
+				return err
+			}
+			raw, _ := op.Response.(*openai.RawResponse)
+			body = raw.ReadCloser
+			return errRejected
+		}
+	}
+
+	client, server, teardown := setupMiddlewareTestServer(reject)
+	defer teardown()
+	server.RegisterHandler("/v1/files/file-1/content", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "content")
+	})
+
+	content, err := client.GetFileContent(context.Background(), "file-1")
+	checks.ErrorIs(t, err, errRejected, "GetFileContent should return the middleware error")
+	if content.ReadCloser != nil {
+		t.Error("no body should be returned with the error")
+	}
+	if body == nil {
+		t.Fatal("the middleware should have seen the body")
+	}
+	if _, err = io.ReadAll(body); err == nil {
+		t.Error("the body should be closed")
+	}
+}
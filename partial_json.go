@@ -0,0 +1,343 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrPartialJSONSyntax is wrapped by the errors of PartialJSON once the
+// document received so far cannot be the prefix of any valid JSON value.
+var ErrPartialJSONSyntax = errors.New("partial JSON document is not valid JSON")
+
+// PartialJSON incrementally parses a JSON document that arrives in fragments,
+// such as the content deltas of a streamed structured output or the argument
+// deltas of a streamed function call, and decodes the part received so far
+// into a T.
+//
+// The partial document is completed as far as it is known: open strings,
+// arrays and objects are closed, and an object property whose value has not
+// started yet is left out. Strings and numbers may therefore be incomplete
+// until the document is. Use PartialJSON[any] to get the partial document as
+// map[string]any and []any values.
+//
+//	parser := openai.NewPartialJSON[CityInfo]()
+//	for {
+//		chunk, err := stream.Recv()
+//		...
+//		city, err := parser.Add(chunk.Choices[0].Delta.Content)
+//		...
+//	}
+type PartialJSON[T any] struct {
+	data    []byte
+	scanner partialJSONScanner
+}
+
+// NewPartialJSON creates an empty PartialJSON.
+func NewPartialJSON[T any]() *PartialJSON[T] {
+	return &PartialJSON[T]{}
+}
+
+// Add appends a fragment of the document and returns the value received so
+// far. Once the document is found to be invalid JSON, Add returns an error
+// wrapping ErrPartialJSONSyntax.
+func (p *PartialJSON[T]) Add(fragment string) (T, error) {
+	offset := len(p.data)
+	p.data = append(p.data, fragment...)
+	for i := offset; i < len(p.data) && p.scanner.err == nil; i++ {
+		p.scanner.step(p.data[i], i)
+	}
+	return p.Value()
+}
+
+// Value returns the value received so far. It is the zero T until the
+// document has started.
+func (p *PartialJSON[T]) Value() (T, error) {
+	var v T
+	completed, err := p.scanner.complete(p.data)
+	if err != nil || completed == nil {
+		return v, err
+	}
+	if err = json.Unmarshal(completed, &v); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// Complete reports whether a complete JSON value has been received.
+func (p *PartialJSON[T]) Complete() bool {
+	return p.scanner.err == nil && p.scanner.state == partialJSONAfterValue && len(p.scanner.stack) == 0
+}
+
+// String returns the fragments received so far.
+func (p *PartialJSON[T]) String() string {
+	return string(p.data)
+}
+
+// CompletePartialJSON completes a truncated JSON document the way PartialJSON
+// does, returning "" if the document has not started yet.
+func CompletePartialJSON(s string) (string, error) {
+	var scanner partialJSONScanner
+	data := []byte(s)
+	for i := 0; i < len(data) && scanner.err == nil; i++ {
+		scanner.step(data[i], i)
+	}
+	completed, err := scanner.complete(data)
+	return string(completed), err
+}
+
+type partialJSONState int
+
+const (
+	partialJSONValue partialJSONState = iota
+	partialJSONFirstValue
+	partialJSONFirstKey
+	partialJSONKey
+	partialJSONColon
+	partialJSONAfterValue
+	partialJSONString
+	partialJSONKeyString
+	partialJSONNumber
+	partialJSONLiteral
+)
+
+// partialJSONScanner is a JSON syntax checker fed one byte at a time. It
+// remembers the longest prefix of the input that can be completed into a
+// valid document by closing the open string and containers. The containers
+// only change at such checkpoints, so the stack also describes the prefix.
+type partialJSONScanner struct {
+	state partialJSONState
+	// stack holds the open containers, '{' or '['.
+	stack []byte
+	// checkpoint is the length of the longest completable prefix, and
+	// inString whether it ends inside a string.
+	checkpoint int
+	inString   bool
+	// backslash is set after a backslash in a string, hex counts the
+	// remaining digits of a \u escape and utf8 the remaining continuation
+	// bytes of a character.
+	backslash bool
+	hex       int
+	utf8      int
+	// number is the part of the number being scanned, and literal the rest
+	// of the true, false or null being scanned.
+	number  []byte
+	literal string
+	err     error
+}
+
+//nolint:gocognit,gocyclo // one case per scanner state
+func (s *partialJSONScanner) step(c byte, offset int) {
+	switch s.state {
+	case partialJSONString, partialJSONKeyString:
+		s.stepString(c, offset)
+		return
+	case partialJSONNumber:
+		if isNumberByte(c) {
+			s.number = append(s.number, c)
+			if validNumber(s.number) {
+				s.mark(offset+1, false)
+			}
+			return
+		}
+		if !validNumber(s.number) {
+			s.fail(c, offset)
+			return
+		}
+		s.endValue(offset)
+	case partialJSONLiteral:
+		if c != s.literal[0] {
+			s.fail(c, offset)
+			return
+		}
+		s.literal = s.literal[1:]
+		if s.literal == "" {
+			s.endValue(offset + 1)
+		}
+		return
+	default:
+	}
+
+	if isSpace(c) {
+		return
+	}
+	switch s.state {
+	case partialJSONValue, partialJSONFirstValue:
+		if c == ']' && s.state == partialJSONFirstValue {
+			s.closeContainer(offset)
+			return
+		}
+		s.startValue(c, offset)
+	case partialJSONFirstKey, partialJSONKey:
+		switch {
+		case c == '"':
+			s.state = partialJSONKeyString
+		case c == '}' && s.state == partialJSONFirstKey:
+			s.closeContainer(offset)
+		default:
+			s.fail(c, offset)
+		}
+	case partialJSONColon:
+		if c != ':' {
+			s.fail(c, offset)
+			return
+		}
+		s.state = partialJSONValue
+	case partialJSONAfterValue:
+		s.stepAfterValue(c, offset)
+	case partialJSONString, partialJSONKeyString, partialJSONNumber, partialJSONLiteral:
+	}
+}
+
+func (s *partialJSONScanner) startValue(c byte, offset int) {
+	switch {
+	case c == '{' || c == '[':
+		s.stack = append(s.stack, c)
+		s.state = partialJSONFirstValue
+		if c == '{' {
+			s.state = partialJSONFirstKey
+		}
+		s.mark(offset+1, false)
+	case c == '"':
+		s.state = partialJSONString
+		s.mark(offset+1, true)
+	case c == '-' || (c >= '0' && c <= '9'):
+		s.state = partialJSONNumber
+		s.number = append(s.number[:0], c)
+		if validNumber(s.number) {
+			s.mark(offset+1, false)
+		}
+	case c == 't':
+		s.state, s.literal = partialJSONLiteral, "rue"
+	case c == 'f':
+		s.state, s.literal = partialJSONLiteral, "alse"
+	case c == 'n':
+		s.state, s.literal = partialJSONLiteral, "ull"
+	default:
+		s.fail(c, offset)
+	}
+}
+
+func (s *partialJSONScanner) stepAfterValue(c byte, offset int) {
+	if len(s.stack) == 0 {
+		s.fail(c, offset)
+		return
+	}
+	container := s.stack[len(s.stack)-1]
+	switch {
+	case c == ',' && container == '{':
+		s.state = partialJSONKey
+	case c == ',':
+		s.state = partialJSONValue
+	case (c == '}' && container == '{') || (c == ']' && container == '['):
+		s.closeContainer(offset)
+	default:
+		s.fail(c, offset)
+	}
+}
+
+func (s *partialJSONScanner) stepString(c byte, offset int) {
+	switch {
+	case s.hex > 0:
+		if !isHexByte(c) {
+			s.fail(c, offset)
+			return
+		}
+		s.hex--
+	case s.backslash:
+		switch c {
+		case 'u':
+			s.hex = 4
+		case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		default:
+			s.fail(c, offset)
+			return
+		}
+		s.backslash = false
+	case s.utf8 > 0:
+		s.utf8--
+	case c == '\\':
+		s.backslash = true
+	case c == '"':
+		if s.state == partialJSONKeyString {
+			s.state = partialJSONColon
+			return
+		}
+		s.endValue(offset + 1)
+		return
+	case c < 0x20:
+		s.fail(c, offset)
+		return
+	case c >= 0xf0:
+		s.utf8 = 3
+	case c >= 0xe0:
+		s.utf8 = 2
+	case c >= 0xc0:
+		s.utf8 = 1
+	}
+	if s.state == partialJSONString && !s.backslash && s.hex == 0 && s.utf8 == 0 {
+		s.mark(offset+1, true)
+	}
+}
+
+// endValue records that the value being scanned ends before end.
+func (s *partialJSONScanner) endValue(end int) {
+	s.state = partialJSONAfterValue
+	s.mark(end, false)
+}
+
+func (s *partialJSONScanner) closeContainer(offset int) {
+	s.stack = s.stack[:len(s.stack)-1]
+	s.endValue(offset + 1)
+}
+
+func (s *partialJSONScanner) mark(checkpoint int, inString bool) {
+	s.checkpoint = checkpoint
+	s.inString = inString
+}
+
+func (s *partialJSONScanner) fail(c byte, offset int) {
+	s.err = fmt.Errorf("%w: unexpected %q at offset %d", ErrPartialJSONSyntax, c, offset)
+}
+
+// complete returns the completed prefix of data, or nil if no value has
+// started.
+func (s *partialJSONScanner) complete(data []byte) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.checkpoint == 0 {
+		return nil, nil
+	}
+	completed := make([]byte, s.checkpoint, s.checkpoint+len(s.stack)+1)
+	copy(completed, data)
+	if s.inString {
+		completed = append(completed, '"')
+	}
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		if s.stack[i] == '{' {
+			completed = append(completed, '}')
+		} else {
+			completed = append(completed, ']')
+		}
+	}
+	return completed, nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isHexByte(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isNumberByte(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+}
+
+// validNumber reports whether b is a complete JSON number.
+func validNumber(b []byte) bool {
+	var v json.Number
+	return json.Unmarshal(b, &v) == nil
+}
@@ -0,0 +1,187 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCompletePartialJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"  ", ""},
+		{"{", "{}"},
+		{`{"na`, "{}"},
+		{`{"name"`, "{}"},
+		{`{"name":`, "{}"},
+		{`{"name": "Par`, `{"name": "Par"}`},
+		{`{"name":"Paris","tags":["a",`, `{"name":"Paris","tags":["a"]}`},
+		{`{"a":{"b":[1,{"c":tr`, `{"a":{"b":[1,{}]}}`},
+		{`{"a":true,"b":nul`, `{"a":true}`},
+		{`{"n":-`, `{}`},
+		{`{"n":12`, `{"n":12}`},
+		{`{"n":12.`, `{"n":12}`},
+		{`{"n":1.5e`, `{"n":1.5}`},
+		{`{"n":1.5e3`, `{"n":1.5e3}`},
+		{`{"s":"a\`, `{"s":"a"}`},
+		{`{"s":"a\u00`, `{"s":"a"}`},
+		{`{"s":"aé`, `{"s":"aé"}`},
+		{"{\"s\":\"caf\xc3", `{"s":"caf"}`},
+		{"{\"s\":\"caf\xc3\xa9", `{"s":"café"}`},
+		{`[1,2`, `[1,2]`},
+		{`[`, `[]`},
+		{`"abc`, `"abc"`},
+		{`{"a":[]}`, `{"a":[]}`},
+	}
+	for _, tt := range tests {
+		got, err := openai.CompletePartialJSON(tt.input)
+		checks.NoError(t, err, "CompletePartialJSON error")
+		if got != tt.want {
+			t.Errorf("CompletePartialJSON(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+		if got != "" && !json.Valid([]byte(got)) {
+			t.Errorf("CompletePartialJSON(%q) = %q is not valid JSON", tt.input, got)
+		}
+	}
+}
+
+func TestCompletePartialJSONInvalid(t *testing.T) {
+	for _, input := range []string{`{"a" 1`, `{"a":1,}`, `[1,]`, `{"a":1}}`, `{"n":1-}`, `{"s":"\x"}`, `Sure! {`} {
+		if _, err := openai.CompletePartialJSON(input); !errors.Is(err, openai.ErrPartialJSONSyntax) {
+			t.Errorf("CompletePartialJSON(%q): expected ErrPartialJSONSyntax, got %v", input, err)
+		}
+	}
+}
+
+func TestPartialJSON(t *testing.T) {
+	type city struct {
+		Name      string   `json:"name"`
+		Landmarks []string `json:"landmarks"`
+		Capital   bool     `json:"capital"`
+	}
+	parser := openai.NewPartialJSON[city]()
+	want := []city{
+		{},
+		{Name: "Pa"},
+		{Name: "Paris", Landmarks: []string{}},
+		{Name: "Paris", Landmarks: []string{"Eiffel"}},
+		{Name: "Paris", Landmarks: []string{"Eiffel Tower"}},
+		{Name: "Paris", Landmarks: []string{"Eiffel Tower"}, Capital: true},
+	}
+	for i, fragment := range []string{`{"na`, `me":"Pa`, `ris","landmarks":[`, `"Eiffel`, ` Tower"],"capital":`, `true}`} {
+		got, err := parser.Add(fragment)
+		checks.NoError(t, err, "Add error")
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("after fragment %d: got %+v, want %+v", i, got, want[i])
+		}
+		if parser.Complete() != (i == 5) {
+			t.Errorf("after fragment %d: unexpected Complete() = %v", i, parser.Complete())
+		}
+	}
+	if parser.String() != `{"name":"Paris","landmarks":["Eiffel Tower"],"capital":true}` {
+		t.Errorf("unexpected document: %s", parser.String())
+	}
+
+	_, err := parser.Add(`,`)
+	if !errors.Is(err, openai.ErrPartialJSONSyntax) {
+		t.Errorf("expected ErrPartialJSONSyntax, got %v", err)
+	}
+	if _, err = parser.Value(); !errors.Is(err, openai.ErrPartialJSONSyntax) {
+		t.Errorf("syntax errors should be sticky, got %v", err)
+	}
+}
+
+func TestPartialJSONWithChatCompletionStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, content := range []string{`{"name":`, `"Par`, `is","population":21`, `00000}`} {
+			delta, _ := json.Marshal(content)
+			fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%s}}]}\n\n", delta)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Paris?"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	parser := openai.NewPartialJSON[cityInfo]()
+	var got []cityInfo
+	for {
+		chunk, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		city, addErr := parser.Add(chunk.Choices[0].Delta.Content)
+		checks.NoError(t, addErr, "Add error")
+		got = append(got, city)
+	}
+	want := []cityInfo{
+		{},
+		{Name: "Par"},
+		{Name: "Paris", Population: 21},
+		{Name: "Paris", Population: 2100000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected partial values:\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestPartialJSONWithResponseStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{`{"city":"Pa`, `ris","days":[1,`, `2]}`} {
+			data, _ := json.Marshal(delta)
+			fmt.Fprintf(w, "data: {\"type\":\"response.function_call_arguments.delta\","+
+				"\"item_id\":\"fc_1\",\"output_index\":0,\"delta\":%s}\n\n", data)
+		}
+		fmt.Fprint(w, "data: {\"type\":\"response.function_call_arguments.done\",\"item_id\":\"fc_1\","+
+			"\"output_index\":0,\"arguments\":\"{\\\"city\\\":\\\"Paris\\\",\\\"days\\\":[1,2]}\"}\n\n")
+	})
+
+	stream, err := client.CreateResponseStream(context.Background(), openai.CreateResponseRequest{
+		Model: openai.GPT4o,
+		Input: "Forecast for Paris?",
+	})
+	checks.NoError(t, err, "CreateResponseStream error")
+	defer stream.Close()
+
+	parser := openai.NewPartialJSON[map[string]any]()
+	var got []string
+	for {
+		event, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		if event.Type != openai.ResponseStreamEventFunctionArgumentsDelta {
+			continue
+		}
+		args, addErr := parser.Add(event.Delta)
+		checks.NoError(t, addErr, "Add error")
+		got = append(got, fmt.Sprint(args))
+	}
+	want := "[map[city:Pa] map[city:Paris days:[1]] map[city:Paris days:[1 2]]]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("unexpected partial values: %v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package openai
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	defaultPollInterval    = 500 * time.Millisecond
+	defaultPollMaxInterval = 5 * time.Second
+	defaultPollMultiplier  = 1.5
+)
+
+// PollPolicy configures how often the status of a long-running operation,
+// such as an Assistants run or a background response, is polled. The delay
+// between polls starts at Interval and grows by Multiplier up to MaxInterval.
+type PollPolicy struct {
+	// Interval is the delay before the first poll.
+	Interval time.Duration
+	// MaxInterval caps the growing delay.
+	MaxInterval time.Duration
+	// Multiplier is the factor the delay grows by after each poll. A
+	// multiplier of 1 polls at a constant Interval.
+	Multiplier float64
+}
+
+// DefaultPollPolicy returns a policy that polls after 500ms and then backs
+// off by 1.5x up to 5s between polls.
+func DefaultPollPolicy() *PollPolicy {
+	return &PollPolicy{
+		Interval:    defaultPollInterval,
+		MaxInterval: defaultPollMaxInterval,
+		Multiplier:  defaultPollMultiplier,
+	}
+}
+
+// delay returns the delay before the given poll, counting from 1. A nil
+// policy behaves like DefaultPollPolicy.
+func (p *PollPolicy) delay(poll int) time.Duration {
+	if p == nil {
+		p = DefaultPollPolicy()
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	d := float64(p.Interval) * math.Pow(multiplier, float64(poll-1))
+	if p.MaxInterval > 0 && d > float64(p.MaxInterval) {
+		d = float64(p.MaxInterval)
+	}
+	return time.Duration(d)
+}
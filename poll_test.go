@@ -0,0 +1,29 @@
+package openai //nolint:testpackage // testing private method
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPollPolicyDelay(t *testing.T) {
+	policy := &PollPolicy{Interval: 100 * time.Millisecond, MaxInterval: 300 * time.Millisecond, Multiplier: 2}
+	for poll, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 300 * time.Millisecond,
+		9: 300 * time.Millisecond,
+	} {
+		if got := policy.delay(poll); got != want {
+			t.Errorf("delay(%d) = %v, want %v", poll, got, want)
+		}
+	}
+
+	var defaults *PollPolicy
+	if got := defaults.delay(2); got != 750*time.Millisecond {
+		t.Errorf("default delay(2) = %v, want 750ms", got)
+	}
+	constant := &PollPolicy{Interval: time.Second}
+	if got := constant.delay(5); got != time.Second {
+		t.Errorf("constant delay(5) = %v, want 1s", got)
+	}
+}
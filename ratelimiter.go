@@ -0,0 +1,232 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitExceeded is returned by a rejecting RateLimiter when a call would
+// exceed the remaining request or token budget.
+var ErrRateLimitExceeded = errors.New("client-side rate limit exceeded")
+
+// approxCharsPerToken is the rule of thumb OpenAI gives for English text.
+const approxCharsPerToken = 4
+
+// RateLimiterConfig configures a RateLimiter.
+type RateLimiterConfig struct {
+	// Reject makes calls fail fast with ErrRateLimitExceeded instead of
+	// blocking until enough budget is available.
+	Reject bool
+	// EstimateTokens returns the number of tokens a typed request is expected
+	// to consume. When nil, the estimate is the request's maximum output
+	// tokens plus a character-based estimate of the request body.
+	EstimateTokens func(request any, bodySize int64) int
+}
+
+// RateLimiter is a client-side token bucket that keeps calls within the
+// requests-per-minute and tokens-per-minute limits of each model. The buckets
+// are calibrated from the x-ratelimit-* headers of every response, so no
+// call is held back until the first response for a model has been seen.
+//
+// A RateLimiter is safe for concurrent use and may be shared between clients
+// using the same API key.
+type RateLimiter struct {
+	config RateLimiterConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*rateLimitBuckets
+}
+
+// NewRateLimiter creates a RateLimiter.
+func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
+	return &RateLimiter{
+		config:  config,
+		now:     time.Now,
+		buckets: make(map[string]*rateLimitBuckets),
+	}
+}
+
+type rateLimitBuckets struct {
+	requests tokenBucket
+	tokens   tokenBucket
+}
+
+// tokenBucket refills continuously at limit per minute.
+type tokenBucket struct {
+	limit     float64
+	available float64
+	updated   time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b.limit <= 0 {
+		return
+	}
+	b.available += now.Sub(b.updated).Minutes() * b.limit
+	if b.available > b.limit {
+		b.available = b.limit
+	}
+	b.updated = now
+}
+
+// wait returns how long until cost units are available. Costs above the limit
+// only wait for a full bucket, as they could otherwise never be satisfied.
+func (b *tokenBucket) wait(cost float64) time.Duration {
+	if b.limit <= 0 {
+		return 0
+	}
+	if cost > b.limit {
+		cost = b.limit
+	}
+	if b.available >= cost {
+		return 0
+	}
+	return time.Duration((cost - b.available) / b.limit * float64(time.Minute))
+}
+
+func (b *tokenBucket) update(limit, remaining int, now time.Time) {
+	if limit <= 0 {
+		return
+	}
+	b.limit = float64(limit)
+	b.available = float64(remaining)
+	b.updated = now
+}
+
+// Wait blocks until the budget for model allows one more request consuming
+// the given number of tokens, and then reserves it. A rejecting limiter
+// returns ErrRateLimitExceeded instead of blocking.
+func (l *RateLimiter) Wait(ctx context.Context, model string, tokens int) error {
+	for {
+		delay := l.reserve(model, float64(tokens))
+		if delay == 0 {
+			return nil
+		}
+		if l.config.Reject {
+			return ErrRateLimitExceeded
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes the budget if it is available and otherwise returns how long
+// to wait before trying again.
+func (l *RateLimiter) reserve(model string, tokens float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	buckets, ok := l.buckets[model]
+	if !ok {
+		return 0
+	}
+	now := l.now()
+	buckets.requests.refill(now)
+	buckets.tokens.refill(now)
+
+	delay := buckets.requests.wait(1)
+	if d := buckets.tokens.wait(tokens); d > delay {
+		delay = d
+	}
+	if delay > 0 {
+		return delay
+	}
+	buckets.requests.available--
+	buckets.tokens.available -= tokens
+	return 0
+}
+
+// Update calibrates the budget for model from the rate limit headers of a response.
+func (l *RateLimiter) Update(model string, headers RateLimitHeaders) {
+	if headers.LimitRequests <= 0 && headers.LimitTokens <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	buckets, ok := l.buckets[model]
+	if !ok {
+		buckets = &rateLimitBuckets{}
+		l.buckets[model] = buckets
+	}
+	now := l.now()
+	buckets.requests.update(headers.LimitRequests, headers.RemainingRequests, now)
+	buckets.tokens.update(headers.LimitTokens, headers.RemainingTokens, now)
+}
+
+// Remaining returns the currently available request and token budget for
+// model. Both are -1 until the first response for the model has been seen.
+func (l *RateLimiter) Remaining(model string) (requests, tokens int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	buckets, ok := l.buckets[model]
+	if !ok {
+		return -1, -1
+	}
+	now := l.now()
+	buckets.requests.refill(now)
+	buckets.tokens.refill(now)
+	return int(buckets.requests.available), int(buckets.tokens.available)
+}
+
+func (l *RateLimiter) estimateTokens(request any, bodySize int64) int {
+	if l.config.EstimateTokens != nil {
+		return l.config.EstimateTokens(request, bodySize)
+	}
+	return estimateRequestTokens(request, bodySize)
+}
+
+// estimateRequestTokens estimates the token cost of the requests that are
+// billed by tokens. Other requests, such as file uploads, only count against
+// the request budget.
+func estimateRequestTokens(request any, bodySize int64) int {
+	var maxOutput int
+	switch r := request.(type) {
+	case ChatCompletionRequest:
+		maxOutput = r.MaxCompletionTokens
+		if maxOutput == 0 {
+			maxOutput = r.MaxTokens
+		}
+	case CompletionRequest:
+		maxOutput = r.MaxTokens
+	case CreateResponseRequest:
+		maxOutput = r.MaxOutputTokens
+	case EmbeddingRequestConverter:
+		// Embeddings only consume input tokens.
+	default:
+		return 0
+	}
+	if bodySize < 0 {
+		bodySize = 0
+	}
+	return maxOutput + int(bodySize/approxCharsPerToken)
+}
+
+// sendRateLimited sends req once, keeping it within the budget of the
+// configured RateLimiter.
+func (c *Client) sendRateLimited(req *http.Request) (*http.Response, error) {
+	limiter := c.config.RateLimiter
+	if limiter == nil {
+		return c.config.HTTPClient.Do(req)
+	}
+
+	op := operationFromRequest(req)
+	model := requestModel(op.Request)
+	tokens := limiter.estimateTokens(op.Request, req.ContentLength)
+	if err := limiter.Wait(req.Context(), model, tokens); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.config.HTTPClient.Do(req)
+	if err == nil {
+		limiter.Update(model, newRateLimitHeaders(resp.Header))
+	}
+	return resp, err
+}
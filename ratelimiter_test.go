@@ -0,0 +1,156 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func setupRateLimitedTestServer(limiter *openai.RateLimiter) (*openai.Client, *test.ServerTest, func()) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.RateLimiter = limiter
+	return openai.NewClientWithConfig(config), server, ts.Close
+}
+
+type rateLimitState struct {
+	limitRequests, remainingRequests int
+	limitTokens, remainingTokens     int
+}
+
+func (s rateLimitState) write(w http.ResponseWriter) {
+	w.Header().Set("x-ratelimit-limit-requests", strconv.Itoa(s.limitRequests))
+	w.Header().Set("x-ratelimit-remaining-requests", strconv.Itoa(s.remainingRequests))
+	w.Header().Set("x-ratelimit-limit-tokens", strconv.Itoa(s.limitTokens))
+	w.Header().Set("x-ratelimit-remaining-tokens", strconv.Itoa(s.remainingTokens))
+	fmt.Fprint(w, `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"hi"}}]}`)
+}
+
+func chatRequest(model string, maxTokens int) openai.ChatCompletionRequest {
+	return openai.ChatCompletionRequest{
+		Model:               model,
+		MaxCompletionTokens: maxTokens,
+		Messages:            []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+	}
+}
+
+func TestRateLimiterRejectsExhaustedRequestBudget(t *testing.T) {
+	limiter := openai.NewRateLimiter(openai.RateLimiterConfig{Reject: true})
+	client, server, teardown := setupRateLimitedTestServer(limiter)
+	defer teardown()
+
+	calls := 0
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		rateLimitState{limitRequests: 60, remainingRequests: 0, limitTokens: 10000, remainingTokens: 9000}.write(w)
+	})
+
+	_, err := client.CreateChatCompletion(context.Background(), chatRequest(openai.GPT4oMini, 10))
+	checks.NoError(t, err, "first call should not be limited")
+
+	_, err = client.CreateChatCompletion(context.Background(), chatRequest(openai.GPT4oMini, 10))
+	checks.ErrorIs(t, err, openai.ErrRateLimitExceeded, "second call should be rejected")
+	if calls != 1 {
+		t.Fatalf("rejected call must not reach the server, got %d calls", calls)
+	}
+
+	// Budgets are tracked per model.
+	_, err = client.CreateChatCompletion(context.Background(), chatRequest(openai.GPT4o, 10))
+	checks.NoError(t, err, "other models should not be limited")
+}
+
+func TestRateLimiterRejectsExhaustedTokenBudget(t *testing.T) {
+	limiter := openai.NewRateLimiter(openai.RateLimiterConfig{Reject: true})
+	client, server, teardown := setupRateLimitedTestServer(limiter)
+	defer teardown()
+
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		rateLimitState{limitRequests: 100, remainingRequests: 99, limitTokens: 10000, remainingTokens: 1000}.write(w)
+	})
+
+	_, err := client.CreateChatCompletion(context.Background(), chatRequest(openai.GPT4oMini, 10))
+	checks.NoError(t, err, "first call should not be limited")
+	requests, tokens := limiter.Remaining(openai.GPT4oMini)
+	if requests != 99 || tokens < 1000 || tokens > 1010 {
+		t.Fatalf("unexpected remaining budget: %d requests, %d tokens", requests, tokens)
+	}
+
+	_, err = client.CreateChatCompletion(context.Background(), chatRequest(openai.GPT4oMini, 5000))
+	checks.ErrorIs(t, err, openai.ErrRateLimitExceeded, "call above the token budget should be rejected")
+
+	_, err = client.CreateChatCompletion(context.Background(), chatRequest(openai.GPT4oMini, 100))
+	checks.NoError(t, err, "call within the token budget should pass")
+}
+
+func TestRateLimiterBlocksUntilBudgetRefills(t *testing.T) {
+	limiter := openai.NewRateLimiter(openai.RateLimiterConfig{})
+	client, server, teardown := setupRateLimitedTestServer(limiter)
+	defer teardown()
+
+	// 1200 requests per minute refill one request every 50ms.
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		rateLimitState{limitRequests: 1200, remainingRequests: 0, limitTokens: 100000, remainingTokens: 100000}.write(w)
+	})
+
+	_, err := client.CreateChatCompletion(context.Background(), chatRequest(openai.GPT4oMini, 10))
+	checks.NoError(t, err, "first call should not be limited")
+
+	start := time.Now()
+	_, err = client.CreateChatCompletion(context.Background(), chatRequest(openai.GPT4oMini, 10))
+	checks.NoError(t, err, "second call should wait and succeed")
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("second call should have waited for the budget, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterRespectsContext(t *testing.T) {
+	limiter := openai.NewRateLimiter(openai.RateLimiterConfig{})
+	limiter.Update(openai.GPT4oMini, openai.RateLimitHeaders{LimitRequests: 1, RemainingRequests: 0})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := limiter.Wait(ctx, openai.GPT4oMini, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRateLimiterCustomEstimator(t *testing.T) {
+	var estimated []any
+	limiter := openai.NewRateLimiter(openai.RateLimiterConfig{
+		Reject: true,
+		EstimateTokens: func(request any, _ int64) int {
+			estimated = append(estimated, request)
+			return 1
+		},
+	})
+	limiter.Update(openai.GPT4oMini, openai.RateLimitHeaders{
+		LimitRequests: 10, RemainingRequests: 10,
+		LimitTokens: 10, RemainingTokens: 1,
+	})
+	client, server, teardown := setupRateLimitedTestServer(limiter)
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"chatcmpl-1"}`)
+	})
+
+	_, err := client.CreateChatCompletion(context.Background(), chatRequest(openai.GPT4oMini, 5000))
+	checks.NoError(t, err, "custom estimate should fit the budget")
+	if len(estimated) != 1 {
+		t.Fatalf("estimator should be called once, got %d", len(estimated))
+	}
+	if _, ok := estimated[0].(openai.ChatCompletionRequest); !ok {
+		t.Fatalf("estimator should receive the typed request, got %T", estimated[0])
+	}
+}
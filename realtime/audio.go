@@ -0,0 +1,63 @@
+package realtime
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// PCM16SampleRate is the sample rate of PCM16 audio, in Hz.
+const PCM16SampleRate = 24000
+
+const pcm16SampleSize = 2
+
+// ErrPCM16OddLength is returned by DecodePCM16 when the audio does not
+// hold whole 16-bit samples.
+var ErrPCM16OddLength = errors.New("realtime: PCM16 audio has an odd number of bytes")
+
+// EncodePCM16 encodes mono 16-bit samples as base64 little-endian PCM, the
+// encoding of the audio/pcm format.
+func EncodePCM16(samples []int16) string {
+	data := make([]byte, len(samples)*pcm16SampleSize)
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint16(data[i*pcm16SampleSize:], uint16(sample))
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// DecodePCM16 decodes base64 little-endian PCM, such as the deltas of
+// response.output_audio.delta events, into 16-bit samples.
+func DecodePCM16(audio string) ([]int16, error) {
+	data, err := base64.StdEncoding.DecodeString(audio)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%pcm16SampleSize != 0 {
+		return nil, ErrPCM16OddLength
+	}
+	samples := make([]int16, len(data)/pcm16SampleSize)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*pcm16SampleSize:]))
+	}
+	return samples, nil
+}
+
+// PCM16Chunks splits samples at 24kHz into encoded chunks of chunkDuration,
+// to be sent with InputAudioBufferAppendEvent. The last chunk may be shorter.
+// A chunkDuration shorter than one sample sends everything in one chunk.
+func PCM16Chunks(samples []int16, chunkDuration time.Duration) []string {
+	size := int(chunkDuration * PCM16SampleRate / time.Second)
+	if size <= 0 {
+		size = len(samples)
+	}
+	var chunks []string
+	for start := 0; start < len(samples); start += size {
+		end := start + size
+		if end > len(samples) {
+			end = len(samples)
+		}
+		chunks = append(chunks, EncodePCM16(samples[start:end]))
+	}
+	return chunks
+}
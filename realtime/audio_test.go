@@ -0,0 +1,55 @@
+package realtime_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/realtime"
+)
+
+func TestPCM16RoundTrip(t *testing.T) {
+	samples := []int16{0, 1, -1, 32767, -32768, 256}
+	audio := realtime.EncodePCM16(samples)
+	if audio != "AAABAP///38AgAAB" {
+		t.Errorf("unexpected encoding %q", audio)
+	}
+
+	decoded, err := realtime.DecodePCM16(audio)
+	checks.NoError(t, err, "DecodePCM16 error")
+	if !reflect.DeepEqual(decoded, samples) {
+		t.Errorf("expected %v, got %v", samples, decoded)
+	}
+
+	_, err = realtime.DecodePCM16("AAAB")
+	if !errors.Is(err, realtime.ErrPCM16OddLength) {
+		t.Errorf("expected ErrPCM16OddLength, got %v", err)
+	}
+	_, err = realtime.DecodePCM16("not base64")
+	checks.HasError(t, err, "expected a base64 error")
+}
+
+func TestPCM16Chunks(t *testing.T) {
+	// 250ms of audio in 100ms chunks of 2400 samples.
+	samples := make([]int16, realtime.PCM16SampleRate/4)
+	chunks := realtime.PCM16Chunks(samples, 100*time.Millisecond)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	for i, want := range []int{2400, 2400, 1200} {
+		decoded, err := realtime.DecodePCM16(chunks[i])
+		checks.NoError(t, err, "DecodePCM16 error")
+		if len(decoded) != want {
+			t.Errorf("chunk %d: expected %d samples, got %d", i, want, len(decoded))
+		}
+	}
+
+	if chunks = realtime.PCM16Chunks(samples, 0); len(chunks) != 1 {
+		t.Errorf("expected a single chunk, got %d", len(chunks))
+	}
+	if chunks = realtime.PCM16Chunks(nil, time.Second); len(chunks) != 0 {
+		t.Errorf("expected no chunks, got %d", len(chunks))
+	}
+}
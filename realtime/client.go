@@ -0,0 +1,166 @@
+// Package realtime is a client for the Realtime API, which streams text,
+// audio and function calls over a WebSocket session.
+//
+// A session is opened with Client.Connect. Client events are sent with
+// Conn.Send and server events are received with Conn.Recv, or handled in a
+// loop with Conn.Run:
+//
+//	conn, err := realtime.NewClient(apiKey).Connect(ctx, "gpt-realtime")
+//	if err != nil {
+//		return err
+//	}
+//	defer conn.Close()
+//	err = conn.Send(realtime.ResponseCreateEvent{})
+//	if err != nil {
+//		return err
+//	}
+//	err = conn.Run(ctx, func(event realtime.ServerEvent) error {
+//		if event.Type == realtime.ServerEventResponseOutputTextDelta {
+//			fmt.Print(event.Delta)
+//		}
+//		return nil
+//	})
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/websocket"
+)
+
+const openaiBaseURL = "https://api.openai.com/v1"
+
+// Config configures a Client. Use DefaultConfig or DefaultAzureConfig to
+// create one.
+type Config struct {
+	authToken string
+
+	// BaseURL is the URL of the API, e.g. "https://api.openai.com/v1" or, for
+	// Azure, "https://my-resource.openai.azure.com". The ws and wss schemes
+	// are accepted as well.
+	BaseURL string
+	OrgID   string
+	// APIType selects the URL style and authentication: openai.APITypeOpenAI,
+	// openai.APITypeAzure (api-key header) or openai.APITypeAzureAD (bearer
+	// token). Azure sessions use the GA endpoint, /openai/v1/realtime, whose
+	// events match the OpenAI API.
+	APIType openai.APIType
+	// AzureModelMapperFunc maps a model name to an Azure deployment name. By
+	// default the model name is used as the deployment name.
+	AzureModelMapperFunc func(model string) string
+	// HTTPClient performs the opening handshake.
+	HTTPClient *http.Client
+	// Header holds additional handshake headers, e.g. "OpenAI-Beta:
+	// realtime=v1" to use the beta interface of the API.
+	Header http.Header
+}
+
+// DefaultConfig returns the configuration for the OpenAI API.
+func DefaultConfig(authToken string) Config {
+	return Config{
+		authToken:  authToken,
+		BaseURL:    openaiBaseURL,
+		APIType:    openai.APITypeOpenAI,
+		HTTPClient: &http.Client{},
+	}
+}
+
+// DefaultAzureConfig returns the configuration for an Azure OpenAI resource.
+func DefaultAzureConfig(apiKey, baseURL string) Config {
+	return Config{
+		authToken:  apiKey,
+		BaseURL:    baseURL,
+		APIType:    openai.APITypeAzure,
+		HTTPClient: &http.Client{},
+	}
+}
+
+func (Config) String() string {
+	return "<OpenAI Realtime API Config>"
+}
+
+// Client opens Realtime API sessions.
+type Client struct {
+	config Config
+}
+
+// NewClient creates a client for the OpenAI API.
+func NewClient(authToken string) *Client {
+	return NewClientWithConfig(DefaultConfig(authToken))
+}
+
+// NewClientWithConfig creates a client with the given configuration.
+func NewClientWithConfig(config Config) *Client {
+	return &Client{config: config}
+}
+
+// Connect opens a session with model, which is the deployment on Azure. ctx
+// bounds the opening handshake only; the session lasts until it is closed.
+// Handshakes rejected by the API return an *openai.APIError, or an
+// *openai.RequestError when the response carries no error details.
+func (c *Client) Connect(ctx context.Context, model string) (*Conn, error) {
+	ws, err := websocket.Dial(ctx, c.config.HTTPClient, c.url(model), c.header())
+	var handshakeErr *websocket.HandshakeError
+	if errors.As(err, &handshakeErr) {
+		return nil, handshakeError(handshakeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newConn(ws), nil
+}
+
+func (c *Client) url(model string) string {
+	baseURL := strings.TrimRight(c.config.BaseURL, "/")
+	switch c.config.APIType {
+	case openai.APITypeAzure, openai.APITypeAzureAD:
+		deployment := model
+		if c.config.AzureModelMapperFunc != nil {
+			deployment = c.config.AzureModelMapperFunc(model)
+		}
+		return fmt.Sprintf("%s/openai/v1/realtime?%s", baseURL, url.Values{"model": {deployment}}.Encode())
+	default:
+		return fmt.Sprintf("%s/realtime?%s", baseURL, url.Values{"model": {model}}.Encode())
+	}
+}
+
+func (c *Client) header() http.Header {
+	header := c.config.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if c.config.APIType == openai.APITypeAzure {
+		header.Set(openai.AzureAPIKeyHeader, c.config.authToken)
+	} else {
+		header.Set("Authorization", "Bearer "+c.config.authToken)
+	}
+	if c.config.OrgID != "" {
+		header.Set("OpenAI-Organization", c.config.OrgID)
+	}
+	return header
+}
+
+// handshakeError converts a rejected handshake into the errors returned by
+// the HTTP endpoints of the API.
+func handshakeError(err *websocket.HandshakeError) error {
+	status := fmt.Sprintf("%d %s", err.StatusCode, http.StatusText(err.StatusCode))
+	var errRes openai.ErrorResponse
+	if jsonErr := json.Unmarshal(err.Body, &errRes); jsonErr != nil || errRes.Error == nil {
+		return &openai.RequestError{
+			HTTPStatus:     status,
+			HTTPStatusCode: err.StatusCode,
+			Err:            err,
+			Body:           err.Body,
+		}
+	}
+	errRes.Error.HTTPStatus = status
+	errRes.Error.HTTPStatusCode = err.StatusCode
+	return errRes.Error
+}
@@ -0,0 +1,143 @@
+package realtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ClientEventType identifies an event sent by the client.
+type ClientEventType string
+
+const (
+	ClientEventSessionUpdate            ClientEventType = "session.update"
+	ClientEventInputAudioBufferAppend   ClientEventType = "input_audio_buffer.append"
+	ClientEventInputAudioBufferCommit   ClientEventType = "input_audio_buffer.commit"
+	ClientEventInputAudioBufferClear    ClientEventType = "input_audio_buffer.clear"
+	ClientEventConversationItemCreate   ClientEventType = "conversation.item.create"
+	ClientEventConversationItemTruncate ClientEventType = "conversation.item.truncate"
+	ClientEventConversationItemDelete   ClientEventType = "conversation.item.delete"
+	ClientEventResponseCreate           ClientEventType = "response.create"
+	ClientEventResponseCancel           ClientEventType = "response.cancel"
+)
+
+// ClientEvent is an event sent with Conn.Send. Its type is added to the JSON
+// encoding of the event.
+type ClientEvent interface {
+	ClientEventType() ClientEventType
+}
+
+// SessionUpdateEvent updates the session configuration.
+type SessionUpdateEvent struct {
+	EventID string  `json:"event_id,omitempty"`
+	Session Session `json:"session"`
+}
+
+// InputAudioBufferAppendEvent appends base64-encoded audio, e.g. a chunk
+// returned by EncodePCM16 or PCM16Chunks, to the input audio buffer.
+type InputAudioBufferAppendEvent struct {
+	EventID string `json:"event_id,omitempty"`
+	Audio   string `json:"audio"`
+}
+
+// InputAudioBufferCommitEvent commits the input audio buffer as a user
+// message. It is only needed when turn detection is disabled.
+type InputAudioBufferCommitEvent struct {
+	EventID string `json:"event_id,omitempty"`
+}
+
+// InputAudioBufferClearEvent clears the input audio buffer.
+type InputAudioBufferClearEvent struct {
+	EventID string `json:"event_id,omitempty"`
+}
+
+// ConversationItemCreateEvent adds an item to the conversation, after
+// PreviousItemID or at the end.
+type ConversationItemCreateEvent struct {
+	EventID        string `json:"event_id,omitempty"`
+	PreviousItemID string `json:"previous_item_id,omitempty"`
+	Item           Item   `json:"item"`
+}
+
+// ConversationItemTruncateEvent truncates the audio of an assistant message,
+// typically the part that was not played before the user interrupted.
+type ConversationItemTruncateEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ItemID       string `json:"item_id"`
+	ContentIndex int    `json:"content_index"`
+	AudioEndMS   int    `json:"audio_end_ms"`
+}
+
+// ConversationItemDeleteEvent removes an item from the conversation.
+type ConversationItemDeleteEvent struct {
+	EventID string `json:"event_id,omitempty"`
+	ItemID  string `json:"item_id"`
+}
+
+// ResponseCreateEvent asks the model for a response, configured by the
+// session unless Response overrides it.
+type ResponseCreateEvent struct {
+	EventID  string          `json:"event_id,omitempty"`
+	Response *ResponseConfig `json:"response,omitempty"`
+}
+
+// ResponseCancelEvent cancels the response in progress, or ResponseID.
+type ResponseCancelEvent struct {
+	EventID    string `json:"event_id,omitempty"`
+	ResponseID string `json:"response_id,omitempty"`
+}
+
+func (SessionUpdateEvent) ClientEventType() ClientEventType { return ClientEventSessionUpdate }
+
+func (InputAudioBufferAppendEvent) ClientEventType() ClientEventType {
+	return ClientEventInputAudioBufferAppend
+}
+
+func (InputAudioBufferCommitEvent) ClientEventType() ClientEventType {
+	return ClientEventInputAudioBufferCommit
+}
+
+func (InputAudioBufferClearEvent) ClientEventType() ClientEventType {
+	return ClientEventInputAudioBufferClear
+}
+
+func (ConversationItemCreateEvent) ClientEventType() ClientEventType {
+	return ClientEventConversationItemCreate
+}
+
+func (ConversationItemTruncateEvent) ClientEventType() ClientEventType {
+	return ClientEventConversationItemTruncate
+}
+
+func (ConversationItemDeleteEvent) ClientEventType() ClientEventType {
+	return ClientEventConversationItemDelete
+}
+
+func (ResponseCreateEvent) ClientEventType() ClientEventType { return ClientEventResponseCreate }
+
+func (ResponseCancelEvent) ClientEventType() ClientEventType { return ClientEventResponseCancel }
+
+// marshalClientEvent encodes event with its type as the first field.
+func marshalClientEvent(event ClientEvent) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 || data[0] != '{' {
+		return nil, fmt.Errorf("realtime: client event %T is not a JSON object", event)
+	}
+	eventType, err := json.Marshal(event.ClientEventType())
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(data) + len(eventType) + len(`"type":,`))
+	buf.WriteString(`{"type":`)
+	buf.Write(eventType)
+	if len(data) > len("{}") {
+		buf.WriteByte(',')
+	}
+	buf.Write(data[1:])
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,285 @@
+package realtime_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/internal/websocket"
+	"github.com/sashabaranov/go-openai/realtime"
+)
+
+// realtimeServer accepts a WebSocket session and hands it to handler, then
+// closes it normally.
+func realtimeServer(t *testing.T, handler func(r *http.Request, conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r)
+		if err != nil {
+			t.Errorf("Accept error: %v", err)
+			return
+		}
+		defer conn.Close(websocket.CloseNormalClosure, "")
+		handler(r, conn)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func readEvent(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		t.Errorf("ReadMessage error: %v", err)
+		return nil
+	}
+	var event map[string]any
+	if err = json.Unmarshal(message, &event); err != nil {
+		t.Errorf("invalid client event %s: %v", message, err)
+	}
+	return event
+}
+
+func writeEvent(t *testing.T, conn *websocket.Conn, event string) {
+	t.Helper()
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(event)); err != nil {
+		t.Errorf("WriteMessage error: %v", err)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	server := realtimeServer(t, func(r *http.Request, conn *websocket.Conn) {
+		if r.URL.Path != "/v1/realtime" || r.URL.Query().Get("model") != "gpt-realtime" {
+			t.Errorf("unexpected URL %s", r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+		if got := r.Header.Get("OpenAI-Organization"); got != "org" {
+			t.Errorf("unexpected OpenAI-Organization header %q", got)
+		}
+
+		event := readEvent(t, conn)
+		if event["type"] != "session.update" {
+			t.Errorf("unexpected client event %v", event)
+		}
+		session, _ := event["session"].(map[string]any)
+		if session["instructions"] != "Be brief." {
+			t.Errorf("unexpected session %v", session)
+		}
+		writeEvent(t, conn, `{"type":"response.output_text.delta","event_id":"ev_1","item_id":"item_1","delta":"Hi"}`)
+	})
+
+	config := realtime.DefaultConfig("token")
+	config.BaseURL = server.URL + "/v1"
+	config.OrgID = "org"
+	conn, err := realtime.NewClientWithConfig(config).Connect(context.Background(), "gpt-realtime")
+	checks.NoError(t, err, "Connect error")
+	defer conn.Close()
+
+	err = conn.Send(realtime.SessionUpdateEvent{
+		Session: realtime.Session{Type: "realtime", Instructions: "Be brief."},
+	})
+	checks.NoError(t, err, "Send error")
+
+	event, err := conn.Recv(context.Background())
+	checks.NoError(t, err, "Recv error")
+	if event.Type != realtime.ServerEventResponseOutputTextDelta || event.Delta != "Hi" || event.ItemID != "item_1" {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if len(event.Raw) == 0 {
+		t.Error("expected the raw event")
+	}
+
+	_, err = conn.Recv(context.Background())
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF after a normal closure, got %v", err)
+	}
+}
+
+func TestConnectAzure(t *testing.T) {
+	server := realtimeServer(t, func(r *http.Request, conn *websocket.Conn) {
+		query := r.URL.Query()
+		if r.URL.Path != "/openai/v1/realtime" || query.Get("model") != "realtime-deployment" || len(query) != 1 {
+			t.Errorf("unexpected URL %s", r.URL)
+		}
+		if got := r.Header.Get(openai.AzureAPIKeyHeader); got != "key" {
+			t.Errorf("unexpected %s header %q", openai.AzureAPIKeyHeader, got)
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+
+		event := readEvent(t, conn)
+		session, _ := event["session"].(map[string]any)
+		if event["type"] != "session.update" || session["type"] != "realtime" {
+			t.Errorf("unexpected client event %v", event)
+		}
+		writeEvent(t, conn, `{"type":"session.updated","event_id":"ev_1",`+
+			`"session":{"type":"realtime","id":"sess_1","output_modalities":["text"]}}`)
+		writeEvent(t, conn, `{"type":"response.output_text.delta","event_id":"ev_2","item_id":"item_1","delta":"Hi"}`)
+	})
+
+	config := realtime.DefaultAzureConfig("key", server.URL)
+	config.AzureModelMapperFunc = func(string) string { return "realtime-deployment" }
+	conn, err := realtime.NewClientWithConfig(config).Connect(context.Background(), "gpt-realtime")
+	checks.NoError(t, err, "Connect error")
+	defer conn.Close()
+
+	err = conn.Send(realtime.SessionUpdateEvent{
+		Session: realtime.Session{Type: "realtime", OutputModalities: []realtime.Modality{realtime.ModalityText}},
+	})
+	checks.NoError(t, err, "Send error")
+
+	event, err := conn.Recv(context.Background())
+	checks.NoError(t, err, "Recv error")
+	if event.Type != realtime.ServerEventSessionUpdated || event.Session == nil || event.Session.ID != "sess_1" {
+		t.Errorf("unexpected event %+v", event)
+	}
+	event, err = conn.Recv(context.Background())
+	checks.NoError(t, err, "Recv error")
+	if event.Type != realtime.ServerEventResponseOutputTextDelta || event.Delta != "Hi" {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	_, err = conn.Recv(context.Background())
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestConnectHandshakeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer bad" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"Incorrect API key","type":"invalid_request_error"}}`))
+			return
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := realtime.DefaultConfig("bad")
+	config.BaseURL = server.URL
+	_, err := realtime.NewClientWithConfig(config).Connect(context.Background(), "gpt-realtime")
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *openai.APIError, got %v", err)
+	}
+	if apiErr.HTTPStatusCode != http.StatusUnauthorized || apiErr.Message != "Incorrect API key" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+
+	config = realtime.DefaultConfig("token")
+	config.BaseURL = server.URL
+	_, err = realtime.NewClientWithConfig(config).Connect(context.Background(), "gpt-realtime")
+	var reqErr *openai.RequestError
+	if !errors.As(err, &reqErr) || reqErr.HTTPStatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected an *openai.RequestError with status 503, got %v", err)
+	}
+}
+
+func TestConnRun(t *testing.T) {
+	server := realtimeServer(t, func(_ *http.Request, conn *websocket.Conn) {
+		event := readEvent(t, conn)
+		if len(event) != 1 || event["type"] != "response.create" {
+			t.Errorf("unexpected client event %v", event)
+		}
+		writeEvent(t, conn, `{"type":"response.created","response":{"id":"resp_1","status":"in_progress"}}`)
+		writeEvent(t, conn, `{"type":"error","error":{"type":"invalid_request_error","code":"bad","message":"oops"}}`)
+		writeEvent(t, conn, `{"type":"response.done","response":{"id":"resp_1","status":"completed"}}`)
+	})
+
+	config := realtime.DefaultConfig("token")
+	config.BaseURL = server.URL
+	conn, err := realtime.NewClientWithConfig(config).Connect(context.Background(), "gpt-realtime")
+	checks.NoError(t, err, "Connect error")
+	defer conn.Close()
+
+	checks.NoError(t, conn.Send(realtime.ResponseCreateEvent{}), "Send error")
+
+	var (
+		types    []realtime.ServerEventType
+		eventErr error
+	)
+	err = conn.Run(context.Background(), func(event realtime.ServerEvent) error {
+		types = append(types, event.Type)
+		if event.Error != nil {
+			eventErr = event.Error
+		}
+		return nil
+	})
+	checks.NoError(t, err, "Run error")
+	if len(types) != 3 || types[2] != realtime.ServerEventResponseDone {
+		t.Errorf("unexpected events %v", types)
+	}
+	if eventErr == nil || eventErr.Error() != "bad: oops" {
+		t.Errorf("unexpected error event %v", eventErr)
+	}
+}
+
+func TestConnRunHandlerError(t *testing.T) {
+	server := realtimeServer(t, func(_ *http.Request, conn *websocket.Conn) {
+		writeEvent(t, conn, `{"type":"session.created","session":{"id":"sess_1"}}`)
+		_, _, _ = conn.ReadMessage()
+	})
+
+	config := realtime.DefaultConfig("token")
+	config.BaseURL = server.URL
+	conn, err := realtime.NewClientWithConfig(config).Connect(context.Background(), "gpt-realtime")
+	checks.NoError(t, err, "Connect error")
+	defer conn.Close()
+
+	errStop := errors.New("stop")
+	err = conn.Run(context.Background(), func(event realtime.ServerEvent) error {
+		if event.Session == nil || event.Session.ID != "sess_1" {
+			t.Errorf("unexpected event %+v", event)
+		}
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("expected the handler error, got %v", err)
+	}
+}
+
+func TestConnRecvContext(t *testing.T) {
+	server := realtimeServer(t, func(_ *http.Request, conn *websocket.Conn) {
+		readEvent(t, conn)
+		writeEvent(t, conn, `{"type":"response.done","response":{"id":"resp_1","status":"cancelled"}}`)
+		_, _, _ = conn.ReadMessage()
+	})
+
+	config := realtime.DefaultConfig("token")
+	config.BaseURL = server.URL
+	conn, err := realtime.NewClientWithConfig(config).Connect(context.Background(), "gpt-realtime")
+	checks.NoError(t, err, "Connect error")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = conn.Recv(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// The session stays open after the canceled Recv.
+	checks.NoError(t, conn.Send(realtime.ResponseCancelEvent{}), "Send error")
+	event, err := conn.Recv(context.Background())
+	checks.NoError(t, err, "Recv error")
+	if event.Response == nil || event.Response.Status != "cancelled" {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	checks.NoError(t, conn.Close(), "Close error")
+	if _, err = conn.Recv(context.Background()); !errors.Is(err, realtime.ErrClosed) {
+		t.Errorf("expected ErrClosed from Recv, got %v", err)
+	}
+	if err = conn.Send(realtime.ResponseCancelEvent{}); !errors.Is(err, realtime.ErrClosed) {
+		t.Errorf("expected ErrClosed from Send, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/sashabaranov/go-openai/internal/websocket"
+)
+
+// ErrClosed is returned when using a connection that was closed with Close.
+var ErrClosed = errors.New("realtime: connection closed")
+
+// Conn is a Realtime API session. Events are read from the WebSocket in the
+// background and handed out by Recv, in order. Send may be called from any
+// goroutine, Recv and Run from one goroutine at a time.
+type Conn struct {
+	ws     *websocket.Conn
+	events chan ServerEvent
+	// done is closed when the connection ends, after err was set.
+	done chan struct{}
+	err  error
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newConn(ws *websocket.Conn) *Conn {
+	c := &Conn{
+		ws:     ws,
+		events: make(chan ServerEvent),
+		done:   make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+func (c *Conn) readLoop() {
+	defer close(c.done)
+	for {
+		_, message, err := c.ws.ReadMessage()
+		if err != nil {
+			c.err = c.readError(err)
+			return
+		}
+
+		var event ServerEvent
+		if err = json.Unmarshal(message, &event); err != nil {
+			c.err = err
+			_ = c.ws.Close(websocket.CloseProtocolError, "invalid event")
+			return
+		}
+		select {
+		case c.events <- event:
+		case <-c.closed:
+			c.err = ErrClosed
+			return
+		}
+	}
+}
+
+// readError maps the error that ended the connection: a normal closure by
+// the server ends the session with io.EOF.
+func (c *Conn) readError(err error) error {
+	select {
+	case <-c.closed:
+		return ErrClosed
+	default:
+	}
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) && closeErr.Code == websocket.CloseNormalClosure {
+		return io.EOF
+	}
+	return err
+}
+
+// Send sends a client event.
+func (c *Conn) Send(event ClientEvent) error {
+	data, err := marshalClientEvent(event)
+	if err != nil {
+		return err
+	}
+	err = c.ws.WriteMessage(websocket.TextMessage, data)
+	if errors.Is(err, websocket.ErrClosed) {
+		return ErrClosed
+	}
+	return err
+}
+
+// Recv returns the next server event. It returns io.EOF once the server has
+// closed the session normally, and the context's error when ctx is done
+// first, in which case the session stays open. Error events are returned as
+// events; see ServerEvent.Error.
+func (c *Conn) Recv(ctx context.Context) (ServerEvent, error) {
+	select {
+	case event := <-c.events:
+		return event, nil
+	case <-c.done:
+		return ServerEvent{}, c.err
+	case <-ctx.Done():
+		return ServerEvent{}, ctx.Err()
+	}
+}
+
+// Run passes every server event to handler until the server closes the
+// session, ctx is done or handler returns an error, and returns that error.
+// A normal closure by the server is not an error.
+func (c *Conn) Run(ctx context.Context, handler func(event ServerEvent) error) error {
+	for {
+		event, err := c.Recv(ctx)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = handler(event); err != nil {
+			return err
+		}
+	}
+}
+
+// Close ends the session.
+func (c *Conn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.ws.Close(websocket.CloseNormalClosure, "")
+	})
+	return err
+}
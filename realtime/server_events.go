@@ -0,0 +1,90 @@
+package realtime
+
+import "encoding/json"
+
+// ServerEventType identifies an event sent by the server.
+type ServerEventType string
+
+const (
+	ServerEventError                              ServerEventType = "error"
+	ServerEventSessionCreated                     ServerEventType = "session.created"
+	ServerEventSessionUpdated                     ServerEventType = "session.updated"
+	ServerEventConversationItemAdded              ServerEventType = "conversation.item.added"
+	ServerEventConversationItemDone               ServerEventType = "conversation.item.done"
+	ServerEventConversationItemTruncated          ServerEventType = "conversation.item.truncated"
+	ServerEventConversationItemDeleted            ServerEventType = "conversation.item.deleted"
+	ServerEventInputAudioBufferCommitted          ServerEventType = "input_audio_buffer.committed"
+	ServerEventInputAudioBufferCleared            ServerEventType = "input_audio_buffer.cleared"
+	ServerEventInputAudioBufferSpeechStarted      ServerEventType = "input_audio_buffer.speech_started"
+	ServerEventInputAudioBufferSpeechStopped      ServerEventType = "input_audio_buffer.speech_stopped"
+	ServerEventInputAudioTranscriptionDelta       ServerEventType = "conversation.item.input_audio_transcription.delta"
+	ServerEventInputAudioTranscriptionCompleted   ServerEventType = "conversation.item.input_audio_transcription.completed"
+	ServerEventInputAudioTranscriptionFailed      ServerEventType = "conversation.item.input_audio_transcription.failed"
+	ServerEventResponseCreated                    ServerEventType = "response.created"
+	ServerEventResponseDone                       ServerEventType = "response.done"
+	ServerEventResponseOutputItemAdded            ServerEventType = "response.output_item.added"
+	ServerEventResponseOutputItemDone             ServerEventType = "response.output_item.done"
+	ServerEventResponseContentPartAdded           ServerEventType = "response.content_part.added"
+	ServerEventResponseContentPartDone            ServerEventType = "response.content_part.done"
+	ServerEventResponseOutputTextDelta            ServerEventType = "response.output_text.delta"
+	ServerEventResponseOutputTextDone             ServerEventType = "response.output_text.done"
+	ServerEventResponseOutputAudioDelta           ServerEventType = "response.output_audio.delta"
+	ServerEventResponseOutputAudioDone            ServerEventType = "response.output_audio.done"
+	ServerEventResponseOutputAudioTranscriptDelta ServerEventType = "response.output_audio_transcript.delta"
+	ServerEventResponseOutputAudioTranscriptDone  ServerEventType = "response.output_audio_transcript.done"
+	ServerEventResponseFunctionCallArgumentsDelta ServerEventType = "response.function_call_arguments.delta"
+	ServerEventResponseFunctionCallArgumentsDone  ServerEventType = "response.function_call_arguments.done"
+	ServerEventRateLimitsUpdated                  ServerEventType = "rate_limits.updated"
+)
+
+// ServerEvent is an event sent by the server. Type identifies the event and
+// the fields it sets; Raw holds the original JSON, for the fields not
+// covered here.
+type ServerEvent struct {
+	Type    ServerEventType `json:"type"`
+	EventID string          `json:"event_id"`
+
+	// Session is set by session.created and session.updated.
+	Session *Session `json:"session,omitempty"`
+	// Item is set by the conversation.item.* and response.output_item.*
+	// events that carry a whole item.
+	Item           *Item  `json:"item,omitempty"`
+	PreviousItemID string `json:"previous_item_id,omitempty"`
+	// Response is set by response.created and response.done.
+	Response *Response `json:"response,omitempty"`
+
+	ResponseID   string       `json:"response_id,omitempty"`
+	ItemID       string       `json:"item_id,omitempty"`
+	OutputIndex  int          `json:"output_index,omitempty"`
+	ContentIndex int          `json:"content_index,omitempty"`
+	Part         *ItemContent `json:"part,omitempty"`
+
+	// Delta is the text, base64-encoded audio, transcript or arguments added
+	// by a *.delta event.
+	Delta      string `json:"delta,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+	Arguments  string `json:"arguments,omitempty"`
+	CallID     string `json:"call_id,omitempty"`
+	Name       string `json:"name,omitempty"`
+
+	AudioStartMS int `json:"audio_start_ms,omitempty"`
+	AudioEndMS   int `json:"audio_end_ms,omitempty"`
+
+	// Error is set by error and input_audio_transcription.failed events.
+	Error      *Error      `json:"error,omitempty"`
+	RateLimits []RateLimit `json:"rate_limits,omitempty"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+func (e *ServerEvent) UnmarshalJSON(data []byte) error {
+	type serverEvent ServerEvent
+	var event serverEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+	*e = ServerEvent(event)
+	e.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
@@ -0,0 +1,253 @@
+package realtime
+
+// Modality is a kind of output a response may contain.
+type Modality string
+
+const (
+	ModalityText  Modality = "text"
+	ModalityAudio Modality = "audio"
+)
+
+// Voices for audio output.
+const (
+	VoiceAlloy   = "alloy"
+	VoiceAsh     = "ash"
+	VoiceBallad  = "ballad"
+	VoiceCedar   = "cedar"
+	VoiceCoral   = "coral"
+	VoiceEcho    = "echo"
+	VoiceMarin   = "marin"
+	VoiceSage    = "sage"
+	VoiceShimmer = "shimmer"
+	VoiceVerse   = "verse"
+)
+
+// Session configures a session. It is sent with session.update, and received
+// with session.created and session.updated events. Only the fields that are
+// set are updated.
+type Session struct {
+	// Type is "realtime" for speech-to-speech sessions, or "transcription".
+	Type             string     `json:"type,omitempty"`
+	ID               string     `json:"id,omitempty"`
+	Object           string     `json:"object,omitempty"`
+	Model            string     `json:"model,omitempty"`
+	OutputModalities []Modality `json:"output_modalities,omitempty"`
+	Instructions     string     `json:"instructions,omitempty"`
+	Audio            *Audio     `json:"audio,omitempty"`
+	Tools            []Tool     `json:"tools,omitempty"`
+	// ToolChoice is "auto", "none", "required" or a ToolChoice.
+	ToolChoice any `json:"tool_choice,omitempty"`
+	// MaxOutputTokens is an integer, or "inf".
+	MaxOutputTokens any   `json:"max_output_tokens,omitempty"`
+	ExpiresAt       int64 `json:"expires_at,omitempty"`
+}
+
+// Audio configures the audio input and output of a session.
+type Audio struct {
+	Input  *AudioInput  `json:"input,omitempty"`
+	Output *AudioOutput `json:"output,omitempty"`
+}
+
+// AudioInput configures the input audio buffer.
+type AudioInput struct {
+	Format         *AudioFormat         `json:"format,omitempty"`
+	Transcription  *AudioTranscription  `json:"transcription,omitempty"`
+	TurnDetection  *TurnDetection       `json:"turn_detection,omitempty"`
+	NoiseReduction *AudioNoiseReduction `json:"noise_reduction,omitempty"`
+}
+
+// AudioOutput configures the audio generated by the model.
+type AudioOutput struct {
+	Format *AudioFormat `json:"format,omitempty"`
+	Voice  string       `json:"voice,omitempty"`
+	Speed  float64      `json:"speed,omitempty"`
+}
+
+// AudioFormat is an audio encoding, e.g. PCM16 at 24kHz.
+type AudioFormat struct {
+	// Type is "audio/pcm", "audio/pcmu" or "audio/pcma".
+	Type string `json:"type"`
+	// Rate is the sample rate, which is 24000 for PCM16.
+	Rate int `json:"rate,omitempty"`
+}
+
+// PCM16Format returns the format of the audio encoded by EncodePCM16.
+func PCM16Format() *AudioFormat {
+	return &AudioFormat{Type: "audio/pcm", Rate: PCM16SampleRate}
+}
+
+// AudioTranscription enables the transcription of input audio.
+type AudioTranscription struct {
+	Model    string `json:"model,omitempty"`
+	Language string `json:"language,omitempty"`
+	Prompt   string `json:"prompt,omitempty"`
+}
+
+// TurnDetection configures voice activity detection.
+type TurnDetection struct {
+	// Type is "server_vad" or "semantic_vad".
+	Type              string   `json:"type"`
+	Threshold         *float64 `json:"threshold,omitempty"`
+	PrefixPaddingMS   int      `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMS int      `json:"silence_duration_ms,omitempty"`
+	IdleTimeoutMS     int      `json:"idle_timeout_ms,omitempty"`
+	// Eagerness is "low", "medium", "high" or "auto", for semantic_vad.
+	Eagerness         string `json:"eagerness,omitempty"`
+	CreateResponse    *bool  `json:"create_response,omitempty"`
+	InterruptResponse *bool  `json:"interrupt_response,omitempty"`
+}
+
+// AudioNoiseReduction filters the input audio.
+type AudioNoiseReduction struct {
+	// Type is "near_field" or "far_field".
+	Type string `json:"type"`
+}
+
+// Tool is a function the model may call. Parameters is a JSON schema, e.g. a
+// jsonschema.Definition.
+type Tool struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+// NewFunctionTool creates a function tool.
+func NewFunctionTool(name, description string, parameters any) Tool {
+	return Tool{Type: "function", Name: name, Description: description, Parameters: parameters}
+}
+
+// ToolChoice forces the model to call a specific function.
+type ToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// ItemType identifies the variant of a conversation item.
+type ItemType string
+
+const (
+	ItemTypeMessage            ItemType = "message"
+	ItemTypeFunctionCall       ItemType = "function_call"
+	ItemTypeFunctionCallOutput ItemType = "function_call_output"
+)
+
+// Item is an item of the conversation: a message, a function call or the
+// output of a function call.
+type Item struct {
+	ID     string   `json:"id,omitempty"`
+	Type   ItemType `json:"type"`
+	Object string   `json:"object,omitempty"`
+	Status string   `json:"status,omitempty"`
+	// Role is "user", "assistant" or "system", for messages.
+	Role    string        `json:"role,omitempty"`
+	Content []ItemContent `json:"content,omitempty"`
+	// CallID, Name and Arguments are set for function calls, and CallID and
+	// Output for their outputs.
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	Output    string `json:"output,omitempty"`
+}
+
+// ContentType identifies the variant of a message content part.
+type ContentType string
+
+const (
+	ContentTypeInputText   ContentType = "input_text"
+	ContentTypeInputAudio  ContentType = "input_audio"
+	ContentTypeOutputText  ContentType = "output_text"
+	ContentTypeOutputAudio ContentType = "output_audio"
+)
+
+// ItemContent is a content part of a message. Audio is base64-encoded.
+type ItemContent struct {
+	Type       ContentType `json:"type"`
+	Text       string      `json:"text,omitempty"`
+	Audio      string      `json:"audio,omitempty"`
+	Transcript string      `json:"transcript,omitempty"`
+}
+
+// NewUserTextMessage creates a user message with text content.
+func NewUserTextMessage(text string) Item {
+	return Item{
+		Type:    ItemTypeMessage,
+		Role:    "user",
+		Content: []ItemContent{{Type: ContentTypeInputText, Text: text}},
+	}
+}
+
+// NewFunctionCallOutput creates the output of the function call callID.
+func NewFunctionCallOutput(callID, output string) Item {
+	return Item{Type: ItemTypeFunctionCallOutput, CallID: callID, Output: output}
+}
+
+// Response is a response of the model, as received with response.created and
+// response.done events.
+type Response struct {
+	ID     string `json:"id"`
+	Object string `json:"object,omitempty"`
+	// Status is "in_progress", "completed", "cancelled", "failed" or "incomplete".
+	Status           string         `json:"status"`
+	StatusDetails    *StatusDetails `json:"status_details,omitempty"`
+	Output           []Item         `json:"output,omitempty"`
+	OutputModalities []Modality     `json:"output_modalities,omitempty"`
+	ConversationID   string         `json:"conversation_id,omitempty"`
+	Metadata         map[string]any `json:"metadata,omitempty"`
+	Usage            *ResponseUsage `json:"usage,omitempty"`
+}
+
+// StatusDetails explains why a response did not complete.
+type StatusDetails struct {
+	Type   string `json:"type,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Error  *Error `json:"error,omitempty"`
+}
+
+// ResponseUsage reports the token use of a response.
+type ResponseUsage struct {
+	TotalTokens  int `json:"total_tokens"`
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// ResponseConfig overrides the session configuration for a single response.
+type ResponseConfig struct {
+	OutputModalities []Modality `json:"output_modalities,omitempty"`
+	Instructions     string     `json:"instructions,omitempty"`
+	Audio            *Audio     `json:"audio,omitempty"`
+	Tools            []Tool     `json:"tools,omitempty"`
+	ToolChoice       any        `json:"tool_choice,omitempty"`
+	MaxOutputTokens  any        `json:"max_output_tokens,omitempty"`
+	// Conversation is "auto" to add the response to the conversation, or
+	// "none" to generate it out of band.
+	Conversation string         `json:"conversation,omitempty"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+	// Input replaces the conversation as the context of the response.
+	Input []Item `json:"input,omitempty"`
+}
+
+// Error is an error reported by the server in an error event.
+type Error struct {
+	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
+	// EventID is the ID of the client event that caused the error.
+	EventID string `json:"event_id,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return e.Code + ": " + e.Message
+}
+
+// RateLimit is a rate limit reported by a rate_limits.updated event.
+type RateLimit struct {
+	Name         string  `json:"name"`
+	Limit        int     `json:"limit"`
+	Remaining    int     `json:"remaining"`
+	ResetSeconds float64 `json:"reset_seconds"`
+}
@@ -225,6 +225,13 @@ type ResponseError struct {
 	Message string `json:"message"`
 }
 
+func (e *ResponseError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
 // ResponseIncompleteDetails explains why a response did not complete.
 type ResponseIncompleteDetails struct {
 	Reason string `json:"reason,omitempty"`
@@ -257,14 +264,15 @@ type ResponseOutputTokensDetails struct {
 
 // ResponseAnnotation describes a citation or file annotation in output text.
 type ResponseAnnotation struct {
-	Type       string `json:"type"`
-	FileID     string `json:"file_id,omitempty"`
-	Filename   string `json:"filename,omitempty"`
-	Index      int    `json:"index,omitempty"`
-	StartIndex int    `json:"start_index,omitempty"`
-	EndIndex   int    `json:"end_index,omitempty"`
-	URL        string `json:"url,omitempty"`
-	Title      string `json:"title,omitempty"`
+	Type        string `json:"type"`
+	FileID      string `json:"file_id,omitempty"`
+	Filename    string `json:"filename,omitempty"`
+	Index       int    `json:"index,omitempty"`
+	StartIndex  int    `json:"start_index,omitempty"`
+	EndIndex    int    `json:"end_index,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Title       string `json:"title,omitempty"`
+	ContainerID string `json:"container_id,omitempty"`
 }
 
 // ResponseLogprob contains token log-probability information.
@@ -290,8 +298,9 @@ type ResponseSummaryPart struct {
 	Text string `json:"text"`
 }
 
-// ResponseOutputItem contains the common fields shared by response output item variants.
-// The top-level Output field remains []any so new variants can be consumed without a library release.
+// ResponseOutputItem contains the common fields shared by response output item
+// variants, as reported by output item stream events. See ResponseOutput for
+// the typed variants.
 type ResponseOutputItem struct {
 	ID        string                  `json:"id,omitempty"`
 	Type      string                  `json:"type"`
@@ -308,6 +317,8 @@ type ResponseOutputItem struct {
 }
 
 // CreateResponseResponse represents a response returned by the Responses API.
+// Output holds typed items; it was []any in earlier releases, and code that
+// inspected items as map[string]any can decode each item's Raw instead.
 type CreateResponseResponse struct {
 	ID                   string                      `json:"id"`
 	Object               string                      `json:"object"`
@@ -322,7 +333,7 @@ type CreateResponseResponse struct {
 	Metadata             map[string]any              `json:"metadata,omitempty"`
 	Model                string                      `json:"model"`
 	Moderation           any                         `json:"moderation,omitempty"`
-	Output               []any                       `json:"output"`
+	Output               []ResponseOutput            `json:"output"`
 	OutputText           string                      `json:"output_text,omitempty"`
 	ParallelToolCalls    bool                        `json:"parallel_tool_calls,omitempty"`
 	PreviousResponseID   string                      `json:"previous_response_id,omitempty"`
@@ -357,16 +368,8 @@ func (r CreateResponseResponse) GetOutputText() string {
 	}
 
 	var output strings.Builder
-	for _, rawItem := range r.Output {
-		data, err := json.Marshal(rawItem)
-		if err != nil {
-			continue
-		}
-		var item ResponseOutputItem
-		if err = json.Unmarshal(data, &item); err != nil {
-			continue
-		}
-		for _, content := range item.Content {
+	for _, message := range r.Messages() {
+		for _, content := range message.Content {
 			if content.Type == "output_text" {
 				output.WriteString(content.Text)
 			}
@@ -375,11 +378,27 @@ func (r CreateResponseResponse) GetOutputText() string {
 	return output.String()
 }
 
-// RetrieveResponseOptions controls optional data returned by RetrieveResponse.
+// RetrieveResponseOptions controls optional data returned by RetrieveResponse
+// and RetrieveResponseStream.
 type RetrieveResponseOptions struct {
 	Include            []ResponseInclude
 	IncludeObfuscation *bool
-	StartingAfter      *int
+	// StartingAfter makes a stream start after the event with this sequence number.
+	StartingAfter *int
+}
+
+func (o RetrieveResponseOptions) values() url.Values {
+	values := url.Values{}
+	for _, include := range o.Include {
+		values.Add("include", string(include))
+	}
+	if o.IncludeObfuscation != nil {
+		values.Set("include_obfuscation", strconv.FormatBool(*o.IncludeObfuscation))
+	}
+	if o.StartingAfter != nil {
+		values.Set("starting_after", strconv.Itoa(*o.StartingAfter))
+	}
+	return values
 }
 
 // ResponseInputItemsListOptions controls pagination for ListResponseInputItems.
@@ -446,8 +465,12 @@ func (c *Client) CreateResponse(
 	if request.Stream {
 		return response, ErrResponseStreamNotSupported
 	}
+	if err = validateResponseInput(request.Input); err != nil {
+		return response, err
+	}
 
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(responsesSuffix), withBody(request))
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(responsesSuffix), withBody(request),
+		withOperation("CreateResponse", request))
 	if err != nil {
 		return response, err
 	}
@@ -461,21 +484,13 @@ func (c *Client) RetrieveResponse(
 	responseID string,
 	options ...RetrieveResponseOptions,
 ) (response CreateResponseResponse, err error) {
-	values := url.Values{}
+	var opts RetrieveResponseOptions
 	if len(options) > 0 {
-		for _, include := range options[0].Include {
-			values.Add("include", string(include))
-		}
-		if options[0].IncludeObfuscation != nil {
-			values.Set("include_obfuscation", strconv.FormatBool(*options[0].IncludeObfuscation))
-		}
-		if options[0].StartingAfter != nil {
-			values.Set("starting_after", strconv.Itoa(*options[0].StartingAfter))
-		}
+		opts = options[0]
 	}
 
-	urlSuffix := responseResourceSuffix(responseID, "", values)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	urlSuffix := responseResourceSuffix(responseID, "", opts.values())
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("RetrieveResponse", nil))
 	if err != nil {
 		return response, err
 	}
@@ -495,7 +510,7 @@ func (c *Client) GetResponse(
 // DeleteResponse deletes a stored response.
 func (c *Client) DeleteResponse(ctx context.Context, responseID string) (response ResponseDeleteResponse, err error) {
 	urlSuffix := responseResourceSuffix(responseID, "", nil)
-	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(urlSuffix))
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(urlSuffix), withOperation("DeleteResponse", nil))
 	if err != nil {
 		return response, err
 	}
@@ -506,7 +521,7 @@ func (c *Client) DeleteResponse(ctx context.Context, responseID string) (respons
 // CancelResponse cancels a background response.
 func (c *Client) CancelResponse(ctx context.Context, responseID string) (response CreateResponseResponse, err error) {
 	urlSuffix := responseResourceSuffix(responseID, "cancel", nil)
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix))
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withOperation("CancelResponse", nil))
 	if err != nil {
 		return response, err
 	}
@@ -537,7 +552,7 @@ func (c *Client) ListResponseInputItems(
 	}
 
 	urlSuffix := responseResourceSuffix(responseID, "input_items", values)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("ListResponseInputItems", nil))
 	if err != nil {
 		return response, err
 	}
@@ -552,11 +567,15 @@ func (c *Client) CountResponseInputTokens(
 ) (response ResponseInputTokensResponse, err error) {
 	request.Stream = false
 	request.StreamOptions = nil
+	if err = validateResponseInput(request.Input); err != nil {
+		return response, err
+	}
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
 		c.fullURL(responsesSuffix+"/input_tokens"),
 		withBody(request),
+		withOperation("CountResponseInputTokens", request),
 	)
 	if err != nil {
 		return response, err
@@ -572,11 +591,15 @@ func (c *Client) CompactResponse(
 ) (response ResponseCompaction, err error) {
 	request.Stream = false
 	request.StreamOptions = nil
+	if err = validateResponseInput(request.Input); err != nil {
+		return response, err
+	}
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
 		c.fullURL(responsesSuffix+"/compact"),
 		withBody(request),
+		withOperation("CompactResponse", request),
 	)
 	if err != nil {
 		return response, err
@@ -0,0 +1,516 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidResponseInput is wrapped by the validation errors of response input items.
+var ErrInvalidResponseInput = errors.New("invalid response input item")
+
+const (
+	responseInputTypeMessage              = "message"
+	responseInputTypeInputText            = "input_text"
+	responseInputTypeInputImage           = "input_image"
+	responseInputTypeInputFile            = "input_file"
+	responseInputTypeOutputText           = "output_text"
+	responseInputTypeFunctionCallOutput   = "function_call_output"
+	responseInputTypeCustomToolCallOutput = "custom_tool_call_output"
+	responseInputTypeComputerCallOutput   = "computer_call_output"
+	responseInputTypeLocalShellCallOutput = "local_shell_call_output"
+	responseInputTypeMCPApprovalResponse  = "mcp_approval_response"
+	responseInputTypeItemReference        = "item_reference"
+	responseInputTypeComputerScreenshot   = "computer_screenshot"
+	responseInputTypeRefusal              = "refusal"
+)
+
+// ResponseInputItem is an item of structured response input. A
+// []ResponseInputItem can be used as CreateResponseRequest.Input, in which
+// case every item is validated before the request is sent.
+type ResponseInputItem interface {
+	Validate() error
+}
+
+// ResponseInputContent is a content part of a structured input message.
+type ResponseInputContent interface {
+	Validate() error
+}
+
+func invalidResponseInput(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidResponseInput, fmt.Sprintf(format, args...))
+}
+
+// NewResponseInputText creates a text content part.
+func NewResponseInputText(text string) ResponseInputText {
+	return ResponseInputText{Type: responseInputTypeInputText, Text: text}
+}
+
+// NewResponseInputImageURL creates an image content part from a URL, which may
+// be a base64 data URL. Detail is one of "low", "high" or "auto".
+func NewResponseInputImageURL(imageURL, detail string) ResponseInputImage {
+	return ResponseInputImage{Type: responseInputTypeInputImage, ImageURL: imageURL, Detail: detail}
+}
+
+// NewResponseInputImageFile creates an image content part from an uploaded file.
+func NewResponseInputImageFile(fileID, detail string) ResponseInputImage {
+	return ResponseInputImage{Type: responseInputTypeInputImage, FileID: fileID, Detail: detail}
+}
+
+// NewResponseInputFileID creates a file content part from an uploaded file.
+func NewResponseInputFileID(fileID string) ResponseInputFile {
+	return ResponseInputFile{Type: responseInputTypeInputFile, FileID: fileID}
+}
+
+// NewResponseInputFileURL creates a file content part from a URL.
+func NewResponseInputFileURL(fileURL string) ResponseInputFile {
+	return ResponseInputFile{Type: responseInputTypeInputFile, FileURL: fileURL}
+}
+
+// NewResponseInputFileData creates a file content part from base64-encoded data.
+func NewResponseInputFileData(filename, fileData string) ResponseInputFile {
+	return ResponseInputFile{Type: responseInputTypeInputFile, Filename: filename, FileData: fileData}
+}
+
+// Validate checks that the text content part is well formed.
+func (t ResponseInputText) Validate() error {
+	if t.Type != responseInputTypeInputText {
+		return invalidResponseInput("text content type must be %q, got %q", responseInputTypeInputText, t.Type)
+	}
+	return nil
+}
+
+// Validate checks that the image content part references exactly one image.
+func (i ResponseInputImage) Validate() error {
+	if i.Type != responseInputTypeInputImage {
+		return invalidResponseInput("image content type must be %q, got %q", responseInputTypeInputImage, i.Type)
+	}
+	if (i.ImageURL == "") == (i.FileID == "") {
+		return invalidResponseInput("image content requires exactly one of image_url and file_id")
+	}
+	return nil
+}
+
+// Validate checks that the file content part references exactly one file.
+func (f ResponseInputFile) Validate() error {
+	if f.Type != responseInputTypeInputFile {
+		return invalidResponseInput("file content type must be %q, got %q", responseInputTypeInputFile, f.Type)
+	}
+	sources := 0
+	for _, source := range []string{f.FileData, f.FileID, f.FileURL} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return invalidResponseInput("file content requires exactly one of file_data, file_id and file_url")
+	}
+	if f.FileData != "" && f.Filename == "" {
+		return invalidResponseInput("file content with file_data requires a filename")
+	}
+	return nil
+}
+
+// Validate checks that the output content part, used in assistant messages,
+// is output text or a refusal.
+func (c ResponseOutputContent) Validate() error {
+	switch c.Type {
+	case responseInputTypeOutputText, responseInputTypeRefusal:
+		return nil
+	default:
+		return invalidResponseInput("assistant content type must be %q or %q, got %q",
+			responseInputTypeOutputText, responseInputTypeRefusal, c.Type)
+	}
+}
+
+// Validate checks the role and content of the message.
+func (m ResponseInputMessage) Validate() error {
+	if m.Type != "" && m.Type != responseInputTypeMessage {
+		return invalidResponseInput("message type must be %q, got %q", responseInputTypeMessage, m.Type)
+	}
+	switch m.Role {
+	case ChatMessageRoleUser, ChatMessageRoleSystem, ChatMessageRoleDeveloper, ChatMessageRoleAssistant:
+	default:
+		return invalidResponseInput("unsupported message role %q", m.Role)
+	}
+
+	switch content := m.Content.(type) {
+	case string:
+		if content == "" {
+			return invalidResponseInput("%s message has no content", m.Role)
+		}
+	case []ResponseInputContent:
+		if len(content) == 0 {
+			return invalidResponseInput("%s message has no content", m.Role)
+		}
+		for i, part := range content {
+			if err := validateMessageContent(m.Role, part); err != nil {
+				return fmt.Errorf("content part %d: %w", i, err)
+			}
+		}
+	case nil:
+		return invalidResponseInput("%s message has no content", m.Role)
+	}
+	return nil
+}
+
+// validateMessageContent checks that part suits a message of role: assistant
+// messages hold output text and refusals, other messages input parts.
+func validateMessageContent(role string, part ResponseInputContent) error {
+	var isOutput bool
+	switch part.(type) {
+	case ResponseOutputContent, *ResponseOutputContent:
+		isOutput = true
+	}
+	if role == ChatMessageRoleAssistant && !isOutput {
+		return invalidResponseInput("assistant content must be %q or %q parts, got %T",
+			responseInputTypeOutputText, responseInputTypeRefusal, part)
+	}
+	if role != ChatMessageRoleAssistant && isOutput {
+		return invalidResponseInput("%s content must be input parts, got %T", role, part)
+	}
+	return part.Validate()
+}
+
+// Validate checks that the output references a call.
+func (o ResponseFunctionCallOutput) Validate() error {
+	if o.Type != responseInputTypeFunctionCallOutput {
+		return invalidResponseInput("function call output type must be %q, got %q",
+			responseInputTypeFunctionCallOutput, o.Type)
+	}
+	if o.CallID == "" {
+		return invalidResponseInput("function call output requires a call_id")
+	}
+	if o.Output == nil {
+		return invalidResponseInput("function call output %s has no output", o.CallID)
+	}
+	return nil
+}
+
+// Validate checks that the function call can be passed back as input.
+func (c ResponseFunctionToolCall) Validate() error {
+	if c.Type != ResponseOutputTypeFunctionCall {
+		return invalidResponseInput("function call type must be %q, got %q", ResponseOutputTypeFunctionCall, c.Type)
+	}
+	if c.CallID == "" || c.Name == "" {
+		return invalidResponseInput("function call requires a call_id and a name")
+	}
+	return nil
+}
+
+// Validate checks that the reasoning item can be passed back as input. Items
+// of responses that are not stored can only be passed back with their
+// encrypted content.
+func (r ResponseReasoningItem) Validate() error {
+	if r.Type != ResponseOutputTypeReasoning {
+		return invalidResponseInput("reasoning type must be %q, got %q", ResponseOutputTypeReasoning, r.Type)
+	}
+	if r.ID == "" && r.EncryptedContent == "" {
+		return invalidResponseInput("reasoning item requires an id or encrypted_content")
+	}
+	if r.Summary == nil {
+		return invalidResponseInput("reasoning item %s requires a summary, which may be empty", r.ID)
+	}
+	return nil
+}
+
+// Validate checks that the output item can be passed back as input. Items of
+// unknown types are passed through unchecked.
+func (o ResponseOutput) Validate() error {
+	if o.Type == "" {
+		return invalidResponseInput("output item has no type")
+	}
+	if item, ok := o.setVariant().(ResponseInputItem); ok {
+		return item.Validate()
+	}
+	return nil
+}
+
+// ResponseItemReference refers to an item of a previous response or conversation by ID.
+type ResponseItemReference struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Validate checks that the reference has an ID.
+func (r ResponseItemReference) Validate() error {
+	if r.Type != responseInputTypeItemReference {
+		return invalidResponseInput("item reference type must be %q, got %q", responseInputTypeItemReference, r.Type)
+	}
+	if r.ID == "" {
+		return invalidResponseInput("item reference requires an id")
+	}
+	return nil
+}
+
+// ResponseCustomToolCallOutput supplies the result of a prior custom tool call.
+type ResponseCustomToolCallOutput struct {
+	Type   string `json:"type"`
+	ID     string `json:"id,omitempty"`
+	CallID string `json:"call_id"`
+	Output any    `json:"output"`
+}
+
+// Validate checks that the output references a call.
+func (o ResponseCustomToolCallOutput) Validate() error {
+	if o.Type != responseInputTypeCustomToolCallOutput {
+		return invalidResponseInput("custom tool call output type must be %q, got %q",
+			responseInputTypeCustomToolCallOutput, o.Type)
+	}
+	if o.CallID == "" {
+		return invalidResponseInput("custom tool call output requires a call_id")
+	}
+	if o.Output == nil {
+		return invalidResponseInput("custom tool call output %s has no output", o.CallID)
+	}
+	return nil
+}
+
+// ResponseComputerScreenshot is the screenshot returned for a computer call.
+type ResponseComputerScreenshot struct {
+	Type     string `json:"type"`
+	ImageURL string `json:"image_url,omitempty"`
+	FileID   string `json:"file_id,omitempty"`
+}
+
+// NewResponseComputerScreenshot creates a screenshot from an image URL, which
+// is typically a base64 data URL.
+func NewResponseComputerScreenshot(imageURL string) ResponseComputerScreenshot {
+	return ResponseComputerScreenshot{Type: responseInputTypeComputerScreenshot, ImageURL: imageURL}
+}
+
+// ResponseComputerCallOutput supplies the result of a prior computer call.
+// AcknowledgedSafetyChecks lists the pending safety checks of the call that
+// the user has acknowledged.
+type ResponseComputerCallOutput struct {
+	Type                     string                     `json:"type"`
+	ID                       string                     `json:"id,omitempty"`
+	CallID                   string                     `json:"call_id"`
+	Output                   ResponseComputerScreenshot `json:"output"`
+	AcknowledgedSafetyChecks []ResponseSafetyCheck      `json:"acknowledged_safety_checks,omitempty"`
+	Status                   string                     `json:"status,omitempty"`
+}
+
+// Validate checks that the output references a call and contains one screenshot.
+func (o ResponseComputerCallOutput) Validate() error {
+	if o.Type != responseInputTypeComputerCallOutput {
+		return invalidResponseInput("computer call output type must be %q, got %q",
+			responseInputTypeComputerCallOutput, o.Type)
+	}
+	if o.CallID == "" {
+		return invalidResponseInput("computer call output requires a call_id")
+	}
+	if o.Output.Type != responseInputTypeComputerScreenshot {
+		return invalidResponseInput("computer call output must be a %q", responseInputTypeComputerScreenshot)
+	}
+	if (o.Output.ImageURL == "") == (o.Output.FileID == "") {
+		return invalidResponseInput("computer screenshot requires exactly one of image_url and file_id")
+	}
+	for _, check := range o.AcknowledgedSafetyChecks {
+		if check.ID == "" {
+			return invalidResponseInput("acknowledged safety check requires an id")
+		}
+	}
+	return nil
+}
+
+// ResponseLocalShellCallOutput supplies the result of a prior local shell call.
+type ResponseLocalShellCallOutput struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Output string `json:"output"`
+	Status string `json:"status,omitempty"`
+}
+
+// Validate checks that the output references a call.
+func (o ResponseLocalShellCallOutput) Validate() error {
+	if o.Type != responseInputTypeLocalShellCallOutput {
+		return invalidResponseInput("local shell call output type must be %q, got %q",
+			responseInputTypeLocalShellCallOutput, o.Type)
+	}
+	if o.ID == "" {
+		return invalidResponseInput("local shell call output requires the id of the call")
+	}
+	return nil
+}
+
+// ResponseMCPApprovalResponse answers an MCP approval request.
+type ResponseMCPApprovalResponse struct {
+	Type              string `json:"type"`
+	ID                string `json:"id,omitempty"`
+	ApprovalRequestID string `json:"approval_request_id"`
+	Approve           bool   `json:"approve"`
+	Reason            string `json:"reason,omitempty"`
+}
+
+// Validate checks that the response references an approval request.
+func (r ResponseMCPApprovalResponse) Validate() error {
+	if r.Type != responseInputTypeMCPApprovalResponse {
+		return invalidResponseInput("MCP approval response type must be %q, got %q",
+			responseInputTypeMCPApprovalResponse, r.Type)
+	}
+	if r.ApprovalRequestID == "" {
+		return invalidResponseInput("MCP approval response requires an approval_request_id")
+	}
+	return nil
+}
+
+// ResponseInputBuilder assembles structured response input. Items are
+// validated by Build, which reports the first invalid item.
+//
+//	input, err := openai.NewResponseInputBuilder().
+//		Developer("Answer in French.").
+//		User("What is the weather in Paris?").
+//		Output(previous.Output...).
+//		FunctionCallOutput("call_1", `{"temperature":21}`).
+//		Build()
+type ResponseInputBuilder struct {
+	items []ResponseInputItem
+}
+
+// NewResponseInputBuilder creates an empty ResponseInputBuilder.
+func NewResponseInputBuilder() *ResponseInputBuilder {
+	return &ResponseInputBuilder{}
+}
+
+// Item appends arbitrary input items.
+func (b *ResponseInputBuilder) Item(items ...ResponseInputItem) *ResponseInputBuilder {
+	b.items = append(b.items, items...)
+	return b
+}
+
+// Message appends a message with the given role and content parts.
+func (b *ResponseInputBuilder) Message(role string, content ...ResponseInputContent) *ResponseInputBuilder {
+	return b.Item(ResponseInputMessage{Type: responseInputTypeMessage, Role: role, Content: content})
+}
+
+func (b *ResponseInputBuilder) textMessage(role, text string) *ResponseInputBuilder {
+	return b.Item(ResponseInputMessage{Type: responseInputTypeMessage, Role: role, Content: text})
+}
+
+// User appends a user message with text content.
+func (b *ResponseInputBuilder) User(text string) *ResponseInputBuilder {
+	return b.textMessage(ChatMessageRoleUser, text)
+}
+
+// System appends a system message with text content.
+func (b *ResponseInputBuilder) System(text string) *ResponseInputBuilder {
+	return b.textMessage(ChatMessageRoleSystem, text)
+}
+
+// Developer appends a developer message with text content.
+func (b *ResponseInputBuilder) Developer(text string) *ResponseInputBuilder {
+	return b.textMessage(ChatMessageRoleDeveloper, text)
+}
+
+// Assistant appends an assistant message with text content, such as an earlier model reply.
+func (b *ResponseInputBuilder) Assistant(text string) *ResponseInputBuilder {
+	return b.textMessage(ChatMessageRoleAssistant, text)
+}
+
+// Output appends output items of an earlier response, which is how context is
+// carried over when the response was not stored.
+func (b *ResponseInputBuilder) Output(items ...ResponseOutput) *ResponseInputBuilder {
+	for _, item := range items {
+		b.items = append(b.items, item)
+	}
+	return b
+}
+
+// ItemReference appends a reference to a stored item.
+func (b *ResponseInputBuilder) ItemReference(id string) *ResponseInputBuilder {
+	return b.Item(ResponseItemReference{Type: responseInputTypeItemReference, ID: id})
+}
+
+// Reasoning appends a reasoning item, typically one returned with encrypted content.
+func (b *ResponseInputBuilder) Reasoning(item ResponseReasoningItem) *ResponseInputBuilder {
+	item.Type = ResponseOutputTypeReasoning
+	if item.Summary == nil {
+		item.Summary = []ResponseSummaryPart{}
+	}
+	return b.Item(item)
+}
+
+// FunctionCall appends a function call, e.g. one made by an earlier response.
+func (b *ResponseInputBuilder) FunctionCall(call ResponseFunctionToolCall) *ResponseInputBuilder {
+	call.Type = ResponseOutputTypeFunctionCall
+	return b.Item(call)
+}
+
+// FunctionCallOutput appends the result of a function call. Output is
+// typically a string, or a slice of content parts.
+func (b *ResponseInputBuilder) FunctionCallOutput(callID string, output any) *ResponseInputBuilder {
+	return b.Item(ResponseFunctionCallOutput{Type: responseInputTypeFunctionCallOutput, CallID: callID, Output: output})
+}
+
+// CustomToolCallOutput appends the result of a custom tool call.
+func (b *ResponseInputBuilder) CustomToolCallOutput(callID string, output any) *ResponseInputBuilder {
+	return b.Item(ResponseCustomToolCallOutput{
+		Type:   responseInputTypeCustomToolCallOutput,
+		CallID: callID,
+		Output: output,
+	})
+}
+
+// ComputerCallOutput appends the screenshot taken after a computer call,
+// acknowledging the given safety checks.
+func (b *ResponseInputBuilder) ComputerCallOutput(
+	callID string,
+	screenshot ResponseComputerScreenshot,
+	acknowledgedSafetyChecks ...ResponseSafetyCheck,
+) *ResponseInputBuilder {
+	return b.Item(ResponseComputerCallOutput{
+		Type:                     responseInputTypeComputerCallOutput,
+		CallID:                   callID,
+		Output:                   screenshot,
+		AcknowledgedSafetyChecks: acknowledgedSafetyChecks,
+	})
+}
+
+// LocalShellCallOutput appends the output of a local shell call.
+func (b *ResponseInputBuilder) LocalShellCallOutput(callID, output string) *ResponseInputBuilder {
+	return b.Item(ResponseLocalShellCallOutput{Type: responseInputTypeLocalShellCallOutput, ID: callID, Output: output})
+}
+
+// MCPApprovalResponse appends the answer to an MCP approval request.
+func (b *ResponseInputBuilder) MCPApprovalResponse(
+	approvalRequestID string,
+	approve bool,
+	reason string,
+) *ResponseInputBuilder {
+	return b.Item(ResponseMCPApprovalResponse{
+		Type:              responseInputTypeMCPApprovalResponse,
+		ApprovalRequestID: approvalRequestID,
+		Approve:           approve,
+		Reason:            reason,
+	})
+}
+
+// Build validates the items and returns them for use as CreateResponseRequest.Input.
+func (b *ResponseInputBuilder) Build() ([]ResponseInputItem, error) {
+	if err := validateResponseInputItems(b.items); err != nil {
+		return nil, err
+	}
+	items := make([]ResponseInputItem, len(b.items))
+	copy(items, b.items)
+	return items, nil
+}
+
+func validateResponseInputItems(items []ResponseInputItem) error {
+	for i, item := range items {
+		if item == nil {
+			return fmt.Errorf("input item %d: %w", i, invalidResponseInput("item is nil"))
+		}
+		if err := item.Validate(); err != nil {
+			return fmt.Errorf("input item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateResponseInput validates typed input items; other input is sent as is.
+func validateResponseInput(input any) error {
+	if items, ok := input.([]ResponseInputItem); ok {
+		return validateResponseInputItems(items)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestResponseInputBuilder(t *testing.T) {
+	var previous openai.CreateResponseResponse
+	err := json.Unmarshal([]byte(`{"output":[
+		{"id":"rs_1","type":"reasoning","summary":[],"encrypted_content":"gAAA"},
+		{"id":"fc_1","type":"function_call","call_id":"call_1","name":"get_weather","arguments":"{}"}
+	]}`), &previous)
+	checks.NoError(t, err, "unmarshal error")
+
+	input, err := openai.NewResponseInputBuilder().
+		Developer("Be brief.").
+		Message(openai.ChatMessageRoleUser,
+			openai.NewResponseInputText("What is this?"),
+			openai.NewResponseInputImageURL("https://example.com/cat.png", "low"),
+		).
+		Output(previous.Output...).
+		FunctionCallOutput("call_1", `{"temperature":21}`).
+		ItemReference("msg_0").
+		Reasoning(openai.ResponseReasoningItem{EncryptedContent: "gBBB"}).
+		CustomToolCallOutput("call_2", "done").
+		ComputerCallOutput("call_3", openai.NewResponseComputerScreenshot("data:image/png;base64,AAAA"),
+			openai.ResponseSafetyCheck{ID: "sc_1"}).
+		MCPApprovalResponse("mcpr_1", false, "not allowed").
+		Build()
+	checks.NoError(t, err, "Build error")
+
+	data, err := json.Marshal(input)
+	checks.NoError(t, err, "marshal error")
+	expected := `[` +
+		`{"type":"message","role":"developer","content":"Be brief."},` +
+		`{"type":"message","role":"user","content":[{"type":"input_text","text":"What is this?"},` +
+		`{"type":"input_image","detail":"low","image_url":"https://example.com/cat.png"}]},` +
+		`{"id":"rs_1","type":"reasoning","summary":[],"encrypted_content":"gAAA"},` +
+		`{"id":"fc_1","type":"function_call","call_id":"call_1","name":"get_weather","arguments":"{}"},` +
+		`{"type":"function_call_output","call_id":"call_1","output":"{\"temperature\":21}"},` +
+		`{"type":"item_reference","id":"msg_0"},` +
+		`{"type":"reasoning","summary":[],"encrypted_content":"gBBB"},` +
+		`{"type":"custom_tool_call_output","call_id":"call_2","output":"done"},` +
+		`{"type":"computer_call_output","call_id":"call_3",` +
+		`"output":{"type":"computer_screenshot","image_url":"data:image/png;base64,AAAA"},` +
+		`"acknowledged_safety_checks":[{"id":"sc_1"}]},` +
+		`{"type":"mcp_approval_response","approval_request_id":"mcpr_1","approve":false,"reason":"not allowed"}` +
+		`]`
+	if string(data) != expected {
+		t.Errorf("unexpected input:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestResponseInputBuilderValidation(t *testing.T) {
+	testCases := []struct {
+		name    string
+		builder *openai.ResponseInputBuilder
+	}{
+		{"empty message", openai.NewResponseInputBuilder().User("")},
+		{"unknown role", openai.NewResponseInputBuilder().Message("tool", openai.NewResponseInputText("hi"))},
+		{"image without source", openai.NewResponseInputBuilder().Message(openai.ChatMessageRoleUser,
+			openai.NewResponseInputImageURL("", "auto"))},
+		{"file data without name", openai.NewResponseInputBuilder().Message(openai.ChatMessageRoleUser,
+			openai.NewResponseInputFileData("", "AAAA"))},
+		{"output without call", openai.NewResponseInputBuilder().FunctionCallOutput("", "result")},
+		{"output without result", openai.NewResponseInputBuilder().CustomToolCallOutput("call_1", nil)},
+		{"reference without id", openai.NewResponseInputBuilder().ItemReference("")},
+		{"reasoning without content", openai.NewResponseInputBuilder().Reasoning(openai.ResponseReasoningItem{})},
+		{"screenshot without image", openai.NewResponseInputBuilder().ComputerCallOutput("call_1",
+			openai.ResponseComputerScreenshot{Type: "computer_screenshot"})},
+		{"approval without request", openai.NewResponseInputBuilder().MCPApprovalResponse("", true, "")},
+		{"function call without name", openai.NewResponseInputBuilder().FunctionCall(
+			openai.ResponseFunctionToolCall{CallID: "call_1"})},
+		{"assistant with input text", openai.NewResponseInputBuilder().Message(openai.ChatMessageRoleAssistant,
+			openai.NewResponseInputText("hi"))},
+		{"user with output text", openai.NewResponseInputBuilder().Message(openai.ChatMessageRoleUser,
+			openai.ResponseOutputContent{Type: "output_text", Text: "hi"})},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.builder.Build()
+			checks.ErrorIs(t, err, openai.ErrInvalidResponseInput, "Build should reject the item")
+		})
+	}
+}
+
+func TestResponseInputAssistantContent(t *testing.T) {
+	_, err := openai.NewResponseInputBuilder().
+		Message(openai.ChatMessageRoleAssistant,
+			openai.ResponseOutputContent{Type: "output_text", Text: "Sunny."},
+			&openai.ResponseOutputContent{Type: "refusal", Refusal: "No."}).
+		Build()
+	checks.NoError(t, err, "assistant output content should be accepted")
+}
+
+func TestCreateResponseValidatesTypedInput(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests int
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"input":[{"type":"message","role":"user","content":"hi"}],"model":"gpt-4o"}` {
+			t.Errorf("unexpected request body: %s", body)
+		}
+		fmt.Fprint(w, `{"id":"resp_1","object":"response","output":[]}`)
+	})
+
+	_, err := client.CreateResponse(context.Background(), openai.CreateResponseRequest{
+		Model: openai.GPT4o,
+		Input: []openai.ResponseInputItem{openai.ResponseFunctionCallOutput{Type: "function_call_output"}},
+	})
+	checks.ErrorIs(t, err, openai.ErrInvalidResponseInput, "CreateResponse should validate input")
+
+	input, err := openai.NewResponseInputBuilder().User("hi").Build()
+	checks.NoError(t, err, "Build error")
+	_, err = client.CreateResponse(context.Background(), openai.CreateResponseRequest{Model: openai.GPT4o, Input: input})
+	checks.NoError(t, err, "CreateResponse error")
+	if requests != 1 {
+		t.Errorf("expected only the valid request to be sent, got %d requests", requests)
+	}
+}
@@ -0,0 +1,525 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ResponseOutputType identifies the variant of a response output item.
+type ResponseOutputType string
+
+const (
+	ResponseOutputTypeMessage             ResponseOutputType = "message"
+	ResponseOutputTypeFunctionCall        ResponseOutputType = "function_call"
+	ResponseOutputTypeReasoning           ResponseOutputType = "reasoning"
+	ResponseOutputTypeWebSearchCall       ResponseOutputType = "web_search_call"
+	ResponseOutputTypeFileSearchCall      ResponseOutputType = "file_search_call"
+	ResponseOutputTypeCodeInterpreterCall ResponseOutputType = "code_interpreter_call"
+	ResponseOutputTypeImageGenerationCall ResponseOutputType = "image_generation_call"
+	ResponseOutputTypeMCPCall             ResponseOutputType = "mcp_call"
+	ResponseOutputTypeMCPListTools        ResponseOutputType = "mcp_list_tools"
+	ResponseOutputTypeMCPApprovalRequest  ResponseOutputType = "mcp_approval_request"
+	ResponseOutputTypeComputerCall        ResponseOutputType = "computer_call"
+	ResponseOutputTypeCustomToolCall      ResponseOutputType = "custom_tool_call"
+	ResponseOutputTypeLocalShellCall      ResponseOutputType = "local_shell_call"
+)
+
+// ResponseOutput is a single item of a response's output. Exactly one of the
+// variant fields matching Type is set. Items of types unknown to this library
+// leave every variant nil; their complete JSON is always available in Raw.
+type ResponseOutput struct {
+	Type ResponseOutputType
+
+	Message             *ResponseOutputMessage
+	FunctionCall        *ResponseFunctionToolCall
+	Reasoning           *ResponseReasoningItem
+	WebSearchCall       *ResponseWebSearchCall
+	FileSearchCall      *ResponseFileSearchCall
+	CodeInterpreterCall *ResponseCodeInterpreterCall
+	ImageGenerationCall *ResponseImageGenerationCall
+	MCPCall             *ResponseMCPCall
+	MCPListTools        *ResponseMCPListTools
+	MCPApprovalRequest  *ResponseMCPApprovalRequest
+	ComputerCall        *ResponseComputerCall
+	CustomToolCall      *ResponseCustomToolCall
+	LocalShellCall      *ResponseLocalShellCall
+
+	Raw json.RawMessage
+}
+
+// variant returns a pointer to the variant field for t, or nil if t is unknown.
+func (o *ResponseOutput) variant(t ResponseOutputType) any {
+	switch t {
+	case ResponseOutputTypeMessage:
+		o.Message = new(ResponseOutputMessage)
+		return o.Message
+	case ResponseOutputTypeFunctionCall:
+		o.FunctionCall = new(ResponseFunctionToolCall)
+		return o.FunctionCall
+	case ResponseOutputTypeReasoning:
+		o.Reasoning = new(ResponseReasoningItem)
+		return o.Reasoning
+	case ResponseOutputTypeWebSearchCall:
+		o.WebSearchCall = new(ResponseWebSearchCall)
+		return o.WebSearchCall
+	case ResponseOutputTypeFileSearchCall:
+		o.FileSearchCall = new(ResponseFileSearchCall)
+		return o.FileSearchCall
+	case ResponseOutputTypeCodeInterpreterCall:
+		o.CodeInterpreterCall = new(ResponseCodeInterpreterCall)
+		return o.CodeInterpreterCall
+	case ResponseOutputTypeImageGenerationCall:
+		o.ImageGenerationCall = new(ResponseImageGenerationCall)
+		return o.ImageGenerationCall
+	case ResponseOutputTypeMCPCall:
+		o.MCPCall = new(ResponseMCPCall)
+		return o.MCPCall
+	case ResponseOutputTypeMCPListTools:
+		o.MCPListTools = new(ResponseMCPListTools)
+		return o.MCPListTools
+	case ResponseOutputTypeMCPApprovalRequest:
+		o.MCPApprovalRequest = new(ResponseMCPApprovalRequest)
+		return o.MCPApprovalRequest
+	case ResponseOutputTypeComputerCall:
+		o.ComputerCall = new(ResponseComputerCall)
+		return o.ComputerCall
+	case ResponseOutputTypeCustomToolCall:
+		o.CustomToolCall = new(ResponseCustomToolCall)
+		return o.CustomToolCall
+	case ResponseOutputTypeLocalShellCall:
+		o.LocalShellCall = new(ResponseLocalShellCall)
+		return o.LocalShellCall
+	default:
+		return nil
+	}
+}
+
+// setVariant returns the variant field that is set, or nil.
+//
+//nolint:gocyclo // one case per variant
+func (o ResponseOutput) setVariant() any {
+	switch {
+	case o.Message != nil:
+		return o.Message
+	case o.FunctionCall != nil:
+		return o.FunctionCall
+	case o.Reasoning != nil:
+		return o.Reasoning
+	case o.WebSearchCall != nil:
+		return o.WebSearchCall
+	case o.FileSearchCall != nil:
+		return o.FileSearchCall
+	case o.CodeInterpreterCall != nil:
+		return o.CodeInterpreterCall
+	case o.ImageGenerationCall != nil:
+		return o.ImageGenerationCall
+	case o.MCPCall != nil:
+		return o.MCPCall
+	case o.MCPListTools != nil:
+		return o.MCPListTools
+	case o.MCPApprovalRequest != nil:
+		return o.MCPApprovalRequest
+	case o.ComputerCall != nil:
+		return o.ComputerCall
+	case o.CustomToolCall != nil:
+		return o.CustomToolCall
+	case o.LocalShellCall != nil:
+		return o.LocalShellCall
+	default:
+		return nil
+	}
+}
+
+// UnmarshalJSON decodes the variant selected by the item's type and retains
+// the complete item in Raw.
+func (o *ResponseOutput) UnmarshalJSON(data []byte) error {
+	var discriminator struct {
+		Type ResponseOutputType `json:"type"`
+	}
+	if err := json.Unmarshal(data, &discriminator); err != nil {
+		return err
+	}
+
+	*o = ResponseOutput{Type: discriminator.Type}
+	if v := o.variant(discriminator.Type); v != nil {
+		if err := json.Unmarshal(data, v); err != nil {
+			return err
+		}
+	}
+	o.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON encodes the variant that is set, falling back to Raw for items
+// of unknown types. Fields of Raw that the variant does not model are kept, so
+// that decoded items can be passed back to the API unchanged.
+func (o ResponseOutput) MarshalJSON() ([]byte, error) {
+	v := o.setVariant()
+	if v == nil {
+		if len(o.Raw) != 0 {
+			return o.Raw, nil
+		}
+		return json.Marshal(struct {
+			Type ResponseOutputType `json:"type"`
+		}{o.Type})
+	}
+	data, err := json.Marshal(v)
+	if err != nil || len(o.Raw) == 0 {
+		return data, err
+	}
+	return mergeRawFields(o.Raw, data, jsonFieldNames(reflect.TypeOf(v))), nil
+}
+
+// jsonFieldNames returns the JSON names of the fields of the struct t, or of
+// the struct t points to.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for embedded := range jsonFieldNames(field.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// mergeRawFields appends the fields of raw that typed lacks to typed, unless
+// known lists them: a field the variant models but omitted, such as a cleared
+// omitempty field, stays omitted. raw is ignored when it is not an object of
+// the same type.
+func mergeRawFields(raw, typed []byte, known map[string]bool) []byte {
+	var rawFields, typedFields map[string]json.RawMessage
+	if json.Unmarshal(raw, &rawFields) != nil || json.Unmarshal(typed, &typedFields) != nil {
+		return typed
+	}
+	if !bytes.Equal(rawFields["type"], typedFields["type"]) {
+		return typed
+	}
+
+	extra := make([]string, 0, len(rawFields))
+	for field := range rawFields {
+		if _, ok := typedFields[field]; !ok && !known[field] {
+			extra = append(extra, field)
+		}
+	}
+	if len(extra) == 0 {
+		return typed
+	}
+	sort.Strings(extra)
+
+	var buf bytes.Buffer
+	buf.Write(typed[:len(typed)-1])
+	for _, field := range extra {
+		key, _ := json.Marshal(field)
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err := json.Compact(&buf, rawFields[field]); err != nil {
+			return typed
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// ResponseOutputMessage is a message generated by the model.
+type ResponseOutputMessage struct {
+	ID      string                  `json:"id"`
+	Type    ResponseOutputType      `json:"type"`
+	Role    string                  `json:"role"`
+	Status  string                  `json:"status,omitempty"`
+	Phase   string                  `json:"phase,omitempty"`
+	Content []ResponseOutputContent `json:"content"`
+}
+
+// ResponseFunctionToolCall is a call to a function tool.
+type ResponseFunctionToolCall struct {
+	ID        string             `json:"id,omitempty"`
+	Type      ResponseOutputType `json:"type"`
+	CallID    string             `json:"call_id"`
+	Name      string             `json:"name"`
+	Arguments string             `json:"arguments"`
+	Status    string             `json:"status,omitempty"`
+}
+
+// ResponseReasoningContent is a reasoning text content part.
+type ResponseReasoningContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// ResponseReasoningItem describes the chain of thought of a reasoning model.
+// EncryptedContent is populated when ResponseIncludeReasoningEncryptedContent
+// is requested, and allows the item to be passed back when Store is false.
+type ResponseReasoningItem struct {
+	ID               string                     `json:"id,omitempty"`
+	Type             ResponseOutputType         `json:"type"`
+	Summary          []ResponseSummaryPart      `json:"summary"`
+	Content          []ResponseReasoningContent `json:"content,omitempty"`
+	EncryptedContent string                     `json:"encrypted_content,omitempty"`
+	Status           string                     `json:"status,omitempty"`
+}
+
+// ResponseWebSearchSource is a source consulted by a web search.
+type ResponseWebSearchSource struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// ResponseWebSearchAction describes the search, open_page or find action
+// taken by a web search call.
+type ResponseWebSearchAction struct {
+	Type    string                    `json:"type"`
+	Query   string                    `json:"query,omitempty"`
+	Queries []string                  `json:"queries,omitempty"`
+	Sources []ResponseWebSearchSource `json:"sources,omitempty"`
+	URL     string                    `json:"url,omitempty"`
+	Pattern string                    `json:"pattern,omitempty"`
+}
+
+// ResponseWebSearchCall is a call to the built-in web search tool.
+type ResponseWebSearchCall struct {
+	ID     string                   `json:"id"`
+	Type   ResponseOutputType       `json:"type"`
+	Status string                   `json:"status"`
+	Action *ResponseWebSearchAction `json:"action,omitempty"`
+}
+
+// ResponseFileSearchResult is a single file search match.
+type ResponseFileSearchResult struct {
+	FileID     string         `json:"file_id"`
+	Filename   string         `json:"filename,omitempty"`
+	Score      float64        `json:"score,omitempty"`
+	Text       string         `json:"text,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// ResponseFileSearchCall is a call to the built-in file search tool. Results
+// are populated when ResponseIncludeFileSearchCallResults is requested.
+type ResponseFileSearchCall struct {
+	ID      string                     `json:"id"`
+	Type    ResponseOutputType         `json:"type"`
+	Status  string                     `json:"status"`
+	Queries []string                   `json:"queries"`
+	Results []ResponseFileSearchResult `json:"results,omitempty"`
+}
+
+// ResponseCodeInterpreterOutput is a logs or image output of a code interpreter call.
+type ResponseCodeInterpreterOutput struct {
+	Type string `json:"type"`
+	Logs string `json:"logs,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// ResponseCodeInterpreterCall is a call to the built-in code interpreter tool.
+type ResponseCodeInterpreterCall struct {
+	ID          string                          `json:"id"`
+	Type        ResponseOutputType              `json:"type"`
+	Status      string                          `json:"status"`
+	ContainerID string                          `json:"container_id"`
+	Code        string                          `json:"code,omitempty"`
+	Outputs     []ResponseCodeInterpreterOutput `json:"outputs,omitempty"`
+}
+
+// ResponseImageGenerationCall is a call to the built-in image generation
+// tool. Result holds the base64-encoded image.
+type ResponseImageGenerationCall struct {
+	ID            string             `json:"id"`
+	Type          ResponseOutputType `json:"type"`
+	Status        string             `json:"status"`
+	Result        string             `json:"result,omitempty"`
+	RevisedPrompt string             `json:"revised_prompt,omitempty"`
+	Background    string             `json:"background,omitempty"`
+	OutputFormat  string             `json:"output_format,omitempty"`
+	Quality       string             `json:"quality,omitempty"`
+	Size          string             `json:"size,omitempty"`
+}
+
+// ResponseMCPCall is a call to a tool on a remote MCP server.
+type ResponseMCPCall struct {
+	ID                string             `json:"id"`
+	Type              ResponseOutputType `json:"type"`
+	ServerLabel       string             `json:"server_label"`
+	Name              string             `json:"name"`
+	Arguments         string             `json:"arguments"`
+	Output            string             `json:"output,omitempty"`
+	Error             string             `json:"error,omitempty"`
+	ApprovalRequestID string             `json:"approval_request_id,omitempty"`
+	Status            string             `json:"status,omitempty"`
+}
+
+// ResponseMCPTool is a tool made available by an MCP server.
+type ResponseMCPTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
+	Annotations any    `json:"annotations,omitempty"`
+}
+
+// ResponseMCPListTools lists the tools available on an MCP server.
+type ResponseMCPListTools struct {
+	ID          string             `json:"id"`
+	Type        ResponseOutputType `json:"type"`
+	ServerLabel string             `json:"server_label"`
+	Tools       []ResponseMCPTool  `json:"tools"`
+	Error       string             `json:"error,omitempty"`
+}
+
+// ResponseMCPApprovalRequest asks for approval before an MCP tool is invoked.
+type ResponseMCPApprovalRequest struct {
+	ID          string             `json:"id"`
+	Type        ResponseOutputType `json:"type"`
+	ServerLabel string             `json:"server_label"`
+	Name        string             `json:"name"`
+	Arguments   string             `json:"arguments"`
+}
+
+// ResponseComputerPoint is a coordinate on the screen.
+type ResponseComputerPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// ResponseComputerAction is a click, double_click, drag, keypress, move,
+// screenshot, scroll, type or wait action. Only the fields used by Type are set,
+// except X and Y, which are always encoded since 0 is a valid coordinate.
+type ResponseComputerAction struct {
+	Type    string                  `json:"type"`
+	Button  string                  `json:"button,omitempty"`
+	X       int                     `json:"x"`
+	Y       int                     `json:"y"`
+	ScrollX int                     `json:"scroll_x,omitempty"`
+	ScrollY int                     `json:"scroll_y,omitempty"`
+	Keys    []string                `json:"keys,omitempty"`
+	Text    string                  `json:"text,omitempty"`
+	Path    []ResponseComputerPoint `json:"path,omitempty"`
+}
+
+// ResponseSafetyCheck is a pending safety check for a computer call.
+type ResponseSafetyCheck struct {
+	ID      string `json:"id"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ResponseComputerCall is a call to the computer use tool.
+type ResponseComputerCall struct {
+	ID                  string                 `json:"id"`
+	Type                ResponseOutputType     `json:"type"`
+	CallID              string                 `json:"call_id"`
+	Status              string                 `json:"status"`
+	Action              ResponseComputerAction `json:"action"`
+	PendingSafetyChecks []ResponseSafetyCheck  `json:"pending_safety_checks"`
+}
+
+// ResponseCustomToolCall is a call to a custom tool with free-form input.
+type ResponseCustomToolCall struct {
+	ID     string             `json:"id,omitempty"`
+	Type   ResponseOutputType `json:"type"`
+	CallID string             `json:"call_id"`
+	Name   string             `json:"name"`
+	Input  string             `json:"input"`
+}
+
+// ResponseLocalShellAction is the command requested by a local shell call.
+type ResponseLocalShellAction struct {
+	Type             string            `json:"type"`
+	Command          []string          `json:"command"`
+	Env              map[string]string `json:"env"`
+	TimeoutMs        int               `json:"timeout_ms,omitempty"`
+	User             string            `json:"user,omitempty"`
+	WorkingDirectory string            `json:"working_directory,omitempty"`
+}
+
+// ResponseLocalShellCall is a call to the local shell tool.
+type ResponseLocalShellCall struct {
+	ID     string                   `json:"id"`
+	Type   ResponseOutputType       `json:"type"`
+	CallID string                   `json:"call_id"`
+	Status string                   `json:"status"`
+	Action ResponseLocalShellAction `json:"action"`
+}
+
+// FunctionCalls returns the function tool calls in the output.
+func (r CreateResponseResponse) FunctionCalls() []ResponseFunctionToolCall {
+	var calls []ResponseFunctionToolCall
+	for _, item := range r.Output {
+		if item.FunctionCall != nil {
+			calls = append(calls, *item.FunctionCall)
+		}
+	}
+	return calls
+}
+
+// ReasoningItems returns the reasoning items in the output. It is not named
+// Reasoning as that field holds the reasoning configuration of the response.
+func (r CreateResponseResponse) ReasoningItems() []ResponseReasoningItem {
+	var items []ResponseReasoningItem
+	for _, item := range r.Output {
+		if item.Reasoning != nil {
+			items = append(items, *item.Reasoning)
+		}
+	}
+	return items
+}
+
+// Messages returns the messages in the output.
+func (r CreateResponseResponse) Messages() []ResponseOutputMessage {
+	var messages []ResponseOutputMessage
+	for _, item := range r.Output {
+		if item.Message != nil {
+			messages = append(messages, *item.Message)
+		}
+	}
+	return messages
+}
+
+// Citations returns the URL, file and container file citations annotating
+// the text of the output messages.
+func (r CreateResponseResponse) Citations() []ResponseAnnotation {
+	var citations []ResponseAnnotation
+	for _, message := range r.Messages() {
+		for _, content := range message.Content {
+			for _, annotation := range content.Annotations {
+				switch annotation.Type {
+				case "url_citation", "file_citation", "container_file_citation":
+					citations = append(citations, annotation)
+				}
+			}
+		}
+	}
+	return citations
+}
+
+// Refusal returns the concatenated refusals of the output messages.
+func (r CreateResponseResponse) Refusal() string {
+	var refusal string
+	for _, message := range r.Messages() {
+		for _, content := range message.Content {
+			if content.Type == "refusal" {
+				refusal += content.Refusal
+			}
+		}
+	}
+	return refusal
+}
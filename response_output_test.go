@@ -0,0 +1,165 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const typedOutputResponse = `{
+	"id": "resp_1",
+	"object": "response",
+	"model": "gpt-4o",
+	"reasoning": {"effort": "low"},
+	"output": [
+		{"id": "rs_1", "type": "reasoning", "summary": [{"type": "summary_text", "text": "Thinking"}],
+			"encrypted_content": "gAAA"},
+		{"id": "ws_1", "type": "web_search_call", "status": "completed",
+			"action": {"type": "search", "query": "weather paris"}},
+		{"id": "msg_1", "type": "message", "role": "assistant", "status": "completed", "content": [
+			{"type": "output_text", "text": "Sunny.", "annotations": [
+				{"type": "url_citation", "url": "https://example.com", "title": "Weather", "start_index": 0, "end_index": 6},
+				{"type": "file_path", "file_id": "file_1"}
+			]}
+		]},
+		{"id": "fc_1", "type": "function_call", "call_id": "call_1", "name": "get_weather",
+			"arguments": "{\"city\":\"Paris\"}", "status": "completed"},
+		{"id": "ctc_1", "type": "custom_tool_call", "call_id": "call_2", "name": "grammar", "input": "x + 1"},
+		{"id": "cc_1", "type": "computer_call", "call_id": "call_3", "status": "completed",
+			"action": {"type": "click", "button": "left", "x": 10, "y": 20}, "pending_safety_checks": []},
+		{"id": "mcp_1", "type": "mcp_call", "server_label": "docs", "name": "search", "arguments": "{}",
+			"output": "found"},
+		{"id": "new_1", "type": "hologram_call", "frequency": 42}
+	]
+}`
+
+func TestResponseOutputTypedVariants(t *testing.T) {
+	var response openai.CreateResponseResponse
+	err := json.Unmarshal([]byte(typedOutputResponse), &response)
+	checks.NoError(t, err, "unmarshal error")
+
+	if len(response.Output) != 8 {
+		t.Fatalf("expected 8 output items, got %d", len(response.Output))
+	}
+	if response.Reasoning == nil || response.Reasoning.Effort != "low" {
+		t.Errorf("reasoning configuration should still be decoded: %+v", response.Reasoning)
+	}
+
+	reasoning := response.ReasoningItems()
+	if len(reasoning) != 1 || reasoning[0].Summary[0].Text != "Thinking" || reasoning[0].EncryptedContent != "gAAA" {
+		t.Errorf("unexpected reasoning items: %+v", reasoning)
+	}
+	if search := response.Output[1].WebSearchCall; search == nil || search.Action.Query != "weather paris" {
+		t.Errorf("unexpected web search call: %+v", response.Output[1])
+	}
+	calls := response.FunctionCalls()
+	if len(calls) != 1 || calls[0].CallID != "call_1" || calls[0].Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected function calls: %+v", calls)
+	}
+	if custom := response.Output[4].CustomToolCall; custom == nil || custom.Input != "x + 1" {
+		t.Errorf("unexpected custom tool call: %+v", response.Output[4])
+	}
+	if computer := response.Output[5].ComputerCall; computer == nil || computer.Action.X != 10 ||
+		computer.Action.Button != "left" {
+		t.Errorf("unexpected computer call: %+v", response.Output[5])
+	}
+	if mcp := response.Output[6].MCPCall; mcp == nil || mcp.ServerLabel != "docs" || mcp.Output != "found" {
+		t.Errorf("unexpected MCP call: %+v", response.Output[6])
+	}
+
+	citations := response.Citations()
+	if len(citations) != 1 || citations[0].URL != "https://example.com" || citations[0].EndIndex != 6 {
+		t.Errorf("unexpected citations: %+v", citations)
+	}
+	if response.GetOutputText() != "Sunny." {
+		t.Errorf("unexpected output text: %q", response.GetOutputText())
+	}
+}
+
+func TestResponseOutputUnknownTypeIsKept(t *testing.T) {
+	var response openai.CreateResponseResponse
+	err := json.Unmarshal([]byte(typedOutputResponse), &response)
+	checks.NoError(t, err, "unmarshal error")
+
+	unknown := response.Output[7]
+	if unknown.Type != "hologram_call" || unknown.Message != nil || unknown.FunctionCall != nil {
+		t.Fatalf("unexpected unknown item: %+v", unknown)
+	}
+
+	data, err := json.Marshal(unknown)
+	checks.NoError(t, err, "marshal error")
+	var fields map[string]any
+	checks.NoError(t, json.Unmarshal(data, &fields), "unmarshal error")
+	if fields["frequency"] != float64(42) || fields["id"] != "new_1" {
+		t.Errorf("unknown item should round-trip, got %s", data)
+	}
+}
+
+func TestResponseOutputMarshalsVariant(t *testing.T) {
+	var item openai.ResponseOutput
+	err := json.Unmarshal([]byte(`{"id":"fc_1","type":"function_call","call_id":"call_1","name":"f","arguments":"{}"}`),
+		&item)
+	checks.NoError(t, err, "unmarshal error")
+
+	item.FunctionCall.Arguments = `{"changed":true}`
+	data, err := json.Marshal(item)
+	checks.NoError(t, err, "marshal error")
+
+	var roundTrip openai.ResponseOutput
+	checks.NoError(t, json.Unmarshal(data, &roundTrip), "unmarshal error")
+	if roundTrip.FunctionCall == nil || roundTrip.FunctionCall.Arguments != `{"changed":true}` {
+		t.Errorf("marshaling should use the typed variant, got %s", data)
+	}
+}
+
+func TestResponseOutputMarshalKeepsUnknownFields(t *testing.T) {
+	var item openai.ResponseOutput
+	err := json.Unmarshal([]byte(`{"id":"fc_1","type":"function_call","call_id":"call_1","name":"f",`+
+		`"arguments":"{}","namespace":"tools","provenance":{"source":"model"}}`), &item)
+	checks.NoError(t, err, "unmarshal error")
+
+	item.FunctionCall.Arguments = `{"changed":true}`
+	data, err := json.Marshal(item)
+	checks.NoError(t, err, "marshal error")
+	want := `{"id":"fc_1","type":"function_call","call_id":"call_1","name":"f","arguments":"{\"changed\":true}",` +
+		`"namespace":"tools","provenance":{"source":"model"}}`
+	if string(data) != want {
+		t.Errorf("unexpected encoding:\n%s\nexpected:\n%s", data, want)
+	}
+
+	// Raw is ignored once the item is replaced by a variant of another type.
+	item.FunctionCall = nil
+	item.Message = &openai.ResponseOutputMessage{ID: "msg_1", Type: openai.ResponseOutputTypeMessage, Role: "assistant"}
+	data, err = json.Marshal(item)
+	checks.NoError(t, err, "marshal error")
+	if want = `{"id":"msg_1","type":"message","role":"assistant","content":null}`; string(data) != want {
+		t.Errorf("unexpected encoding %s", data)
+	}
+}
+
+func TestResponseOutputMarshalDropsClearedFields(t *testing.T) {
+	var item openai.ResponseOutput
+	err := json.Unmarshal([]byte(`{"id":"rs_1","type":"reasoning","summary":[],"encrypted_content":"gAAA",`+
+		`"status":"completed","signature":"sig"}`), &item)
+	checks.NoError(t, err, "unmarshal error")
+
+	// Cleared omitempty fields are modeled by the variant and stay omitted;
+	// only the unmodeled signature is kept from Raw.
+	item.Reasoning.EncryptedContent = ""
+	item.Reasoning.Status = ""
+	data, err := json.Marshal(item)
+	checks.NoError(t, err, "marshal error")
+	if want := `{"id":"rs_1","type":"reasoning","summary":[],"signature":"sig"}`; string(data) != want {
+		t.Errorf("unexpected encoding:\n%s\nexpected:\n%s", data, want)
+	}
+}
+
+func TestResponseComputerActionKeepsZeroCoordinates(t *testing.T) {
+	data, err := json.Marshal(openai.ResponseComputerAction{Type: "click", Button: "left"})
+	checks.NoError(t, err, "marshal error")
+	if want := `{"type":"click","button":"left","x":0,"y":0}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+}
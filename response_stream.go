@@ -3,6 +3,7 @@ package openai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -77,9 +78,11 @@ type ResponseStreamEvent struct {
 	OutputIndex       int                     `json:"output_index,omitempty"`
 	ContentIndex      int                     `json:"content_index,omitempty"`
 	SummaryIndex      int                     `json:"summary_index,omitempty"`
+	AnnotationIndex   int                     `json:"annotation_index,omitempty"`
 	Delta             string                  `json:"delta,omitempty"`
 	Text              string                  `json:"text,omitempty"`
 	Arguments         string                  `json:"arguments,omitempty"`
+	Input             string                  `json:"input,omitempty"`
 	PartialImageB64   string                  `json:"partial_image_b64,omitempty"`
 	PartialImageIndex int                     `json:"partial_image_index,omitempty"`
 	Logprobs          []ResponseLogprob       `json:"logprobs,omitempty"`
@@ -103,21 +106,153 @@ func (e *ResponseStreamEvent) UnmarshalJSON(data []byte) error {
 }
 
 // ResponseStream reads server-sent events from a streaming Responses API request.
+//
+// The streams of background responses resume by themselves: when the
+// connection drops before the response has finished, Recv reconnects with
+// RetrieveResponseStream, starting after the last event received, and skips
+// events it has already returned. See ClientConfig.ResponseStreamMaxReconnects.
 type ResponseStream struct {
 	*streamReader[ResponseStreamEvent]
+
+	resume *responseStreamResume
+}
+
+// responseStreamResume tracks what is needed to resume a response stream.
+type responseStreamResume struct {
+	client *Client
+	ctx    context.Context //nolint:containedctx // reconnects belong to the request that created the stream
+	// responseID is known once an event carrying the response was received.
+	responseID string
+	options    RetrieveResponseOptions
+	// sequence is the sequence number of the last event returned, if seen.
+	sequence   int
+	seen       bool
+	finished   bool
+	reconnects int
+}
+
+// Recv returns the next event, or io.EOF once the stream has ended. When the
+// stream of a background response cannot be resumed, Recv returns the error
+// that ended it.
+func (s *ResponseStream) Recv() (ResponseStreamEvent, error) {
+	for {
+		event, err := s.streamReader.Recv()
+		resume := s.resume
+		if resume == nil {
+			return event, err
+		}
+		if err == nil {
+			if resume.seen && event.SequenceNumber <= resume.sequence {
+				continue
+			}
+			resume.observe(event)
+			return event, nil
+		}
+
+		reader := resume.reconnect(err)
+		if reader == nil {
+			return event, err
+		}
+		s.streamReader.Close()
+		s.streamReader = reader
+	}
+}
+
+func (r *responseStreamResume) observe(event ResponseStreamEvent) {
+	r.sequence = event.SequenceNumber
+	r.seen = true
+	r.reconnects = 0
+	if event.Response != nil && event.Response.ID != "" {
+		r.responseID = event.Response.ID
+	}
+	switch event.Type {
+	case ResponseStreamEventCompleted, ResponseStreamEventFailed, ResponseStreamEventIncomplete,
+		ResponseStreamEventError:
+		r.finished = true
+	default:
+	}
+}
+
+// resumable reports whether the stream can be resumed after err. Errors
+// reported by the API and malformed events are not transient; an io.EOF
+// before the response has finished means the connection was dropped.
+func (r *responseStreamResume) resumable(err error) bool {
+	if r.finished || r.responseID == "" || r.ctx.Err() != nil ||
+		r.reconnects >= r.maxReconnects() {
+		return false
+	}
+	var (
+		apiErr       *APIError
+		requestErr   *RequestError
+		syntaxErr    *json.SyntaxError
+		unmarshalErr *json.UnmarshalTypeError
+	)
+	return !errors.As(err, &apiErr) && !errors.As(err, &requestErr) &&
+		!errors.As(err, &syntaxErr) && !errors.As(err, &unmarshalErr) &&
+		!errors.Is(err, ErrTooManyEmptyStreamMessages)
+}
+
+func (r *responseStreamResume) maxReconnects() int {
+	if r.client.config.ResponseStreamMaxReconnects == 0 {
+		return defaultResponseStreamMaxReconnects
+	}
+	return r.client.config.ResponseStreamMaxReconnects
+}
+
+// reconnect opens the stream again after the last event returned, once the
+// stream ended with err. Failed attempts are repeated as long as their errors
+// are transient, up to the reconnect limit. It returns nil if the stream
+// cannot be resumed.
+func (r *responseStreamResume) reconnect(err error) *streamReader[ResponseStreamEvent] {
+	for r.resumable(err) {
+		var reader *streamReader[ResponseStreamEvent]
+		reader, err = r.reconnectOnce()
+		if err == nil {
+			return reader
+		}
+	}
+	return nil
+}
+
+// reconnectOnce makes a single attempt to reconnect. The first attempt is
+// made right away, later ones back off according to the client's
+// RetryPolicy, or DefaultRetryPolicy if it has none.
+func (r *responseStreamResume) reconnectOnce() (*streamReader[ResponseStreamEvent], error) {
+	r.reconnects++
+	if r.reconnects > 1 {
+		policy := r.client.config.RetryPolicy
+		if policy == nil {
+			policy = DefaultRetryPolicy()
+		}
+		if err := sleepContext(r.ctx, policy.backoff(r.reconnects-1)); err != nil {
+			return nil, err
+		}
+	}
+
+	options := r.options
+	if r.seen {
+		sequence := r.sequence
+		options.StartingAfter = &sequence
+	}
+	return r.client.retrieveResponseStream(r.ctx, r.responseID, options)
 }
 
 // CreateResponseStream creates a response and streams its generation events.
+// The stream resumes after connection drops if request.Background is set.
 func (c *Client) CreateResponseStream(
 	ctx context.Context,
 	request CreateResponseRequest,
 ) (stream *ResponseStream, err error) {
 	request.Stream = true
+	if err = validateResponseInput(request.Input); err != nil {
+		return nil, err
+	}
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
 		c.fullURL(responsesSuffix),
 		withBody(request),
+		withOperation("CreateResponseStream", request),
 	)
 	if err != nil {
 		return nil, err
@@ -127,5 +262,49 @@ func (c *Client) CreateResponseStream(
 	if err != nil {
 		return nil, err
 	}
-	return &ResponseStream{streamReader: reader}, nil
+	stream = &ResponseStream{streamReader: reader}
+	if request.Background {
+		stream.resume = &responseStreamResume{client: c, ctx: ctx}
+	}
+	return stream, nil
+}
+
+// RetrieveResponseStream streams the events of a background response, from
+// the beginning or after options.StartingAfter. Events that were already
+// emitted are replayed first. The stream resumes after connection drops.
+func (c *Client) RetrieveResponseStream(
+	ctx context.Context,
+	responseID string,
+	options ...RetrieveResponseOptions,
+) (*ResponseStream, error) {
+	var opts RetrieveResponseOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	reader, err := c.retrieveResponseStream(ctx, responseID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resume := &responseStreamResume{client: c, ctx: ctx, responseID: responseID, options: opts}
+	if opts.StartingAfter != nil {
+		resume.sequence = *opts.StartingAfter
+		resume.seen = true
+	}
+	return &ResponseStream{streamReader: reader, resume: resume}, nil
+}
+
+func (c *Client) retrieveResponseStream(
+	ctx context.Context,
+	responseID string,
+	options RetrieveResponseOptions,
+) (*streamReader[ResponseStreamEvent], error) {
+	values := options.values()
+	values.Set("stream", "true")
+	urlSuffix := responseResourceSuffix(responseID, "", values)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), withOperation("RetrieveResponseStream", nil))
+	if err != nil {
+		return nil, err
+	}
+	return sendRequestStream[ResponseStreamEvent](c, req)
 }
@@ -0,0 +1,349 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ResponseStreamAccumulator rebuilds a response from the events of a
+// ResponseStream. Between events, Response returns a live snapshot of the
+// output generated so far; once the response has completed, failed or become
+// incomplete, it returns the final response exactly as CreateResponse would
+// have.
+//
+// The delta callbacks are optional and are called after the delta has been
+// applied, together with the accumulated value of the field it updates.
+type ResponseStreamAccumulator struct {
+	// OnEvent is called for every event.
+	OnEvent func(event ResponseStreamEvent)
+	// OnOutputItemDone is called with each output item once it is complete.
+	OnOutputItemDone func(item ResponseOutput)
+
+	OnOutputTextDelta            func(event ResponseStreamEvent, text string)
+	OnRefusalDelta               func(event ResponseStreamEvent, refusal string)
+	OnFunctionCallArgumentsDelta func(event ResponseStreamEvent, arguments string)
+	OnCustomToolCallInputDelta   func(event ResponseStreamEvent, input string)
+	OnMCPCallArgumentsDelta      func(event ResponseStreamEvent, arguments string)
+	OnCodeInterpreterCodeDelta   func(event ResponseStreamEvent, code string)
+	OnReasoningSummaryTextDelta  func(event ResponseStreamEvent, text string)
+	OnReasoningTextDelta         func(event ResponseStreamEvent, text string)
+	// OnPartialImage receives each partial image of an image generation call
+	// as the base64-encoded event.PartialImageB64.
+	OnPartialImage func(event ResponseStreamEvent)
+
+	response CreateResponseResponse
+	done     bool
+}
+
+// NewResponseStreamAccumulator creates an empty ResponseStreamAccumulator.
+func NewResponseStreamAccumulator() *ResponseStreamAccumulator {
+	return &ResponseStreamAccumulator{}
+}
+
+// Done reports whether a terminal event, response.completed, response.failed
+// or response.incomplete, has been received.
+func (a *ResponseStreamAccumulator) Done() bool {
+	return a.done
+}
+
+// Response returns the response accumulated so far. Snapshots taken before
+// the response is done are copies that later events do not modify.
+func (a *ResponseStreamAccumulator) Response() CreateResponseResponse {
+	if a.done {
+		return a.response
+	}
+	snapshot := a.response
+	data, err := json.Marshal(a.response.Output)
+	if err == nil {
+		snapshot.Output = nil
+		_ = json.Unmarshal(data, &snapshot.Output)
+	}
+	return snapshot
+}
+
+// Consume reads stream until it ends, accumulating every event, and returns
+// the final response.
+func (a *ResponseStreamAccumulator) Consume(stream *ResponseStream) (CreateResponseResponse, error) {
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return a.Response(), nil
+		}
+		if err != nil {
+			return a.Response(), err
+		}
+		if err = a.Add(event); err != nil {
+			return a.Response(), err
+		}
+	}
+}
+
+// Add applies an event to the snapshot. An error event is returned as a
+// *ResponseError; events that refer to unknown output items are ignored.
+//
+//nolint:gocyclo // one case per event type
+func (a *ResponseStreamAccumulator) Add(event ResponseStreamEvent) error {
+	if a.OnEvent != nil {
+		a.OnEvent(event)
+	}
+
+	switch event.Type {
+	case ResponseStreamEventCreated, ResponseStreamEventQueued, ResponseStreamEventInProgress:
+		a.setResponse(event.Response)
+	case ResponseStreamEventCompleted, ResponseStreamEventFailed, ResponseStreamEventIncomplete:
+		a.setResponse(event.Response)
+		a.done = true
+	case ResponseStreamEventOutputItemAdded, ResponseStreamEventOutputItemDone:
+		return a.setItem(event)
+	case ResponseStreamEventContentPartAdded, ResponseStreamEventContentPartDone:
+		a.setContentPart(event)
+	case ResponseStreamEventOutputTextDelta:
+		if part := a.messageContent(event); part != nil {
+			part.Text += event.Delta
+			part.Logprobs = append(part.Logprobs, event.Logprobs...)
+			callDelta(a.OnOutputTextDelta, event, part.Text)
+		}
+	case ResponseStreamEventOutputTextDone:
+		if part := a.messageContent(event); part != nil {
+			part.Text = event.Text
+		}
+	case ResponseStreamEventOutputTextAnnotationAdded:
+		if part := a.messageContent(event); part != nil && event.Annotation != nil {
+			part.Annotations = append(part.Annotations, *event.Annotation)
+		}
+	case ResponseStreamEventRefusalDelta:
+		if part := a.messageContent(event); part != nil {
+			part.Refusal += event.Delta
+			callDelta(a.OnRefusalDelta, event, part.Refusal)
+		}
+	case ResponseStreamEventRefusalDone:
+		if part := a.messageContent(event); part != nil {
+			if refusal, ok := stringField(event.Raw, "refusal"); ok {
+				part.Refusal = refusal
+			}
+		}
+	case ResponseStreamEventFunctionArgumentsDelta:
+		if call := a.item(event.OutputIndex).FunctionCall; call != nil {
+			call.Arguments += event.Delta
+			callDelta(a.OnFunctionCallArgumentsDelta, event, call.Arguments)
+		}
+	case ResponseStreamEventFunctionArgumentsDone:
+		if call := a.item(event.OutputIndex).FunctionCall; call != nil {
+			call.Arguments = event.Arguments
+		}
+	case ResponseStreamEventCustomToolInputDelta:
+		if call := a.item(event.OutputIndex).CustomToolCall; call != nil {
+			call.Input += event.Delta
+			callDelta(a.OnCustomToolCallInputDelta, event, call.Input)
+		}
+	case ResponseStreamEventCustomToolInputDone:
+		if call := a.item(event.OutputIndex).CustomToolCall; call != nil {
+			call.Input = event.Input
+		}
+	case ResponseStreamEventMCPCallArgumentsDelta:
+		if call := a.item(event.OutputIndex).MCPCall; call != nil {
+			call.Arguments += event.Delta
+			callDelta(a.OnMCPCallArgumentsDelta, event, call.Arguments)
+		}
+	case ResponseStreamEventMCPCallArgumentsDone:
+		if call := a.item(event.OutputIndex).MCPCall; call != nil {
+			call.Arguments = event.Arguments
+		}
+	case ResponseStreamEventCodeInterpreterCodeDelta:
+		if call := a.item(event.OutputIndex).CodeInterpreterCall; call != nil {
+			call.Code += event.Delta
+			callDelta(a.OnCodeInterpreterCodeDelta, event, call.Code)
+		}
+	case ResponseStreamEventCodeInterpreterCodeDone:
+		if call := a.item(event.OutputIndex).CodeInterpreterCall; call != nil {
+			call.Code = event.Code
+		}
+	case ResponseStreamEventReasoningSummaryPartAdded, ResponseStreamEventReasoningSummaryPartDone:
+		if part := a.reasoningSummary(event); part != nil && event.Part != nil {
+			*part = ResponseSummaryPart{Type: event.Part.Type, Text: event.Part.Text}
+		}
+	case ResponseStreamEventReasoningSummaryTextDelta:
+		if part := a.reasoningSummary(event); part != nil {
+			part.Text += event.Delta
+			callDelta(a.OnReasoningSummaryTextDelta, event, part.Text)
+		}
+	case ResponseStreamEventReasoningSummaryTextDone:
+		if part := a.reasoningSummary(event); part != nil {
+			part.Text = event.Text
+		}
+	case ResponseStreamEventReasoningTextDelta:
+		if part := a.reasoningContent(event); part != nil {
+			part.Text += event.Delta
+			callDelta(a.OnReasoningTextDelta, event, part.Text)
+		}
+	case ResponseStreamEventReasoningTextDone:
+		if part := a.reasoningContent(event); part != nil {
+			part.Text = event.Text
+		}
+	case ResponseStreamEventImageGenerationPartialImage:
+		if call := a.item(event.OutputIndex).ImageGenerationCall; call != nil {
+			call.Result = event.PartialImageB64
+		}
+		if a.OnPartialImage != nil {
+			a.OnPartialImage(event)
+		}
+	case ResponseStreamEventWebSearchInProgress, ResponseStreamEventWebSearchSearching,
+		ResponseStreamEventWebSearchCompleted:
+		if call := a.item(event.OutputIndex).WebSearchCall; call != nil {
+			call.Status = streamEventStatus(event.Type)
+		}
+	case ResponseStreamEventFileSearchInProgress, ResponseStreamEventFileSearchSearching,
+		ResponseStreamEventFileSearchCompleted:
+		if call := a.item(event.OutputIndex).FileSearchCall; call != nil {
+			call.Status = streamEventStatus(event.Type)
+		}
+	case ResponseStreamEventCodeInterpreterInProgress, ResponseStreamEventCodeInterpreterInterpreting,
+		ResponseStreamEventCodeInterpreterCompleted:
+		if call := a.item(event.OutputIndex).CodeInterpreterCall; call != nil {
+			call.Status = streamEventStatus(event.Type)
+		}
+	case ResponseStreamEventImageGenerationInProgress, ResponseStreamEventImageGenerationGenerating,
+		ResponseStreamEventImageGenerationCompleted:
+		if call := a.item(event.OutputIndex).ImageGenerationCall; call != nil {
+			call.Status = streamEventStatus(event.Type)
+		}
+	case ResponseStreamEventError:
+		if event.Error != nil {
+			return event.Error
+		}
+		return &ResponseError{Code: event.Code, Message: event.Message}
+	}
+	return nil
+}
+
+func callDelta(callback func(ResponseStreamEvent, string), event ResponseStreamEvent, accumulated string) {
+	if callback != nil {
+		callback(event, accumulated)
+	}
+}
+
+// streamEventStatus returns the status named by the last segment of a tool
+// call progress event, e.g. "searching" for response.web_search_call.searching.
+func streamEventStatus(eventType ResponseStreamEventType) string {
+	s := string(eventType)
+	return s[strings.LastIndex(s, ".")+1:]
+}
+
+func stringField(raw json.RawMessage, field string) (string, bool) {
+	if len(raw) == 0 {
+		return "", false
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(raw, &fields) != nil {
+		return "", false
+	}
+	var value string
+	if json.Unmarshal(fields[field], &value) != nil {
+		return "", false
+	}
+	return value, true
+}
+
+func (a *ResponseStreamAccumulator) setResponse(response *CreateResponseResponse) {
+	if response == nil {
+		return
+	}
+	output := a.response.Output
+	header := a.response.httpHeader
+	a.response = *response
+	a.response.httpHeader = header
+	// Progress events carry the response without the output streamed so far.
+	if len(a.response.Output) == 0 {
+		a.response.Output = output
+	}
+}
+
+// item returns the output item at index, or an empty item if there is none,
+// so that the variant fields of the result can always be inspected.
+func (a *ResponseStreamAccumulator) item(index int) *ResponseOutput {
+	if index < 0 || index >= len(a.response.Output) {
+		return &ResponseOutput{}
+	}
+	return &a.response.Output[index]
+}
+
+func (a *ResponseStreamAccumulator) setItem(event ResponseStreamEvent) error {
+	var payload struct {
+		Item *ResponseOutput `json:"item"`
+	}
+	raw := event.Raw
+	if len(raw) == 0 {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		raw = data
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return fmt.Errorf("decoding output item: %w", err)
+	}
+	if payload.Item == nil || event.OutputIndex < 0 {
+		return nil
+	}
+
+	for len(a.response.Output) <= event.OutputIndex {
+		a.response.Output = append(a.response.Output, ResponseOutput{})
+	}
+	a.response.Output[event.OutputIndex] = *payload.Item
+	if event.Type == ResponseStreamEventOutputItemDone && a.OnOutputItemDone != nil {
+		a.OnOutputItemDone(*payload.Item)
+	}
+	return nil
+}
+
+func (a *ResponseStreamAccumulator) setContentPart(event ResponseStreamEvent) {
+	if event.Part == nil || event.ContentIndex < 0 {
+		return
+	}
+	item := a.item(event.OutputIndex)
+	switch {
+	case item.Message != nil:
+		for len(item.Message.Content) <= event.ContentIndex {
+			item.Message.Content = append(item.Message.Content, ResponseOutputContent{})
+		}
+		item.Message.Content[event.ContentIndex] = *event.Part
+	case item.Reasoning != nil:
+		for len(item.Reasoning.Content) <= event.ContentIndex {
+			item.Reasoning.Content = append(item.Reasoning.Content, ResponseReasoningContent{})
+		}
+		item.Reasoning.Content[event.ContentIndex] = ResponseReasoningContent{Type: event.Part.Type, Text: event.Part.Text}
+	}
+}
+
+func (a *ResponseStreamAccumulator) messageContent(event ResponseStreamEvent) *ResponseOutputContent {
+	message := a.item(event.OutputIndex).Message
+	if message == nil || event.ContentIndex < 0 || event.ContentIndex >= len(message.Content) {
+		return nil
+	}
+	return &message.Content[event.ContentIndex]
+}
+
+func (a *ResponseStreamAccumulator) reasoningSummary(event ResponseStreamEvent) *ResponseSummaryPart {
+	reasoning := a.item(event.OutputIndex).Reasoning
+	if reasoning == nil || event.SummaryIndex < 0 {
+		return nil
+	}
+	for len(reasoning.Summary) <= event.SummaryIndex {
+		reasoning.Summary = append(reasoning.Summary, ResponseSummaryPart{Type: "summary_text"})
+	}
+	return &reasoning.Summary[event.SummaryIndex]
+}
+
+func (a *ResponseStreamAccumulator) reasoningContent(event ResponseStreamEvent) *ResponseReasoningContent {
+	reasoning := a.item(event.OutputIndex).Reasoning
+	if reasoning == nil || event.ContentIndex < 0 {
+		return nil
+	}
+	for len(reasoning.Content) <= event.ContentIndex {
+		reasoning.Content = append(reasoning.Content, ResponseReasoningContent{Type: "reasoning_text"})
+	}
+	return &reasoning.Content[event.ContentIndex]
+}
@@ -0,0 +1,193 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const accumulatedResponse = `{"id":"resp_1","object":"response","created_at":1741476542,"status":"completed",` +
+	`"model":"gpt-4o","output":[` +
+	`{"id":"rs_1","type":"reasoning","summary":[{"type":"summary_text","text":"Look it up."}]},` +
+	`{"id":"msg_1","type":"message","role":"assistant","status":"completed","content":[` +
+	`{"type":"output_text","text":"Sunny in Paris.","annotations":[` +
+	`{"type":"url_citation","url":"https://example.com","start_index":0,"end_index":5}]}]},` +
+	`{"id":"fc_1","type":"function_call","call_id":"call_1","name":"get_weather",` +
+	`"arguments":"{\"city\":\"Paris\"}","status":"completed"}],` +
+	`"usage":{"input_tokens":5,"output_tokens":9,"total_tokens":14}}`
+
+var accumulatedResponseEvents = []string{
+	`{"type":"response.created","sequence_number":0,"response":{"id":"resp_1","object":"response",` +
+		`"created_at":1741476542,"status":"in_progress","model":"gpt-4o","output":[]}}`,
+	`{"type":"response.output_item.added","output_index":0,"item":{"id":"rs_1","type":"reasoning","summary":[]}}`,
+	`{"type":"response.reasoning_summary_part.added","output_index":0,"summary_index":0,` +
+		`"part":{"type":"summary_text","text":""}}`,
+	`{"type":"response.reasoning_summary_text.delta","output_index":0,"summary_index":0,"delta":"Look "}`,
+	`{"type":"response.reasoning_summary_text.delta","output_index":0,"summary_index":0,"delta":"it up."}`,
+	`{"type":"response.reasoning_summary_text.done","output_index":0,"summary_index":0,"text":"Look it up."}`,
+	`{"type":"response.output_item.done","output_index":0,"item":{"id":"rs_1","type":"reasoning",` +
+		`"summary":[{"type":"summary_text","text":"Look it up."}]}}`,
+	`{"type":"response.output_item.added","output_index":1,"item":{"id":"msg_1","type":"message",` +
+		`"role":"assistant","status":"in_progress","content":[]}}`,
+	`{"type":"response.content_part.added","output_index":1,"content_index":0,` +
+		`"part":{"type":"output_text","text":"","annotations":[]}}`,
+	`{"type":"response.output_text.delta","output_index":1,"content_index":0,"delta":"Sunny "}`,
+	`{"type":"response.output_text.delta","output_index":1,"content_index":0,"delta":"in Paris."}`,
+	`{"type":"response.output_text.annotation.added","output_index":1,"content_index":0,"annotation_index":0,` +
+		`"annotation":{"type":"url_citation","url":"https://example.com","start_index":0,"end_index":5}}`,
+	`{"type":"response.output_text.done","output_index":1,"content_index":0,"text":"Sunny in Paris."}`,
+	`{"type":"response.output_item.added","output_index":2,"item":{"id":"fc_1","type":"function_call",` +
+		`"call_id":"call_1","name":"get_weather","arguments":"","status":"in_progress"}}`,
+	`{"type":"response.function_call_arguments.delta","output_index":2,"delta":"{\"city\":"}`,
+	`{"type":"response.function_call_arguments.delta","output_index":2,"delta":"\"Paris\"}"}`,
+	`{"type":"response.function_call_arguments.done","output_index":2,"arguments":"{\"city\":\"Paris\"}"}`,
+}
+
+func TestResponseStreamAccumulator(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range accumulatedResponseEvents {
+			fmt.Fprintf(w, "data: %s\n\n", event)
+		}
+		// Status updates of the message and function call items are only
+		// reflected in the final response.
+		fmt.Fprintf(w, "data: {\"type\":\"response.completed\",\"response\":%s}\n\n", accumulatedResponse)
+	})
+
+	stream, err := client.CreateResponseStream(context.Background(), openai.CreateResponseRequest{
+		Model: openai.GPT4o,
+		Input: "Weather in Paris?",
+	})
+	checks.NoError(t, err, "CreateResponseStream error")
+	defer stream.Close()
+
+	var (
+		texts, arguments, summaries []string
+		done                        []openai.ResponseOutputType
+	)
+	accumulator := openai.NewResponseStreamAccumulator()
+	accumulator.OnOutputTextDelta = func(_ openai.ResponseStreamEvent, text string) {
+		texts = append(texts, text)
+	}
+	accumulator.OnFunctionCallArgumentsDelta = func(_ openai.ResponseStreamEvent, args string) {
+		arguments = append(arguments, args)
+	}
+	accumulator.OnReasoningSummaryTextDelta = func(_ openai.ResponseStreamEvent, text string) {
+		summaries = append(summaries, text)
+	}
+	accumulator.OnOutputItemDone = func(item openai.ResponseOutput) {
+		done = append(done, item.Type)
+	}
+
+	for range accumulatedResponseEvents {
+		event, recvErr := stream.Recv()
+		checks.NoError(t, recvErr, "Recv error")
+		checks.NoError(t, accumulator.Add(event), "Add error")
+	}
+	if accumulator.Done() {
+		t.Fatal("accumulator should not be done before response.completed")
+	}
+	snapshot := accumulator.Response()
+	if snapshot.Status != openai.ResponseStatusInProgress || snapshot.GetOutputText() != "Sunny in Paris." {
+		t.Errorf("unexpected snapshot: %+v", snapshot)
+	}
+	calls := snapshot.FunctionCalls()
+	if len(calls) != 1 || calls[0].Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected function calls in snapshot: %+v", calls)
+	}
+	if citations := snapshot.Citations(); len(citations) != 1 || citations[0].EndIndex != 5 {
+		t.Errorf("unexpected citations in snapshot: %+v", citations)
+	}
+	reasoning := snapshot.ReasoningItems()
+	if len(reasoning) != 1 || reasoning[0].Summary[0].Text != "Look it up." {
+		t.Errorf("unexpected reasoning in snapshot: %+v", reasoning)
+	}
+
+	final, err := accumulator.Consume(stream)
+	checks.NoError(t, err, "Consume error")
+	var expected openai.CreateResponseResponse
+	checks.NoError(t, json.Unmarshal([]byte(accumulatedResponse), &expected), "unmarshal error")
+	if !reflect.DeepEqual(final, expected) {
+		t.Errorf("final response differs from the non-streaming response:\n%+v\n%+v", final, expected)
+	}
+
+	if strings.Join(texts, "|") != "Sunny |Sunny in Paris." {
+		t.Errorf("unexpected text callbacks: %q", texts)
+	}
+	if strings.Join(arguments, "|") != `{"city":|{"city":"Paris"}` {
+		t.Errorf("unexpected argument callbacks: %q", arguments)
+	}
+	if strings.Join(summaries, "|") != "Look |Look it up." {
+		t.Errorf("unexpected summary callbacks: %q", summaries)
+	}
+	if fmt.Sprint(done) != "[reasoning]" {
+		t.Errorf("unexpected done callbacks: %v", done)
+	}
+}
+
+func TestResponseStreamAccumulatorSnapshotsAreCopies(t *testing.T) {
+	accumulator := openai.NewResponseStreamAccumulator()
+	for _, raw := range accumulatedResponseEvents[7:10] {
+		var event openai.ResponseStreamEvent
+		checks.NoError(t, json.Unmarshal([]byte(raw), &event), "unmarshal error")
+		checks.NoError(t, accumulator.Add(event), "Add error")
+	}
+	snapshot := accumulator.Response()
+
+	var event openai.ResponseStreamEvent
+	checks.NoError(t, json.Unmarshal([]byte(accumulatedResponseEvents[10]), &event), "unmarshal error")
+	checks.NoError(t, accumulator.Add(event), "Add error")
+
+	if snapshot.GetOutputText() != "Sunny " || accumulator.Response().GetOutputText() != "Sunny in Paris." {
+		t.Errorf("snapshot should not change: %q", snapshot.GetOutputText())
+	}
+}
+
+func TestResponseStreamAccumulatorPartialImage(t *testing.T) {
+	accumulator := openai.NewResponseStreamAccumulator()
+	var partials []string
+	accumulator.OnPartialImage = func(event openai.ResponseStreamEvent) {
+		partials = append(partials, event.PartialImageB64)
+	}
+	for _, raw := range []string{
+		`{"type":"response.output_item.added","output_index":0,` +
+			`"item":{"id":"ig_1","type":"image_generation_call","status":"in_progress"}}`,
+		`{"type":"response.image_generation_call.generating","output_index":0,"item_id":"ig_1"}`,
+		`{"type":"response.image_generation_call.partial_image","output_index":0,"partial_image_index":0,` +
+			`"partial_image_b64":"AAAA"}`,
+	} {
+		var event openai.ResponseStreamEvent
+		checks.NoError(t, json.Unmarshal([]byte(raw), &event), "unmarshal error")
+		checks.NoError(t, accumulator.Add(event), "Add error")
+	}
+
+	call := accumulator.Response().Output[0].ImageGenerationCall
+	if call == nil || call.Result != "AAAA" || call.Status != "generating" {
+		t.Errorf("unexpected image generation call: %+v", call)
+	}
+	if fmt.Sprint(partials) != "[AAAA]" {
+		t.Errorf("unexpected partial image callbacks: %v", partials)
+	}
+}
+
+func TestResponseStreamAccumulatorError(t *testing.T) {
+	var event openai.ResponseStreamEvent
+	err := json.Unmarshal([]byte(`{"type":"error","code":"server_error","message":"boom"}`), &event)
+	checks.NoError(t, err, "unmarshal error")
+
+	err = openai.NewResponseStreamAccumulator().Add(event)
+	var responseErr *openai.ResponseError
+	if !errors.As(err, &responseErr) || responseErr.Code != "server_error" {
+		t.Fatalf("expected ResponseError, got %v", err)
+	}
+}
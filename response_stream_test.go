@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
 	"github.com/sashabaranov/go-openai/internal/test/checks"
 )
 
@@ -133,3 +136,198 @@ func TestCreateResponseStreamMalformedEvent(t *testing.T) {
 		t.Fatalf("expected JSON syntax error, got %T: %v", err, err)
 	}
 }
+
+func writeResponseEvents(w http.ResponseWriter, events ...string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, event := range events {
+		fmt.Fprintf(w, "data: %s\n\n", event)
+	}
+}
+
+func responseTextDeltaEvent(sequence int, delta string) string {
+	return fmt.Sprintf(`{"type":"response.output_text.delta","sequence_number":%d,`+
+		`"item_id":"msg_1","output_index":0,"content_index":0,"delta":%q}`, sequence, delta)
+}
+
+const (
+	responseCreatedEvent = `{"type":"response.created","sequence_number":0,` +
+		`"response":{"id":"resp_1","object":"response","status":"in_progress","output":[]}}`
+	responseItemAddedEvent = `{"type":"response.output_item.added","sequence_number":1,"output_index":0,` +
+		`"item":{"id":"msg_1","type":"message","role":"assistant","status":"in_progress","content":[]}}`
+	responsePartAddedEvent = `{"type":"response.content_part.added","sequence_number":2,"item_id":"msg_1",` +
+		`"output_index":0,"content_index":0,"part":{"type":"output_text","text":"","annotations":[]}}`
+	responseCompletedEvent = `{"type":"response.completed","sequence_number":5,` +
+		`"response":{"id":"resp_1","object":"response","status":"completed","output":[]}}`
+)
+
+func TestResponseStreamResumesBackgroundResponse(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, _ *http.Request) {
+		writeResponseEvents(w, responseCreatedEvent, responseItemAddedEvent, responsePartAddedEvent,
+			responseTextDeltaEvent(3, "Hel"))
+		// The connection drops in the middle of an event.
+		fmt.Fprint(w, `data: {"type":"response.output_text.delta","sequ`)
+	})
+	var queries []string
+	server.RegisterHandler("/v1/responses/resp_1", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if len(queries) == 1 {
+			// Events may be replayed, and the connection drops again.
+			writeResponseEvents(w, responseTextDeltaEvent(3, "Hel"), responseTextDeltaEvent(4, "lo"))
+			return
+		}
+		writeResponseEvents(w, responseCompletedEvent)
+	})
+
+	stream, err := client.CreateResponseStream(context.Background(), openai.CreateResponseRequest{
+		Model:      openai.GPT4o,
+		Input:      "Hello",
+		Background: true,
+	})
+	checks.NoError(t, err, "CreateResponseStream error")
+	defer stream.Close()
+
+	var (
+		sequences []int
+		text      string
+	)
+	accumulator := openai.NewResponseStreamAccumulator()
+	accumulator.OnEvent = func(event openai.ResponseStreamEvent) {
+		sequences = append(sequences, event.SequenceNumber)
+		text += event.Delta
+	}
+	response, err := accumulator.Consume(stream)
+	checks.NoError(t, err, "Consume error")
+
+	if fmt.Sprint(sequences) != "[0 1 2 3 4 5]" {
+		t.Errorf("every event should be received once, got sequence numbers %v", sequences)
+	}
+	if text != "Hello" || response.Status != openai.ResponseStatusCompleted {
+		t.Errorf("unexpected response %q: %+v", text, response)
+	}
+	if fmt.Sprint(queries) != "[starting_after=3&stream=true starting_after=4&stream=true]" {
+		t.Errorf("unexpected resume queries: %v", queries)
+	}
+}
+
+// newResumeTestClient creates a client that resumes response streams at most
+// maxReconnects times, where zero means the default.
+func newResumeTestClient(t *testing.T, maxReconnects int) (*openai.Client, *test.ServerTest) {
+	t.Helper()
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	t.Cleanup(ts.Close)
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.RetryPolicy = &openai.RetryPolicy{MaxAttempts: 1, InitialBackoff: time.Millisecond}
+	config.ResponseStreamMaxReconnects = maxReconnects
+	return openai.NewClientWithConfig(config), server
+}
+
+func TestResponseStreamResumeLimits(t *testing.T) {
+	for _, tc := range []struct {
+		maxReconnects int
+		want          int
+	}{{0, 3}, {1, 1}, {-1, 0}} {
+		client, server := newResumeTestClient(t, tc.maxReconnects)
+		server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, _ *http.Request) {
+			writeResponseEvents(w, responseCreatedEvent)
+		})
+		var retrievals int
+		server.RegisterHandler("/v1/responses/resp_1", func(w http.ResponseWriter, _ *http.Request) {
+			retrievals++
+			writeResponseEvents(w)
+		})
+
+		for _, background := range []bool{false, true} {
+			stream, err := client.CreateResponseStream(context.Background(), openai.CreateResponseRequest{
+				Input:      "Hello",
+				Background: background,
+			})
+			checks.NoError(t, err, "CreateResponseStream error")
+			_, err = stream.Recv()
+			checks.NoError(t, err, "Recv error")
+			_, err = stream.Recv()
+			if !errors.Is(err, io.EOF) {
+				t.Errorf("expected io.EOF, got %v", err)
+			}
+			stream.Close()
+		}
+		if retrievals != tc.want {
+			t.Errorf("ResponseStreamMaxReconnects=%d: expected %d reconnects of the background stream only, got %d",
+				tc.maxReconnects, tc.want, retrievals)
+		}
+	}
+}
+
+func TestResponseStreamRetriesFailedReconnects(t *testing.T) {
+	client, server := newResumeTestClient(t, 0)
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, _ *http.Request) {
+		writeResponseEvents(w, responseCreatedEvent)
+	})
+	var retrievals int
+	server.RegisterHandler("/v1/responses/resp_1", func(w http.ResponseWriter, _ *http.Request) {
+		retrievals++
+		if retrievals < 3 {
+			// The connection fails before a response is sent.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		writeResponseEvents(w, responseCompletedEvent)
+	})
+
+	stream, err := client.CreateResponseStream(context.Background(), openai.CreateResponseRequest{
+		Input:      "Hello",
+		Background: true,
+	})
+	checks.NoError(t, err, "CreateResponseStream error")
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	event, err := stream.Recv()
+	checks.NoError(t, err, "Recv should retry failed reconnects")
+	if event.Type != openai.ResponseStreamEventCompleted || retrievals != 3 {
+		t.Errorf("unexpected event %s after %d reconnect attempts", event.Type, retrievals)
+	}
+}
+
+func TestRetrieveResponseStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/responses/resp_1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet ||
+			r.URL.RawQuery != "include=message.output_text.logprobs&starting_after=1&stream=true" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		// Events up to starting_after are not expected, but are skipped.
+		writeResponseEvents(w, responseItemAddedEvent, responsePartAddedEvent, responseCompletedEvent)
+	})
+
+	startingAfter := 1
+	stream, err := client.RetrieveResponseStream(context.Background(), "resp_1", openai.RetrieveResponseOptions{
+		Include:       []openai.ResponseInclude{openai.ResponseIncludeMessageOutputTextLogprobs},
+		StartingAfter: &startingAfter,
+	})
+	checks.NoError(t, err, "RetrieveResponseStream error")
+	defer stream.Close()
+
+	var sequences []int
+	for {
+		event, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		sequences = append(sequences, event.SequenceNumber)
+	}
+	if fmt.Sprint(sequences) != "[2 5]" {
+		t.Errorf("unexpected sequence numbers: %v", sequences)
+	}
+}
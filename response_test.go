@@ -117,10 +117,11 @@ func TestCreateResponse(t *testing.T) { //nolint:gocognit
 func TestCreateResponseUsesOutputTextConvenienceField(t *testing.T) {
 	response := openai.CreateResponseResponse{
 		OutputText: "convenience text",
-		Output: []any{map[string]any{
-			"type": "message",
-			"content": []any{
-				map[string]any{"type": "output_text", "text": "nested text"},
+		Output: []openai.ResponseOutput{{
+			Type: openai.ResponseOutputTypeMessage,
+			Message: &openai.ResponseOutputMessage{
+				Type:    openai.ResponseOutputTypeMessage,
+				Content: []openai.ResponseOutputContent{{Type: "output_text", Text: "nested text"}},
 			},
 		}},
 	}
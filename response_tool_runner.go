@@ -0,0 +1,216 @@
+package openai
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrResponseToolRunnerInput is returned when the input of a request that is
+// not stored is neither a string nor a slice of ResponseInputItem, so it
+// cannot be replayed.
+var ErrResponseToolRunnerInput = errors.New("response tool runner cannot replay the request input")
+
+// ResponseToolRunner runs the tool-calling loop of the Responses API: it
+// creates a response, executes the function calls in its output with the
+// registered handlers and creates the next response with their outputs, until
+// the model answers without calling functions.
+//
+// Stored responses are chained with PreviousResponseID, or through the
+// request's Conversation when one is set. When Store is false the state is
+// kept on the client instead: every request replays the original input, the
+// output items of the earlier responses and the function call outputs.
+// Reasoning items are replayed with their encrypted content, which is added
+// to the request's Include; those returned without it are dropped.
+type ResponseToolRunner struct {
+	client *Client
+	config ToolRunnerConfig
+	tools  map[string]FunctionTool
+	order  []string
+}
+
+// ResponseToolRunResult is the outcome of a ResponseToolRunner run.
+type ResponseToolRunResult struct {
+	// Response is the last response, which holds the final answer unless
+	// the run stopped early.
+	Response CreateResponseResponse
+	// Input is the input of the last request. When Store is false it is the
+	// whole conversation up to Response.
+	Input []ResponseInputItem
+	// Iterations is the number of responses that were created.
+	Iterations int
+	// ToolCalls is the number of function calls that were executed.
+	ToolCalls int
+	// Usage is the total token usage of all responses.
+	Usage ResponseUsage
+}
+
+// NewResponseToolRunner creates a ResponseToolRunner executing the given tools.
+func NewResponseToolRunner(client *Client, config ToolRunnerConfig, tools ...FunctionTool) *ResponseToolRunner {
+	runner := &ResponseToolRunner{
+		client: client,
+		config: config,
+		tools:  make(map[string]FunctionTool),
+	}
+	for _, tool := range tools {
+		runner.Register(tool)
+	}
+	return runner
+}
+
+// Register adds a tool, replacing any tool with the same name.
+func (r *ResponseToolRunner) Register(tool FunctionTool) {
+	if _, ok := r.tools[tool.Definition.Name]; !ok {
+		r.order = append(r.order, tool.Definition.Name)
+	}
+	r.tools[tool.Definition.Name] = tool
+}
+
+// Run executes the tool-calling loop for request. The registered tools are
+// added to request.Tools unless a function tool of the same name is already
+// present. request.MaxToolCalls is sent to the API unchanged; the number of
+// function calls per run is limited by ToolRunnerConfig.MaxToolCalls.
+func (r *ResponseToolRunner) Run(ctx context.Context, request CreateResponseRequest) (ResponseToolRunResult, error) {
+	var result ResponseToolRunResult
+	request.Tools = r.requestTools(request.Tools)
+	stored := request.Store == nil || *request.Store
+	if !stored {
+		input, err := replayableResponseInput(request.Input)
+		if err != nil {
+			return result, err
+		}
+		request.Input = input
+		result.Input = input
+		request.Include = includeReasoningEncryptedContent(request.Include)
+	}
+	parallel := request.ParallelToolCalls == nil || *request.ParallelToolCalls
+
+	maxIterations := r.config.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = defaultToolRunnerMaxIterations
+	}
+	for result.Iterations < maxIterations {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		response, err := r.client.CreateResponse(ctx, request)
+		if err != nil {
+			return result, err
+		}
+		result.Response = response
+		result.Iterations++
+		if response.Usage != nil {
+			addResponseUsage(&result.Usage, *response.Usage)
+		}
+
+		functionCalls := response.FunctionCalls()
+		if len(functionCalls) == 0 {
+			return result, nil
+		}
+		if r.config.exceedsMaxToolCalls(result.ToolCalls + len(functionCalls)) {
+			return result, ErrToolRunnerMaxToolCalls
+		}
+
+		calls := make([]ToolCall, len(functionCalls))
+		for i, call := range functionCalls {
+			calls[i] = ToolCall{
+				ID:       call.CallID,
+				Type:     ToolTypeFunction,
+				Function: FunctionCall{Name: call.Name, Arguments: call.Arguments},
+			}
+		}
+		outputs, err := runFunctionTools(ctx, r.tools, r.config, calls, parallel)
+		if err != nil {
+			return result, err
+		}
+		result.ToolCalls += len(calls)
+
+		builder := NewResponseInputBuilder()
+		if stored {
+			if request.Conversation == nil {
+				request.PreviousResponseID = response.ID
+			}
+		} else {
+			builder.Item(result.Input...).Output(replayableResponseOutput(response.Output)...)
+		}
+		for i, call := range calls {
+			builder.FunctionCallOutput(call.ID, outputs[i])
+		}
+		input, err := builder.Build()
+		if err != nil {
+			return result, err
+		}
+		request.Input = input
+		result.Input = input
+	}
+	return result, ErrToolRunnerMaxIterations
+}
+
+func (r *ResponseToolRunner) requestTools(tools []ResponseTool) []ResponseTool {
+	present := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if tool.Type != ToolTypeFunction {
+			continue
+		}
+		if tool.Function != nil {
+			present[tool.Function.Name] = true
+		} else if name, ok := tool.Parameters["name"].(string); ok {
+			present[name] = true
+		}
+	}
+	tools = append([]ResponseTool(nil), tools...)
+	for _, name := range r.order {
+		if !present[name] {
+			tools = append(tools, NewResponseFunctionTool(r.tools[name].Definition))
+		}
+	}
+	return tools
+}
+
+// replayableResponseInput converts the input of a request into items that can
+// be sent again together with later items.
+func replayableResponseInput(input any) ([]ResponseInputItem, error) {
+	builder := NewResponseInputBuilder()
+	switch input := input.(type) {
+	case nil:
+	case string:
+		builder.User(input)
+	case []ResponseInputItem:
+		builder.Item(input...)
+	default:
+		return nil, ErrResponseToolRunnerInput
+	}
+	return builder.Build()
+}
+
+// includeReasoningEncryptedContent adds ResponseIncludeReasoningEncryptedContent
+// to include, so that the reasoning items of responses that are not stored
+// can be replayed.
+func includeReasoningEncryptedContent(include []ResponseInclude) []ResponseInclude {
+	for _, value := range include {
+		if value == ResponseIncludeReasoningEncryptedContent {
+			return include
+		}
+	}
+	return append(append([]ResponseInclude(nil), include...), ResponseIncludeReasoningEncryptedContent)
+}
+
+// replayableResponseOutput returns the output items that can be sent back as
+// input. Reasoning items of responses that are not stored are rejected unless
+// they carry their encrypted content, so the others are dropped.
+func replayableResponseOutput(output []ResponseOutput) []ResponseOutput {
+	replayable := make([]ResponseOutput, 0, len(output))
+	for _, item := range output {
+		if item.Reasoning != nil && item.Reasoning.EncryptedContent == "" {
+			continue
+		}
+		replayable = append(replayable, item)
+	}
+	return replayable
+}
+
+func addResponseUsage(total *ResponseUsage, usage ResponseUsage) {
+	total.InputTokens += usage.InputTokens
+	total.OutputTokens += usage.OutputTokens
+	total.TotalTokens += usage.TotalTokens
+}
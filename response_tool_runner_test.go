@@ -0,0 +1,223 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const (
+	responseFunctionCallsOutput = `{"id":"resp_1","object":"response","status":"completed","output":[` +
+		`{"type":"reasoning","id":"rs_1","summary":[]},` +
+		`{"type":"function_call","id":"fc_1","call_id":"call_1","name":"get_weather","arguments":"{\"city\":\"Paris\"}"},` +
+		`{"type":"function_call","id":"fc_2","call_id":"call_2","name":"get_time","arguments":"{}"}],` +
+		`"usage":{"input_tokens":3,"output_tokens":2,"total_tokens":5}}`
+	responseAnswerOutput = `{"id":"resp_2","object":"response","status":"completed","output":[` +
+		`{"type":"message","id":"msg_1","role":"assistant","status":"completed",` +
+		`"content":[{"type":"output_text","text":"Sunny.","annotations":[]}]}],` +
+		`"usage":{"input_tokens":4,"output_tokens":1,"total_tokens":5}}`
+)
+
+// registerResponses serves the responses in order and records the request bodies.
+func registerResponses(server *test.ServerTest, requests *[]map[string]any, responses ...string) {
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		*requests = append(*requests, request)
+		if len(*requests) > len(responses) {
+			http.Error(w, "unexpected request", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, responses[len(*requests)-1])
+	})
+}
+
+func TestResponseToolRunner(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []map[string]any
+	registerResponses(server, &requests, responseFunctionCallsOutput, responseAnswerOutput)
+
+	var calls int32
+	runner := openai.NewResponseToolRunner(client, openai.ToolRunnerConfig{}, weatherTool(&calls))
+	result, err := runner.Run(context.Background(), openai.CreateResponseRequest{
+		Model: openai.GPT4o,
+		Input: "Weather?",
+	})
+	checks.NoError(t, err, "Run error")
+
+	if result.Iterations != 2 || result.ToolCalls != 2 || result.Usage.TotalTokens != 10 || calls != 1 {
+		t.Errorf("unexpected result: %+v with %d handler calls", result, calls)
+	}
+	if result.Response.GetOutputText() != "Sunny." {
+		t.Errorf("unexpected final response: %+v", result.Response)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	tools, _ := requests[0]["tools"].([]any)
+	if len(tools) != 1 || tools[0].(map[string]any)["name"] != "get_weather" {
+		t.Errorf("registered tools should be sent inline: %v", requests[0]["tools"])
+	}
+	if requests[1]["previous_response_id"] != "resp_1" {
+		t.Errorf("stored responses should be chained: %v", requests[1])
+	}
+	input, _ := requests[1]["input"].([]any)
+	want := []string{"sunny in Paris", `Error: unknown tool "get_time"`}
+	if len(input) != len(want) {
+		t.Fatalf("only the function call outputs should be sent: %v", requests[1]["input"])
+	}
+	for i, output := range want {
+		item := input[i].(map[string]any)
+		if item["type"] != "function_call_output" || item["call_id"] != fmt.Sprintf("call_%d", i+1) ||
+			item["output"] != output {
+			t.Errorf("unexpected function call output %d: %v", i, item)
+		}
+	}
+}
+
+func TestResponseToolRunnerClientState(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []map[string]any
+	registerResponses(server, &requests, responseFunctionCallsOutput, responseAnswerOutput)
+
+	var calls int32
+	store := false
+	runner := openai.NewResponseToolRunner(client, openai.ToolRunnerConfig{}, weatherTool(&calls))
+	result, err := runner.Run(context.Background(), openai.CreateResponseRequest{
+		Model: openai.GPT4o,
+		Input: "Weather?",
+		Store: &store,
+		Tools: []openai.ResponseTool{openai.NewResponseFunctionTool(openai.FunctionDefinition{
+			Name:       "get_weather",
+			Parameters: json.RawMessage(`{"type":"object"}`),
+		})},
+	})
+	checks.NoError(t, err, "Run error")
+
+	if tools, _ := requests[0]["tools"].([]any); len(tools) != 1 {
+		t.Errorf("tools already in the request should not be added again: %v", requests[0]["tools"])
+	}
+	if _, ok := requests[1]["previous_response_id"]; ok {
+		t.Errorf("responses that are not stored should not be chained: %v", requests[1])
+	}
+	input, _ := requests[1]["input"].([]any)
+	var types []string
+	for _, item := range input {
+		types = append(types, fmt.Sprint(item.(map[string]any)["type"]))
+	}
+	// The reasoning item has no encrypted content and cannot be replayed.
+	wantTypes := "[message function_call function_call function_call_output function_call_output]"
+	if fmt.Sprint(types) != wantTypes {
+		t.Errorf("the conversation should be replayed, got item types %v", types)
+	}
+	if fmt.Sprint(requests[0]["include"]) != "[reasoning.encrypted_content]" {
+		t.Errorf("encrypted reasoning content should be requested: %v", requests[0]["include"])
+	}
+	if len(result.Input) != 5 {
+		t.Errorf("unexpected result input: %+v", result.Input)
+	}
+
+	_, err = runner.Run(context.Background(), openai.CreateResponseRequest{
+		Input: []any{map[string]any{"role": "user", "content": "Weather?"}},
+		Store: &store,
+	})
+	if !errors.Is(err, openai.ErrResponseToolRunnerInput) {
+		t.Errorf("expected ErrResponseToolRunnerInput, got %v", err)
+	}
+}
+
+func TestResponseToolRunnerReplaysEncryptedReasoning(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []map[string]any
+	registerResponses(server, &requests, `{"id":"resp_1","object":"response","status":"completed","output":[`+
+		`{"type":"reasoning","id":"rs_1","summary":[],"encrypted_content":"gAAA"},`+
+		`{"type":"function_call","id":"fc_1","call_id":"call_1","name":"get_weather","arguments":"{\"city\":\"Paris\"}"}]}`,
+		responseAnswerOutput)
+
+	var calls int32
+	store := false
+	runner := openai.NewResponseToolRunner(client, openai.ToolRunnerConfig{}, weatherTool(&calls))
+	_, err := runner.Run(context.Background(), openai.CreateResponseRequest{
+		Model:   openai.GPT4o,
+		Input:   "Weather?",
+		Store:   &store,
+		Include: []openai.ResponseInclude{openai.ResponseIncludeReasoningEncryptedContent},
+	})
+	checks.NoError(t, err, "Run error")
+
+	if fmt.Sprint(requests[0]["include"]) != "[reasoning.encrypted_content]" {
+		t.Errorf("Include should not be duplicated: %v", requests[0]["include"])
+	}
+	input, _ := requests[1]["input"].([]any)
+	if len(input) != 4 {
+		t.Fatalf("unexpected input: %v", requests[1]["input"])
+	}
+	reasoning := input[1].(map[string]any)
+	if reasoning["type"] != "reasoning" || reasoning["encrypted_content"] != "gAAA" {
+		t.Errorf("the reasoning item should be replayed with its encrypted content: %v", reasoning)
+	}
+}
+
+func TestResponseToolRunnerKeepsRequestMaxToolCalls(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []map[string]any
+	registerResponses(server, &requests, responseFunctionCallsOutput, responseAnswerOutput)
+
+	var calls int32
+	runner := openai.NewResponseToolRunner(client, openai.ToolRunnerConfig{}, weatherTool(&calls))
+	result, err := runner.Run(context.Background(), openai.CreateResponseRequest{Input: "Weather?", MaxToolCalls: 1})
+	checks.NoError(t, err, "Run error")
+	if result.ToolCalls != 2 {
+		t.Errorf("request.MaxToolCalls should not limit function calls, got %d calls", result.ToolCalls)
+	}
+	for i, request := range requests {
+		if request["max_tool_calls"] != float64(1) {
+			t.Errorf("request %d: max_tool_calls should be sent unchanged, got %v", i, request["max_tool_calls"])
+		}
+	}
+}
+
+func TestResponseToolRunnerLimits(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []map[string]any
+	registerResponses(server, &requests, responseFunctionCallsOutput, responseFunctionCallsOutput)
+
+	var calls int32
+	runner := openai.NewResponseToolRunner(client, openai.ToolRunnerConfig{MaxIterations: 1}, weatherTool(&calls))
+	result, err := runner.Run(context.Background(), openai.CreateResponseRequest{Input: "Weather?"})
+	if !errors.Is(err, openai.ErrToolRunnerMaxIterations) || result.Iterations != 1 {
+		t.Errorf("expected ErrToolRunnerMaxIterations after 1 iteration, got %v", err)
+	}
+
+	runner = openai.NewResponseToolRunner(client, openai.ToolRunnerConfig{MaxToolCalls: 1}, weatherTool(&calls))
+	result, err = runner.Run(context.Background(), openai.CreateResponseRequest{Input: "Weather?"})
+	if !errors.Is(err, openai.ErrToolRunnerMaxToolCalls) || result.ToolCalls != 0 {
+		t.Errorf("expected ErrToolRunnerMaxToolCalls before running tools, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = runner.Run(ctx, openai.CreateResponseRequest{Input: "Weather?"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(requests) != 2 {
+		t.Errorf("expected 2 requests, got %d", len(requests))
+	}
+}
@@ -0,0 +1,109 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const responseCancelTimeout = 10 * time.Second
+
+// WaitForResponseOptions configures WaitForResponse.
+type WaitForResponseOptions struct {
+	// Poll sets the delays between RetrieveResponse calls. It defaults to
+	// DefaultPollPolicy, and the delay starts over whenever the response
+	// changes status.
+	Poll *PollPolicy
+	// CancelOnContextDone cancels the response with CancelResponse when ctx
+	// is done before the response has finished, so that background work
+	// nobody waits for anymore does not keep running.
+	CancelOnContextDone bool
+}
+
+// ResponseStatusError is returned by WaitForResponse when a response ends
+// failed, incomplete or cancelled.
+type ResponseStatusError struct {
+	Response CreateResponseResponse
+}
+
+func (e *ResponseStatusError) Error() string {
+	response := e.Response
+	switch {
+	case response.Error != nil:
+		return fmt.Sprintf("response %s %s: %v", response.ID, response.Status, response.Error)
+	case response.IncompleteDetails != nil && response.IncompleteDetails.Reason != "":
+		return fmt.Sprintf("response %s %s: %s", response.ID, response.Status, response.IncompleteDetails.Reason)
+	default:
+		return fmt.Sprintf("response %s %s", response.ID, response.Status)
+	}
+}
+
+// Unwrap returns the error reported by the API, if any.
+func (e *ResponseStatusError) Unwrap() error {
+	if e.Response.Error == nil {
+		return nil
+	}
+	return e.Response.Error
+}
+
+// WaitForResponse polls a response, typically one created with Background
+// set, until it is completed, failed, incomplete or cancelled. It returns the
+// final response, with a *ResponseStatusError unless the response completed.
+// Polling stops with the context's error when ctx is done.
+func (c *Client) WaitForResponse(
+	ctx context.Context,
+	responseID string,
+	options ...WaitForResponseOptions,
+) (CreateResponseResponse, error) {
+	var opts WaitForResponseOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	response, err := c.waitForResponse(ctx, responseID, opts.Poll)
+	if err == nil || ctx.Err() == nil || !opts.CancelOnContextDone {
+		return response, err
+	}
+
+	// ctx is done, so the cancellation needs a context of its own.
+	cancelCtx, cancel := context.WithTimeout(context.Background(), responseCancelTimeout)
+	defer cancel()
+	cancelled, cancelErr := c.CancelResponse(cancelCtx, responseID)
+	if cancelErr != nil {
+		return response, fmt.Errorf("%w (cancelling response %s: %v)", err, responseID, cancelErr)
+	}
+	return cancelled, err
+}
+
+func (c *Client) waitForResponse(
+	ctx context.Context,
+	responseID string,
+	poll *PollPolicy,
+) (response CreateResponseResponse, err error) {
+	polls := 0
+	for {
+		var next CreateResponseResponse
+		next, err = c.RetrieveResponse(ctx, responseID)
+		if err != nil {
+			return response, err
+		}
+		if next.Status != response.Status {
+			polls = 0
+		}
+		response = next
+
+		switch response.Status {
+		case ResponseStatusCompleted:
+			return response, nil
+		case ResponseStatusFailed, ResponseStatusIncomplete, ResponseStatusCancelled:
+			return response, &ResponseStatusError{Response: response}
+		case ResponseStatusQueued, ResponseStatusInProgress, ResponseStatusCancelling:
+			polls++
+			if err = sleepContext(ctx, poll.delay(polls)); err != nil {
+				return response, err
+			}
+		default:
+			return response, fmt.Errorf("unexpected response status %q", response.Status)
+		}
+	}
+}
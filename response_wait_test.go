@@ -0,0 +1,97 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestWaitForResponse(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	statuses := []string{"queued", "in_progress", "in_progress", "completed"}
+	var retrievals int32
+	server.RegisterHandler("/v1/responses/resp_1", func(w http.ResponseWriter, _ *http.Request) {
+		i := atomic.AddInt32(&retrievals, 1) - 1
+		fmt.Fprintf(w, `{"id":"resp_1","object":"response","status":%q,"output":[]}`, statuses[i])
+	})
+
+	response, err := client.WaitForResponse(context.Background(), "resp_1",
+		openai.WaitForResponseOptions{Poll: fastPoll})
+	checks.NoError(t, err, "WaitForResponse error")
+	if response.Status != openai.ResponseStatusCompleted || retrievals != 4 {
+		t.Errorf("unexpected response %+v after %d retrievals", response, retrievals)
+	}
+}
+
+func TestWaitForResponseStatusErrors(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/responses/resp_failed", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"resp_failed","status":"failed","error":{"code":"server_error","message":"Boom."}}`)
+	})
+	server.RegisterHandler("/v1/responses/resp_incomplete", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"resp_incomplete","status":"incomplete",`+
+			`"incomplete_details":{"reason":"max_output_tokens"}}`)
+	})
+
+	response, err := client.WaitForResponse(context.Background(), "resp_failed")
+	var statusErr *openai.ResponseStatusError
+	if !errors.As(err, &statusErr) || response.Status != openai.ResponseStatusFailed {
+		t.Fatalf("expected a ResponseStatusError, got %v", err)
+	}
+	var apiErr *openai.ResponseError
+	if !errors.As(err, &apiErr) || apiErr.Code != "server_error" {
+		t.Errorf("the API error should be wrapped, got %v", err)
+	}
+	if err.Error() != "response resp_failed failed: server_error: Boom." {
+		t.Errorf("unexpected error message: %q", err.Error())
+	}
+
+	_, err = client.WaitForResponse(context.Background(), "resp_incomplete")
+	if !errors.As(err, &statusErr) || statusErr.Response.IncompleteDetails.Reason != "max_output_tokens" ||
+		err.Error() != "response resp_incomplete incomplete: max_output_tokens" {
+		t.Errorf("unexpected incomplete error: %v", err)
+	}
+}
+
+func TestWaitForResponseCancelOnContextDone(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/responses/resp_1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"resp_1","object":"response","status":"in_progress"}`)
+	})
+	var cancels int32
+	server.RegisterHandler("/v1/responses/resp_1/cancel", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+			return
+		}
+		atomic.AddInt32(&cancels, 1)
+		fmt.Fprint(w, `{"id":"resp_1","object":"response","status":"cancelled"}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	response, err := client.WaitForResponse(ctx, "resp_1", openai.WaitForResponseOptions{Poll: fastPoll})
+	if !errors.Is(err, context.DeadlineExceeded) || response.Status != openai.ResponseStatusInProgress || cancels != 0 {
+		t.Errorf("expected the deadline to stop polling without cancelling, got %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	response, err = client.WaitForResponse(ctx, "resp_1", openai.WaitForResponseOptions{
+		Poll:                fastPoll,
+		CancelOnContextDone: true,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || response.Status != openai.ResponseStatusCancelled || cancels != 1 {
+		t.Errorf("expected the response to be cancelled, got %v with %+v", err, response)
+	}
+}
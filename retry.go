@@ -0,0 +1,221 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff     = 8 * time.Second
+	defaultRetryMultiplier     = 2
+	defaultRetryJitter         = 0.25
+)
+
+// RetryPolicy configures automatic retries of failed API requests.
+// A nil policy in ClientConfig disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the computed exponential backoff. Delays requested by the
+	// server through Retry-After or x-ratelimit-reset-* headers are not capped.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the backoff grows by after each attempt.
+	Multiplier float64
+	// Jitter randomizes each backoff by up to the given fraction, e.g. 0.25
+	// yields a delay anywhere between 75% and 125% of the computed backoff.
+	Jitter float64
+	// RetryableStatusCodes lists the HTTP status codes that are retried.
+	RetryableStatusCodes []int
+	// RetryableError reports whether a transport error returned by the
+	// HTTPClient should be retried. When nil, every error except context
+	// cancellation and deadline expiry is retried.
+	RetryableError func(err error) bool
+}
+
+// DefaultRetryPolicy returns a policy that retries request timeouts, conflicts,
+// rate limits and server errors up to three times with exponential backoff.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    defaultRetryMaxAttempts,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+		Multiplier:     defaultRetryMultiplier,
+		Jitter:         defaultRetryJitter,
+		RetryableStatusCodes: []int{
+			http.StatusRequestTimeout,
+			http.StatusConflict,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p *RetryPolicy) isRetryableStatus(code int) bool {
+	for _, c := range p.RetryableStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) isRetryableError(err error) bool {
+	if p.RetryableError != nil {
+		return p.RetryableError(err)
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// backoff returns the jittered exponential delay after the given attempt,
+// counting from 1.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1) //nolint:gosec // jitter does not need a secure source
+	}
+	if d < 0 {
+		return 0
+	}
+	return time.Duration(d)
+}
+
+// delay returns how long to wait before the next attempt. Server hints take
+// precedence over the computed backoff.
+func (p *RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := serverRetryDelay(resp); ok {
+			return d
+		}
+	}
+	return p.backoff(attempt)
+}
+
+// serverRetryDelay extracts the delay requested by the server, either through
+// Retry-After (and its millisecond variant) or through the rate limit reset
+// headers of whichever limit has been exhausted.
+func serverRetryDelay(resp *http.Response) (time.Duration, bool) {
+	h := resp.Header
+	if ms, err := strconv.ParseFloat(h.Get("retry-after-ms"), 64); err == nil && ms >= 0 {
+		return time.Duration(ms * float64(time.Millisecond)), true
+	}
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			d := time.Until(t)
+			if d < 0 {
+				d = 0
+			}
+			return d, true
+		}
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	limits := newRateLimitHeaders(h)
+	var (
+		d  time.Duration
+		ok bool
+	)
+	if limits.RemainingRequests == 0 && limits.ResetRequests != "" {
+		d, ok = limits.ResetRequests.Duration(), true
+	}
+	if limits.RemainingTokens == 0 && limits.ResetTokens != "" {
+		if reset := limits.ResetTokens.Duration(); reset > d {
+			d = reset
+		}
+		ok = true
+	}
+	return d, ok
+}
+
+// doRequest sends req through the configured HTTPClient, retrying according to
+// the client's RetryPolicy. Request bodies are replayed through req.GetBody,
+// which http.NewRequest populates for the in-memory bodies built by
+// newRequest, including multipart forms; requests whose body cannot be
+// replayed are sent only once.
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	policy := c.config.RetryPolicy
+	if policy == nil || policy.MaxAttempts <= 1 {
+		return c.sendRateLimited(req)
+	}
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	attemptReq := req
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendRateLimited(attemptReq)
+
+		retry := false
+		if err != nil {
+			retry = !errors.Is(err, ErrRateLimitExceeded) && policy.isRetryableError(err)
+		} else if isFailureStatusCode(resp) {
+			retry = policy.isRetryableStatus(resp.StatusCode)
+		}
+		if !retry || !replayable || attempt >= policy.MaxAttempts {
+			return resp, err
+		}
+
+		wait := policy.delay(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if sleepErr := sleepContext(req.Context(), wait); sleepErr != nil {
+			return nil, sleepErr
+		}
+
+		attemptReq, err = rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rewindRequest returns a copy of req with a fresh body.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,265 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func setupRetryTestServer(policy *openai.RetryPolicy) (*openai.Client, *test.ServerTest, func()) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.RetryPolicy = policy
+	return openai.NewClientWithConfig(config), server, ts.Close
+}
+
+func fastRetryPolicy(attempts int) *openai.RetryPolicy {
+	policy := openai.DefaultRetryPolicy()
+	policy.MaxAttempts = attempts
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(fastRetryPolicy(3))
+	defer teardown()
+
+	calls := 0
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls < 3 {
+			http.Error(w, `{"error":{"message":"overloaded","type":"server_error"}}`, http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"id":"gpt-4o"}]}`)
+	})
+
+	models, err := client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+	if len(models.Models) != 1 || models.Models[0].ID != "gpt-4o" {
+		t.Fatalf("unexpected models: %+v", models.Models)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(fastRetryPolicy(2))
+	defer teardown()
+
+	calls := 0
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":{"message":"slow down","type":"rate_limit_error"}}`)
+	})
+
+	_, err := client.ListModels(context.Background())
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 APIError, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestRetrySkipsNonRetryableStatus(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(fastRetryPolicy(3))
+	defer teardown()
+
+	calls := 0
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"message":"bad request","type":"invalid_request_error"}}`)
+	})
+
+	_, err := client.ListModels(context.Background())
+	checks.HasError(t, err, "ListModels should fail")
+	if calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	policy := fastRetryPolicy(2)
+	client, server, teardown := setupRetryTestServer(policy)
+	defer teardown()
+
+	const wait = 50 * time.Millisecond
+	var first time.Time
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		if first.IsZero() {
+			first = time.Now()
+			w.Header().Set("retry-after-ms", "50")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if elapsed := time.Since(first); elapsed < wait {
+			t.Errorf("retried after %s, expected at least %s", elapsed, wait)
+		}
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	_, err := client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+}
+
+func TestRetryHonorsRateLimitReset(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(fastRetryPolicy(2))
+	defer teardown()
+
+	const wait = 50 * time.Millisecond
+	var first time.Time
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		if first.IsZero() {
+			first = time.Now()
+			w.Header().Set("x-ratelimit-remaining-requests", "10")
+			w.Header().Set("x-ratelimit-reset-requests", "10s")
+			w.Header().Set("x-ratelimit-remaining-tokens", "0")
+			w.Header().Set("x-ratelimit-reset-tokens", "50ms")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if elapsed := time.Since(first); elapsed < wait {
+			t.Errorf("retried after %s, expected at least %s", elapsed, wait)
+		}
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	_, err := client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+}
+
+func TestRetryReplaysMultipartBody(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(fastRetryPolicy(3))
+	defer teardown()
+
+	var bodies []string
+	server.RegisterHandler("/v1/files", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		checks.NoError(t, err, "ReadAll error")
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"id":"file-1","filename":"foo.jsonl"}`)
+	})
+
+	file, err := client.CreateFileBytes(context.Background(), openai.FileBytesRequest{
+		Name:    "foo.jsonl",
+		Bytes:   []byte(`{"prompt":"a"}`),
+		Purpose: openai.PurposeFineTune,
+	})
+	checks.NoError(t, err, "CreateFileBytes error")
+	if file.ID != "file-1" {
+		t.Fatalf("unexpected file: %+v", file)
+	}
+	if len(bodies) != 2 || bodies[0] == "" || bodies[0] != bodies[1] {
+		t.Fatalf("request body was not replayed: %q", bodies)
+	}
+}
+
+func TestRetryStreamConnection(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(fastRetryPolicy(3))
+	defer teardown()
+
+	calls := 0
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"hi"}}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	resp, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if resp.Choices[0].Delta.Content != "hi" {
+		t.Fatalf("unexpected chunk: %+v", resp)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	policy := fastRetryPolicy(3)
+	policy.InitialBackoff = time.Minute
+	policy.MaxBackoff = time.Minute
+	client, server, teardown := setupRetryTestServer(policy)
+	defer teardown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := client.ListModels(ctx)
+	checks.ErrorIs(t, err, context.DeadlineExceeded, "ListModels should stop on ctx deadline")
+	if calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestRetryTransportErrors(t *testing.T) {
+	errTransport := errors.New("connection reset")
+	attempts := 0
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.RetryPolicy = fastRetryPolicy(3)
+	config.HTTPClient = doerFunc(func(*http.Request) (*http.Response, error) {
+		attempts++
+		return nil, errTransport
+	})
+	client := openai.NewClientWithConfig(config)
+
+	_, err := client.ListModels(context.Background())
+	checks.ErrorIs(t, err, errTransport, "ListModels should return the transport error")
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+
+	attempts = 0
+	config.RetryPolicy.RetryableError = func(error) bool { return false }
+	client = openai.NewClientWithConfig(config)
+	_, err = client.ListModels(context.Background())
+	checks.ErrorIs(t, err, errTransport, "ListModels should return the transport error")
+	if attempts != 1 {
+		t.Fatalf("expected a single attempt, got %d", attempts)
+	}
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
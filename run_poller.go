@@ -0,0 +1,165 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const runPollerMessagesPageSize = 100
+
+// ErrRunPollerUnsupportedAction is returned when a run requires an action
+// other than submitting tool outputs.
+var ErrRunPollerUnsupportedAction = errors.New("run requires an unsupported action")
+
+// RunPollerConfig configures a RunPoller.
+type RunPollerConfig struct {
+	// Poll sets the delays between RetrieveRun calls. It defaults to
+	// DefaultPollPolicy, and the delay starts over whenever the run changes
+	// status.
+	Poll *PollPolicy
+	// Approve, when set, is consulted before each tool call is executed.
+	// Calls that are not approved are answered with an output saying so
+	// instead of being executed, and an error aborts polling.
+	Approve func(ctx context.Context, call ToolCall) (approved bool, err error)
+	// HandleError converts a failed tool call, including calls of unknown
+	// tools, into the submitted output. Returning an error aborts polling
+	// instead. By default the error message is submitted.
+	HandleError func(call ToolCall, err error) (string, error)
+}
+
+// RunPoller waits for Assistants API runs to finish. While a run requires
+// action, it executes the requested tool calls with the registered tools,
+// concurrently, and submits their outputs.
+type RunPoller struct {
+	client *Client
+	config RunPollerConfig
+	tools  map[string]FunctionTool
+}
+
+// RunPollResult is the outcome of polling a run.
+type RunPollResult struct {
+	// Run is the run in its final status.
+	Run Run
+	// Messages are the messages created by the run, oldest first.
+	Messages []Message
+	// ToolOutputSubmissions is the number of times tool outputs were submitted.
+	ToolOutputSubmissions int
+}
+
+// NewRunPoller creates a RunPoller executing the given tools.
+func NewRunPoller(client *Client, config RunPollerConfig, tools ...FunctionTool) *RunPoller {
+	poller := &RunPoller{
+		client: client,
+		config: config,
+		tools:  make(map[string]FunctionTool),
+	}
+	for _, tool := range tools {
+		poller.Register(tool)
+	}
+	return poller
+}
+
+// Register adds a tool, replacing any tool with the same name.
+func (p *RunPoller) Register(tool FunctionTool) {
+	p.tools[tool.Definition.Name] = tool
+}
+
+// CreateAndPoll creates a run and polls it until it has finished. The tools
+// available to the run are those of the assistant or request; registering a
+// tool with the poller only provides its handler.
+func (p *RunPoller) CreateAndPoll(ctx context.Context, threadID string, request RunRequest) (RunPollResult, error) {
+	run, err := p.client.CreateRun(ctx, threadID, request)
+	if err != nil {
+		return RunPollResult{}, err
+	}
+	return p.poll(ctx, threadID, run)
+}
+
+// Poll waits until the run is completed, failed, cancelled, expired or
+// incomplete, handling the required actions in between. A run that ends in
+// any of those statuses is not an error; check Run.Status and Run.LastError.
+// Polling stops with the context's error when ctx is done.
+func (p *RunPoller) Poll(ctx context.Context, threadID, runID string) (RunPollResult, error) {
+	run, err := p.client.RetrieveRun(ctx, threadID, runID)
+	if err != nil {
+		return RunPollResult{}, err
+	}
+	return p.poll(ctx, threadID, run)
+}
+
+func (p *RunPoller) poll(ctx context.Context, threadID string, run Run) (RunPollResult, error) {
+	result := RunPollResult{Run: run}
+	polls := 0
+	for {
+		status := result.Run.Status
+		switch status {
+		case RunStatusCompleted, RunStatusFailed, RunStatusCancelled, RunStatusExpired, RunStatusIncomplete:
+			messages, err := p.runMessages(ctx, threadID, result.Run.ID)
+			result.Messages = messages
+			return result, err
+		case RunStatusRequiresAction:
+			run, err := p.submitToolOutputs(ctx, threadID, result.Run)
+			if err != nil {
+				return result, err
+			}
+			result.Run = run
+			result.ToolOutputSubmissions++
+		case RunStatusQueued, RunStatusInProgress, RunStatusCancelling:
+			polls++
+			if err := sleepContext(ctx, p.config.Poll.delay(polls)); err != nil {
+				return result, err
+			}
+			run, err := p.client.RetrieveRun(ctx, threadID, result.Run.ID)
+			if err != nil {
+				return result, err
+			}
+			result.Run = run
+		default:
+			return result, fmt.Errorf("unexpected run status %q", status)
+		}
+		if result.Run.Status != status {
+			polls = 0
+		}
+	}
+}
+
+func (p *RunPoller) submitToolOutputs(ctx context.Context, threadID string, run Run) (Run, error) {
+	action := run.RequiredAction
+	if action == nil || action.Type != RequiredActionTypeSubmitToolOutputs || action.SubmitToolOutputs == nil {
+		return run, ErrRunPollerUnsupportedAction
+	}
+
+	calls := action.SubmitToolOutputs.ToolCalls
+	config := ToolRunnerConfig{Approve: p.config.Approve, HandleError: p.config.HandleError}
+	outputs, err := runFunctionTools(ctx, p.tools, config, calls, true)
+	if err != nil {
+		return run, err
+	}
+	request := SubmitToolOutputsRequest{ToolOutputs: make([]ToolOutput, len(calls))}
+	for i, call := range calls {
+		request.ToolOutputs[i] = ToolOutput{ToolCallID: call.ID, Output: outputs[i]}
+	}
+	return p.client.SubmitToolOutputs(ctx, threadID, run.ID, request)
+}
+
+// runMessages lists the messages created by a run, following pagination.
+func (p *RunPoller) runMessages(ctx context.Context, threadID, runID string) ([]Message, error) {
+	var (
+		messages []Message
+		after    *string
+		limit    = runPollerMessagesPageSize
+		order    = "asc"
+	)
+	for {
+		page, err := p.client.ListMessage(ctx, threadID, &limit, &order, after, nil, &runID)
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, page.Messages...)
+		if !page.HasMore || page.LastID == nil {
+			return messages, nil
+		}
+		after = page.LastID
+	}
+}
@@ -0,0 +1,120 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+var fastPoll = &openai.PollPolicy{Interval: time.Millisecond, Multiplier: 1}
+
+func TestRunPoller(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	statuses := []string{
+		`"status":"in_progress"`,
+		`"status":"requires_action","required_action":{"type":"submit_tool_outputs","submit_tool_outputs":` +
+			`{"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather",` +
+			`"arguments":"{\"city\":\"Paris\"}"}},{"id":"call_2","type":"function",` +
+			`"function":{"name":"get_time","arguments":"{}"}}]}}`,
+		`"status":"in_progress"`,
+		`"status":"completed"`,
+	}
+	var retrievals int32
+	server.RegisterHandler("/v1/threads/thread_1/runs/run_1", func(w http.ResponseWriter, _ *http.Request) {
+		i := atomic.AddInt32(&retrievals, 1) - 1
+		fmt.Fprintf(w, `{"id":"run_1","object":"thread.run","thread_id":"thread_1",%s}`, statuses[i])
+	})
+	var outputs openai.SubmitToolOutputsRequest
+	server.RegisterHandler("/v1/threads/thread_1/runs/run_1/submit_tool_outputs",
+		func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&outputs)
+			fmt.Fprint(w, `{"id":"run_1","object":"thread.run","thread_id":"thread_1","status":"queued"}`)
+		})
+	var listQueries []string
+	server.RegisterHandler("/v1/threads/thread_1/messages", func(w http.ResponseWriter, r *http.Request) {
+		listQueries = append(listQueries, r.URL.RawQuery)
+		if r.URL.Query().Get("after") == "" {
+			fmt.Fprint(w, `{"object":"list","data":[{"id":"msg_1","role":"assistant"}],"last_id":"msg_1","has_more":true}`)
+			return
+		}
+		fmt.Fprint(w, `{"object":"list","data":[{"id":"msg_2","role":"assistant"}],"last_id":"msg_2","has_more":false}`)
+	})
+
+	var calls int32
+	poller := openai.NewRunPoller(client, openai.RunPollerConfig{Poll: fastPoll}, weatherTool(&calls))
+	result, err := poller.Poll(context.Background(), "thread_1", "run_1")
+	checks.NoError(t, err, "Poll error")
+
+	if result.Run.Status != openai.RunStatusCompleted || result.ToolOutputSubmissions != 1 || retrievals != 4 {
+		t.Errorf("unexpected result: %+v after %d retrievals", result, retrievals)
+	}
+	if len(result.Messages) != 2 || result.Messages[0].ID != "msg_1" || result.Messages[1].ID != "msg_2" {
+		t.Errorf("unexpected messages: %+v", result.Messages)
+	}
+	if listQueries[0] != "limit=100&order=asc&run_id=run_1" ||
+		listQueries[1] != "after=msg_1&limit=100&order=asc&run_id=run_1" {
+		t.Errorf("unexpected message queries: %v", listQueries)
+	}
+
+	want := []openai.ToolOutput{
+		{ToolCallID: "call_1", Output: "sunny in Paris"},
+		{ToolCallID: "call_2", Output: `Error: unknown tool "get_time"`},
+	}
+	if fmt.Sprint(outputs.ToolOutputs) != fmt.Sprint(want) || calls != 1 {
+		t.Errorf("unexpected tool outputs: %+v", outputs.ToolOutputs)
+	}
+}
+
+func TestRunPollerCreateAndPollFailedRun(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads/thread_1/runs", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"run_1","object":"thread.run","status":"failed",`+
+			`"last_error":{"code":"rate_limit_exceeded","message":"Slow down."}}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_1/messages", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"object":"list","data":[],"has_more":false}`)
+	})
+
+	poller := openai.NewRunPoller(client, openai.RunPollerConfig{})
+	result, err := poller.CreateAndPoll(context.Background(), "thread_1", openai.RunRequest{AssistantID: "asst_1"})
+	checks.NoError(t, err, "failed runs should not be an error")
+	if result.Run.Status != openai.RunStatusFailed || result.Run.LastError.Code != openai.RunErrorRateLimitExceeded {
+		t.Errorf("unexpected run: %+v", result.Run)
+	}
+}
+
+func TestRunPollerErrors(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads/thread_1/runs/run_1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"run_1","object":"thread.run","status":"in_progress"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_1/runs/run_2", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"run_2","object":"thread.run","status":"requires_action",`+
+			`"required_action":{"type":"confirm"}}`)
+	})
+
+	poller := openai.NewRunPoller(client, openai.RunPollerConfig{Poll: fastPoll})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	result, err := poller.Poll(ctx, "thread_1", "run_1")
+	if !errors.Is(err, context.DeadlineExceeded) || result.Run.Status != openai.RunStatusInProgress {
+		t.Errorf("expected the deadline to stop polling, got %v", err)
+	}
+
+	_, err = poller.Poll(context.Background(), "thread_1", "run_2")
+	if !errors.Is(err, openai.ErrRunPollerUnsupportedAction) {
+		t.Errorf("expected ErrRunPollerUnsupportedAction, got %v", err)
+	}
+}
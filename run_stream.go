@@ -0,0 +1,208 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AssistantStreamEventType is the name of an event of an Assistants API
+// stream, sent as its SSE event: line.
+type AssistantStreamEventType string
+
+const (
+	AssistantStreamEventThreadCreated     AssistantStreamEventType = "thread.created"
+	AssistantStreamEventRunCreated        AssistantStreamEventType = "thread.run.created"
+	AssistantStreamEventRunQueued         AssistantStreamEventType = "thread.run.queued"
+	AssistantStreamEventRunInProgress     AssistantStreamEventType = "thread.run.in_progress"
+	AssistantStreamEventRunRequiresAction AssistantStreamEventType = "thread.run.requires_action"
+	AssistantStreamEventRunCompleted      AssistantStreamEventType = "thread.run.completed"
+	AssistantStreamEventRunIncomplete     AssistantStreamEventType = "thread.run.incomplete"
+	AssistantStreamEventRunFailed         AssistantStreamEventType = "thread.run.failed"
+	AssistantStreamEventRunCancelling     AssistantStreamEventType = "thread.run.cancelling"
+	AssistantStreamEventRunCancelled      AssistantStreamEventType = "thread.run.cancelled"
+	AssistantStreamEventRunExpired        AssistantStreamEventType = "thread.run.expired"
+	AssistantStreamEventRunStepCreated    AssistantStreamEventType = "thread.run.step.created"
+	AssistantStreamEventRunStepInProgress AssistantStreamEventType = "thread.run.step.in_progress"
+	AssistantStreamEventRunStepDelta      AssistantStreamEventType = "thread.run.step.delta"
+	AssistantStreamEventRunStepCompleted  AssistantStreamEventType = "thread.run.step.completed"
+	AssistantStreamEventRunStepFailed     AssistantStreamEventType = "thread.run.step.failed"
+	AssistantStreamEventRunStepCancelled  AssistantStreamEventType = "thread.run.step.cancelled"
+	AssistantStreamEventRunStepExpired    AssistantStreamEventType = "thread.run.step.expired"
+	AssistantStreamEventMessageCreated    AssistantStreamEventType = "thread.message.created"
+	AssistantStreamEventMessageInProgress AssistantStreamEventType = "thread.message.in_progress"
+	AssistantStreamEventMessageDelta      AssistantStreamEventType = "thread.message.delta"
+	AssistantStreamEventMessageCompleted  AssistantStreamEventType = "thread.message.completed"
+	AssistantStreamEventMessageIncomplete AssistantStreamEventType = "thread.message.incomplete"
+	AssistantStreamEventError             AssistantStreamEventType = "error"
+	AssistantStreamEventDone              AssistantStreamEventType = "done"
+)
+
+// AssistantStreamEvent is an event of a streamed run. Exactly one of the
+// object fields is set, depending on the event type: Thread for
+// thread.created, Run for the thread.run.* events, RunStep and RunStepDelta
+// for the thread.run.step.* events, Message and MessageDelta for the
+// thread.message.* events, and Error for error events. Events unknown to
+// this version of the library only carry their Raw data.
+type AssistantStreamEvent struct {
+	Event        AssistantStreamEventType
+	Thread       *Thread
+	Run          *Run
+	RunStep      *RunStep
+	RunStepDelta *RunStepDelta
+	Message      *Message
+	MessageDelta *MessageDelta
+	Error        *APIError
+	// Raw is the data of the event as received.
+	Raw json.RawMessage
+}
+
+// MessageDelta is the change to a message sent by a thread.message.delta event.
+type MessageDelta struct {
+	ID     string              `json:"id"`
+	Object string              `json:"object"`
+	Delta  MessageDeltaContent `json:"delta"`
+}
+
+type MessageDeltaContent struct {
+	Role    string                    `json:"role,omitempty"`
+	Content []MessageDeltaContentPart `json:"content,omitempty"`
+}
+
+// MessageDeltaContentPart is a fragment of the content part at Index of the
+// message, e.g. the next characters of its text value.
+type MessageDeltaContentPart struct {
+	Index int `json:"index"`
+	MessageContent
+}
+
+// RunStepDelta is the change to a run step sent by a thread.run.step.delta
+// event. Tool calls are identified by their Index, and the arguments of a
+// function call arrive in fragments.
+type RunStepDelta struct {
+	ID     string              `json:"id"`
+	Object string              `json:"object"`
+	Delta  RunStepDeltaDetails `json:"delta"`
+}
+
+type RunStepDeltaDetails struct {
+	StepDetails StepDetails `json:"step_details"`
+}
+
+// AssistantStream reads the events of a streamed run.
+type AssistantStream struct {
+	*streamReader[AssistantStreamEvent]
+}
+
+// CreateRunStream creates a run and streams its events.
+func (c *Client) CreateRunStream(
+	ctx context.Context,
+	threadID string,
+	request RunRequest,
+) (*AssistantStream, error) {
+	urlSuffix := fmt.Sprintf("/threads/%s/runs", threadID)
+	return c.createAssistantStream(ctx, "CreateRunStream", urlSuffix, request, struct {
+		RunRequest
+		Stream bool `json:"stream"`
+	}{request, true})
+}
+
+// CreateThreadAndRunStream creates a thread, runs it and streams the events
+// of the run, starting with thread.created.
+func (c *Client) CreateThreadAndRunStream(
+	ctx context.Context,
+	request CreateThreadAndRunRequest,
+) (*AssistantStream, error) {
+	return c.createAssistantStream(ctx, "CreateThreadAndRunStream", "/threads/runs", request, struct {
+		CreateThreadAndRunRequest
+		Stream bool `json:"stream"`
+	}{request, true})
+}
+
+// SubmitToolOutputsStream submits tool outputs and streams the events of the
+// run as it continues.
+func (c *Client) SubmitToolOutputsStream(
+	ctx context.Context,
+	threadID string,
+	runID string,
+	request SubmitToolOutputsRequest,
+) (*AssistantStream, error) {
+	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s/submit_tool_outputs", threadID, runID)
+	return c.createAssistantStream(ctx, "SubmitToolOutputsStream", urlSuffix, request, struct {
+		SubmitToolOutputsRequest
+		Stream bool `json:"stream"`
+	}{request, true})
+}
+
+func (c *Client) createAssistantStream(
+	ctx context.Context,
+	operation string,
+	urlSuffix string,
+	request any,
+	body any,
+) (*AssistantStream, error) {
+	req, err := c.newRequest(
+		ctx,
+		http.MethodPost,
+		c.fullURL(urlSuffix),
+		withBody(body),
+		withBetaAssistantVersion(c.config.AssistantVersion),
+		withOperation(operation, request))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sendRequestStream[AssistantStreamEvent](c, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.translator = assistantStreamTranslator{}
+	return &AssistantStream{streamReader: resp}, nil
+}
+
+// assistantStreamTranslator decodes the data of each event according to the
+// event name.
+type assistantStreamTranslator struct{}
+
+func (assistantStreamTranslator) translate(
+	event string,
+	data []byte,
+) (chunk AssistantStreamEvent, ok bool, err error) {
+	chunk = AssistantStreamEvent{
+		Event: AssistantStreamEventType(event),
+		Raw:   append(json.RawMessage(nil), data...),
+	}
+
+	var target any
+	switch {
+	case chunk.Event == AssistantStreamEventThreadCreated:
+		chunk.Thread = &Thread{}
+		target = chunk.Thread
+	case chunk.Event == AssistantStreamEventRunStepDelta:
+		chunk.RunStepDelta = &RunStepDelta{}
+		target = chunk.RunStepDelta
+	case strings.HasPrefix(event, "thread.run.step."):
+		chunk.RunStep = &RunStep{}
+		target = chunk.RunStep
+	case strings.HasPrefix(event, "thread.run."):
+		chunk.Run = &Run{}
+		target = chunk.Run
+	case chunk.Event == AssistantStreamEventMessageDelta:
+		chunk.MessageDelta = &MessageDelta{}
+		target = chunk.MessageDelta
+	case strings.HasPrefix(event, "thread.message."):
+		chunk.Message = &Message{}
+		target = chunk.Message
+	case chunk.Event == AssistantStreamEventError:
+		chunk.Error = &APIError{}
+		target = chunk.Error
+	default:
+		return chunk, true, nil
+	}
+	if err = json.Unmarshal(data, target); err != nil {
+		return chunk, false, fmt.Errorf("decoding %s event: %w", event, err)
+	}
+	return chunk, true, nil
+}
@@ -0,0 +1,172 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func writeAssistantEvents(w http.ResponseWriter, events ...string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for i := 0; i+1 < len(events); i += 2 {
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", events[i], events[i+1])
+	}
+	fmt.Fprint(w, "event: done\ndata: [DONE]\n\n")
+}
+
+func recvAssistantEvents(t *testing.T, stream *openai.AssistantStream) []openai.AssistantStreamEvent {
+	t.Helper()
+	defer stream.Close()
+	var events []openai.AssistantStreamEvent
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return events
+		}
+		checks.NoError(t, err, "Recv error")
+		events = append(events, event)
+	}
+}
+
+func TestCreateRunStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads/thread_1/runs", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if request["stream"] != true || request["assistant_id"] != "asst_1" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("OpenAI-Beta") != "assistants=v2" {
+			http.Error(w, "missing beta header", http.StatusBadRequest)
+			return
+		}
+		writeAssistantEvents(w,
+			"thread.run.created", `{"id":"run_1","object":"thread.run","status":"queued"}`,
+			"thread.run.step.created", `{"id":"step_1","object":"thread.run.step","type":"message_creation",`+
+				`"status":"in_progress","step_details":{"type":"message_creation",`+
+				`"message_creation":{"message_id":"msg_1"}}}`,
+			"thread.message.created", `{"id":"msg_1","object":"thread.message","role":"assistant","content":[]}`,
+			"thread.message.delta", `{"id":"msg_1","object":"thread.message.delta","delta":{"content":[`+
+				`{"index":0,"type":"text","text":{"value":"Hello","annotations":[]}}]}}`,
+			"thread.run.completed", `{"id":"run_1","object":"thread.run","status":"completed",`+
+				`"usage":{"prompt_tokens":5,"completion_tokens":1,"total_tokens":6}}`,
+			"thread.archived", `{"id":"thread_1"}`,
+		)
+	})
+
+	stream, err := client.CreateRunStream(context.Background(), "thread_1", openai.RunRequest{AssistantID: "asst_1"})
+	checks.NoError(t, err, "CreateRunStream error")
+	events := recvAssistantEvents(t, stream)
+	if len(events) != 6 {
+		t.Fatalf("expected 6 events, got %d", len(events))
+	}
+
+	if events[0].Event != openai.AssistantStreamEventRunCreated || events[0].Run == nil ||
+		events[0].Run.Status != openai.RunStatusQueued {
+		t.Errorf("unexpected run created event: %+v", events[0])
+	}
+	if step := events[1].RunStep; step == nil || step.StepDetails.MessageCreation.MessageID != "msg_1" {
+		t.Errorf("unexpected step created event: %+v", events[1])
+	}
+	if events[2].Message == nil || events[2].Message.Role != "assistant" {
+		t.Errorf("unexpected message created event: %+v", events[2])
+	}
+	delta := events[3].MessageDelta
+	if delta == nil || len(delta.Delta.Content) != 1 || delta.Delta.Content[0].Text.Value != "Hello" {
+		t.Errorf("unexpected message delta event: %+v", events[3])
+	}
+	if run := events[4].Run; run == nil || run.Status != openai.RunStatusCompleted || run.Usage.TotalTokens != 6 {
+		t.Errorf("unexpected run completed event: %+v", events[4])
+	}
+	// Events added to the API later are passed through undecoded.
+	if events[5].Thread != nil || string(events[5].Raw) != `{"id":"thread_1"}` {
+		t.Errorf("unexpected unknown event: %+v", events[5])
+	}
+}
+
+func TestSubmitToolOutputsStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads/thread_1/runs/run_1/submit_tool_outputs",
+		func(w http.ResponseWriter, r *http.Request) {
+			var request map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			if request["stream"] != true || len(request["tool_outputs"].([]any)) != 1 {
+				http.Error(w, "unexpected request", http.StatusBadRequest)
+				return
+			}
+			writeAssistantEvents(w,
+				"thread.run.step.delta", `{"id":"step_1","object":"thread.run.step.delta","delta":{"step_details":{`+
+					`"type":"tool_calls","tool_calls":[{"index":0,"type":"function",`+
+					`"function":{"arguments":"{\"city\":"}}]}}}`,
+				"error", `{"code":"server_error","message":"The server had an error."}`,
+			)
+		})
+
+	stream, err := client.SubmitToolOutputsStream(context.Background(), "thread_1", "run_1",
+		openai.SubmitToolOutputsRequest{ToolOutputs: []openai.ToolOutput{{ToolCallID: "call_1", Output: "21"}}})
+	checks.NoError(t, err, "SubmitToolOutputsStream error")
+	events := recvAssistantEvents(t, stream)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	delta := events[0].RunStepDelta
+	if delta == nil || len(delta.Delta.StepDetails.ToolCalls) != 1 ||
+		*delta.Delta.StepDetails.ToolCalls[0].Index != 0 ||
+		delta.Delta.StepDetails.ToolCalls[0].Function.Arguments != `{"city":` {
+		t.Errorf("unexpected step delta event: %+v", events[0])
+	}
+	if events[1].Error == nil || events[1].Error.Message != "The server had an error." {
+		t.Errorf("unexpected error event: %+v", events[1])
+	}
+}
+
+func TestCreateThreadAndRunStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads/runs", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if request["stream"] != true || request["thread"] == nil {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		writeAssistantEvents(w,
+			"thread.created", `{"id":"thread_1","object":"thread"}`,
+			"thread.run.created", `{"id":"run_1","object":"thread.run","thread_id":"thread_1","status":"queued"}`,
+			"thread.run.created", `{"id":`,
+		)
+	})
+
+	stream, err := client.CreateThreadAndRunStream(context.Background(), openai.CreateThreadAndRunRequest{
+		RunRequest: openai.RunRequest{AssistantID: "asst_1"},
+		Thread: openai.ThreadRequest{
+			Messages: []openai.ThreadMessage{{Role: openai.ThreadMessageRoleUser, Content: "Hi"}},
+		},
+	})
+	checks.NoError(t, err, "CreateThreadAndRunStream error")
+	defer stream.Close()
+
+	event, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if event.Thread == nil || event.Thread.ID != "thread_1" {
+		t.Errorf("unexpected thread created event: %+v", event)
+	}
+	event, err = stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if event.Run == nil || event.Run.ThreadID != "thread_1" {
+		t.Errorf("unexpected run created event: %+v", event)
+	}
+	_, err = stream.Recv()
+	checks.HasError(t, err, "malformed event data should be an error")
+}
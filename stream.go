@@ -39,6 +39,7 @@ func (c *Client) CreateCompletionStream(
 		http.MethodPost,
 		c.fullURL(urlSuffix, withModel(request.Model)),
 		withBody(request),
+		withOperation("CreateCompletionStream", request),
 	)
 	if err != nil {
 		return nil, err
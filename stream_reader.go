@@ -3,6 +3,7 @@ package openai
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,11 +14,27 @@ import (
 
 var (
 	headerData  = regexp.MustCompile(`^data:\s*`)
+	headerEvent = regexp.MustCompile(`^event:\s*`)
 	errorPrefix = regexp.MustCompile(`^data:\s*{"error":`)
 )
 
 type streamable interface {
-	ChatCompletionStreamResponse | CompletionResponse | ResponseStreamEvent
+	ChatCompletionStreamResponse | CompletionResponse | ResponseStreamEvent | AssistantStreamEvent
+}
+
+// streamObserver is notified of the chunks decoded from a stream and of the
+// end of the stream.
+type streamObserver interface {
+	observeChunk(chunk any)
+	end(err error)
+}
+
+// streamTranslator converts the events of a provider-specific stream into
+// chunks, given the name of each event from its event: line, if any, and its
+// data. Events without an equivalent chunk are skipped by returning false,
+// and io.EOF ends the stream.
+type streamTranslator[T streamable] interface {
+	translate(event string, data []byte) (chunk T, ok bool, err error)
 }
 
 type streamReader[T streamable] struct {
@@ -28,11 +45,33 @@ type streamReader[T streamable] struct {
 	response       *http.Response
 	errAccumulator utils.ErrorAccumulator
 	unmarshaler    utils.Unmarshaler
+	observer       streamObserver
+	translator     streamTranslator[T]
+	// event is the name of the event of the last data line returned by
+	// RecvRaw, or "" if it had no event: line.
+	event string
 
 	httpHeader
 }
 
 func (stream *streamReader[T]) Recv() (response T, err error) {
+	defer func() {
+		if stream.observer == nil {
+			return
+		}
+		if err == nil {
+			stream.observer.observeChunk(&response)
+		} else if errors.Is(err, io.EOF) {
+			stream.observer.end(nil)
+		} else {
+			stream.observer.end(err)
+		}
+	}()
+
+	if stream.translator != nil {
+		return stream.recvTranslated()
+	}
+
 	rawLine, err := stream.RecvRaw()
 	if err != nil {
 		return
@@ -45,6 +84,26 @@ func (stream *streamReader[T]) Recv() (response T, err error) {
 	return response, nil
 }
 
+func (stream *streamReader[T]) recvTranslated() (response T, err error) {
+	for {
+		rawLine, recvErr := stream.RecvRaw()
+		if recvErr != nil {
+			return response, recvErr
+		}
+
+		chunk, ok, translateErr := stream.translator.translate(stream.event, rawLine)
+		if errors.Is(translateErr, io.EOF) {
+			stream.isFinished = true
+		}
+		if translateErr != nil {
+			return response, translateErr
+		}
+		if ok {
+			return chunk, nil
+		}
+	}
+}
+
 func (stream *streamReader[T]) RecvRaw() ([]byte, error) {
 	if stream.isFinished {
 		return nil, io.EOF
@@ -58,6 +117,7 @@ func (stream *streamReader[T]) processLines() ([]byte, error) {
 	var (
 		emptyMessagesCount uint
 		hasErrorPrefix     bool
+		event              []byte
 	)
 
 	for {
@@ -84,8 +144,11 @@ func (stream *streamReader[T]) processLines() ([]byte, error) {
 			if hasErrorPrefix {
 				noSpaceLine = headerData.ReplaceAll(noSpaceLine, nil)
 			}
-			writeErr := stream.errAccumulator.Write(noSpaceLine)
-			if writeErr != nil {
+			if headerEvent.Match(noSpaceLine) {
+				// The event name applies to the next data line and is not
+				// part of an error payload.
+				event = headerEvent.ReplaceAll(noSpaceLine, nil)
+			} else if writeErr := stream.errAccumulator.Write(noSpaceLine); writeErr != nil {
 				return nil, writeErr
 			}
 			emptyMessagesCount++
@@ -96,6 +159,7 @@ func (stream *streamReader[T]) processLines() ([]byte, error) {
 			continue
 		}
 
+		stream.event = string(event)
 		noPrefixLine := headerData.ReplaceAll(noSpaceLine, nil)
 		if string(noPrefixLine) == "[DONE]" {
 			stream.isFinished = true
@@ -121,5 +185,8 @@ func (stream *streamReader[T]) unmarshalError() (errResp *ErrorResponse) {
 }
 
 func (stream *streamReader[T]) Close() error {
+	if stream.observer != nil {
+		stream.observer.end(nil)
+	}
 	return stream.response.Body.Close()
 }
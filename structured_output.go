@@ -0,0 +1,287 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+const (
+	defaultStructuredOutputName  = "response"
+	responseTextFormatJSONSchema = "json_schema"
+)
+
+var (
+	// ErrStructuredOutputNotObject is returned by the structured output helpers
+	// when the result type does not reflect into an object schema.
+	ErrStructuredOutputNotObject = errors.New("structured output type must be a struct")
+	// ErrStructuredOutputNoChoices is returned by CreateChatCompletionTyped
+	// when the chat completion has no choices.
+	ErrStructuredOutputNoChoices = errors.New("structured output chat completion has no choices")
+)
+
+var invalidSchemaNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// RefusalError is returned by the structured output helpers when the model
+// refuses to answer instead of producing output matching the schema.
+type RefusalError struct {
+	Refusal string
+}
+
+func (e *RefusalError) Error() string {
+	return "model refused to answer: " + e.Refusal
+}
+
+// StructuredOutputError is returned by the structured output helpers when the
+// model output does not match the schema.
+type StructuredOutputError struct {
+	// Output is the output the model produced.
+	Output string
+	Err    error
+}
+
+func (e *StructuredOutputError) Error() string {
+	return "structured output does not match the schema: " + e.Err.Error()
+}
+
+func (e *StructuredOutputError) Unwrap() error {
+	return e.Err
+}
+
+type structuredOutputOptions struct {
+	name        string
+	description string
+	retries     int
+}
+
+// StructuredOutputOption configures CreateChatCompletionTyped and CreateResponseTyped.
+type StructuredOutputOption func(*structuredOutputOptions)
+
+// WithStructuredOutputName sets the schema name. It defaults to the name of
+// the result type.
+func WithStructuredOutputName(name string) StructuredOutputOption {
+	return func(o *structuredOutputOptions) {
+		o.name = name
+	}
+}
+
+// WithStructuredOutputDescription sets the schema description.
+func WithStructuredOutputDescription(description string) StructuredOutputOption {
+	return func(o *structuredOutputOptions) {
+		o.description = description
+	}
+}
+
+// WithStructuredOutputRetries sets how many times the request is repeated
+// when the output does not match the schema. Each retry tells the model what
+// was wrong with its previous output.
+func WithStructuredOutputRetries(retries int) StructuredOutputOption {
+	return func(o *structuredOutputOptions) {
+		o.retries = retries
+	}
+}
+
+// CreateChatCompletionTyped creates a chat completion whose output is
+// constrained to the JSON schema reflected from T, and decodes it into T.
+// The schema is rewritten into strict form with jsonschema.MakeStrict, so
+// optional fields of T may be returned as null.
+// The request's ResponseFormat is replaced.
+//
+// It returns a *RefusalError when the model refuses to answer and a
+// *StructuredOutputError when the output does not match the schema after all
+// retries. The last chat completion is returned in either case.
+func CreateChatCompletionTyped[T any](
+	ctx context.Context,
+	client *Client,
+	request ChatCompletionRequest,
+	opts ...StructuredOutputOption,
+) (T, ChatCompletionResponse, error) {
+	var result T
+	schema, strict, options, err := newStructuredOutput(result, opts)
+	if err != nil {
+		return result, ChatCompletionResponse{}, err
+	}
+	request.ResponseFormat = &ChatCompletionResponseFormat{
+		Type: ChatCompletionResponseFormatTypeJSONSchema,
+		JSONSchema: &ChatCompletionResponseFormatJSONSchema{
+			Name:        options.name,
+			Description: options.description,
+			Schema:      schema,
+			Strict:      strict,
+		},
+	}
+	request.Messages = append([]ChatCompletionMessage(nil), request.Messages...)
+
+	for attempt := 0; ; attempt++ {
+		response, err := client.CreateChatCompletion(ctx, request)
+		if err != nil {
+			return result, response, err
+		}
+		if len(response.Choices) == 0 {
+			return result, response, ErrStructuredOutputNoChoices
+		}
+		message := response.Choices[0].Message
+		if message.Refusal != "" {
+			return result, response, &RefusalError{Refusal: message.Refusal}
+		}
+
+		var value T
+		err = schema.Unmarshal(message.Content, &value)
+		if err == nil {
+			return value, response, nil
+		}
+		if attempt >= options.retries {
+			return result, response, &StructuredOutputError{Output: message.Content, Err: err}
+		}
+		request.Messages = append(request.Messages, message, ChatCompletionMessage{
+			Role:    ChatMessageRoleUser,
+			Content: structuredOutputFeedback(err),
+		})
+	}
+}
+
+// CreateResponseTyped creates a response whose output text is constrained to
+// the JSON schema reflected from T, and decodes it into T. The request's
+// Text.Format is replaced.
+//
+// Retries continue from the previous response with PreviousResponseID, or
+// within the request's Conversation. When Store is false, the previous output
+// is sent back as input instead, which requires Input to be a string or
+// []ResponseInputItem.
+//
+// Errors are reported as by CreateChatCompletionTyped.
+func CreateResponseTyped[T any](
+	ctx context.Context,
+	client *Client,
+	request CreateResponseRequest,
+	opts ...StructuredOutputOption,
+) (T, CreateResponseResponse, error) {
+	var result T
+	schema, strict, options, err := newStructuredOutput(result, opts)
+	if err != nil {
+		return result, CreateResponseResponse{}, err
+	}
+	text := ResponseTextConfig{}
+	if request.Text != nil {
+		text = *request.Text
+	}
+	text.Format = &ResponseTextFormat{
+		Type:        responseTextFormatJSONSchema,
+		Name:        options.name,
+		Description: options.description,
+		Schema:      schema,
+		Strict:      strict,
+	}
+	request.Text = &text
+
+	for attempt := 0; ; attempt++ {
+		response, err := client.CreateResponse(ctx, request)
+		if err != nil {
+			return result, response, err
+		}
+		if refusal := response.Refusal(); refusal != "" {
+			return result, response, &RefusalError{Refusal: refusal}
+		}
+
+		output := response.GetOutputText()
+		var value T
+		err = schema.Unmarshal(output, &value)
+		if err == nil {
+			return value, response, nil
+		}
+		outputErr := &StructuredOutputError{Output: output, Err: err}
+		if attempt >= options.retries {
+			return result, response, outputErr
+		}
+		var ok bool
+		request, ok = structuredOutputRetryRequest(request, response, structuredOutputFeedback(err))
+		if !ok {
+			return result, response, outputErr
+		}
+	}
+}
+
+// newStructuredOutput reflects the schema of v, rewritten into strict form
+// when possible, and applies opts.
+func newStructuredOutput(v any, opts []StructuredOutputOption) (
+	schema *jsonschema.Definition,
+	strict bool,
+	options structuredOutputOptions,
+	err error,
+) {
+	options = structuredOutputOptions{name: structuredOutputName(reflect.TypeOf(v))}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	schema, err = jsonschema.GenerateSchemaForType(v)
+	if err != nil {
+		return nil, false, options, err
+	}
+	if schema.Type != jsonschema.Object {
+		return nil, false, options, ErrStructuredOutputNotObject
+	}
+	schema, strict = strictSchema(schema)
+	return schema, strict, options, nil
+}
+
+func structuredOutputName(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return defaultStructuredOutputName
+	}
+	name := invalidSchemaNameChars.ReplaceAllString(t.Name(), "_")
+	if name == "" {
+		return defaultStructuredOutputName
+	}
+	return name
+}
+
+// structuredOutputFeedback tells the model what was wrong with its output,
+// listing every schema violation when the output was valid JSON.
+func structuredOutputFeedback(err error) string {
+	var validationErrs jsonschema.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		err = validationErrs
+	}
+	return fmt.Sprintf("Your previous response did not match the required JSON schema: %v. "+
+		"Respond again with JSON that matches the schema.", err)
+}
+
+// structuredOutputRetryRequest returns the request asking the model to fix
+// the output of response, and whether the earlier context could be kept.
+func structuredOutputRetryRequest(
+	request CreateResponseRequest,
+	response CreateResponseResponse,
+	feedback string,
+) (CreateResponseRequest, bool) {
+	builder := NewResponseInputBuilder()
+	switch {
+	case request.Store == nil || *request.Store:
+		if request.Conversation == nil {
+			request.PreviousResponseID = response.ID
+		}
+	default:
+		switch input := request.Input.(type) {
+		case string:
+			builder.User(input)
+		case []ResponseInputItem:
+			builder.Item(input...)
+		default:
+			return request, false
+		}
+		builder.Output(response.Output...)
+	}
+
+	input, err := builder.User(feedback).Build()
+	if err != nil {
+		return request, false
+	}
+	request.Input = input
+	return request, true
+}
@@ -0,0 +1,167 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+type cityInfo struct {
+	Name       string `json:"name"`
+	Population int    `json:"population"`
+}
+
+func TestCreateChatCompletionTyped(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []openai.ChatCompletionRequest
+	registerChatResponses(server, &requests,
+		answerResponse(`{"name":"Paris","population":"many"}`),
+		answerResponse(`{"name":"Paris","population":2100000}`),
+	)
+
+	city, response, err := openai.CreateChatCompletionTyped[cityInfo](context.Background(), client,
+		openai.ChatCompletionRequest{
+			Model:    openai.GPT4o,
+			Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Paris?"}},
+		},
+		openai.WithStructuredOutputRetries(1),
+	)
+	checks.NoError(t, err, "CreateChatCompletionTyped error")
+	if city != (cityInfo{Name: "Paris", Population: 2100000}) {
+		t.Errorf("unexpected result: %+v", city)
+	}
+	if response.Choices[0].Message.Content != `{"name":"Paris","population":2100000}` {
+		t.Errorf("unexpected response: %+v", response)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	format := requests[0].ResponseFormat
+	if format == nil || format.Type != openai.ChatCompletionResponseFormatTypeJSONSchema ||
+		format.JSONSchema.Name != "cityInfo" || !format.JSONSchema.Strict {
+		t.Errorf("unexpected response format: %+v", format)
+	}
+	retry := requests[1].Messages
+	if len(retry) != 3 || retry[1].Role != openai.ChatMessageRoleAssistant ||
+		retry[2].Content != "Your previous response did not match the required JSON schema: "+
+			"/population: must be integer, got string. Respond again with JSON that matches the schema." {
+		t.Errorf("unexpected retry messages: %+v", retry)
+	}
+}
+
+func TestCreateChatCompletionTypedErrors(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	refusal := answerResponse("")
+	refusal.Choices[0].Message.Refusal = "I can't help with that."
+	var requests []openai.ChatCompletionRequest
+	registerChatResponses(server, &requests, refusal, answerResponse(`not json`))
+
+	request := openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Paris?"}},
+	}
+	_, _, err := openai.CreateChatCompletionTyped[cityInfo](context.Background(), client, request)
+	var refusalErr *openai.RefusalError
+	if !errors.As(err, &refusalErr) || refusalErr.Refusal != "I can't help with that." {
+		t.Errorf("expected RefusalError, got %v", err)
+	}
+
+	_, _, err = openai.CreateChatCompletionTyped[cityInfo](context.Background(), client, request,
+		openai.WithStructuredOutputName("city"))
+	var outputErr *openai.StructuredOutputError
+	if !errors.As(err, &outputErr) || outputErr.Output != "not json" {
+		t.Errorf("expected StructuredOutputError, got %v", err)
+	}
+	if requests[1].ResponseFormat.JSONSchema.Name != "city" {
+		t.Errorf("unexpected schema name: %+v", requests[1].ResponseFormat.JSONSchema)
+	}
+
+	_, _, err = openai.CreateChatCompletionTyped[[]cityInfo](context.Background(), client, request)
+	if !errors.Is(err, openai.ErrStructuredOutputNotObject) {
+		t.Errorf("expected ErrStructuredOutputNotObject, got %v", err)
+	}
+}
+
+func typedResponseBody(id, text string) string {
+	output, _ := json.Marshal(text)
+	return fmt.Sprintf(`{"id":%q,"object":"response","status":"completed","output":[`+
+		`{"id":"msg_%s","type":"message","role":"assistant","status":"completed",`+
+		`"content":[{"type":"output_text","text":%s,"annotations":[]}]}]}`, id, id, output)
+}
+
+func TestCreateResponseTyped(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []map[string]any
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request map[string]any
+		_ = json.Unmarshal(body, &request)
+		requests = append(requests, request)
+		if len(requests)%2 == 1 {
+			fmt.Fprint(w, typedResponseBody("resp_1", `{"name":"Paris"}`))
+			return
+		}
+		fmt.Fprint(w, typedResponseBody("resp_2", `{"name":"Paris","population":2100000}`))
+	})
+
+	request := openai.CreateResponseRequest{
+		Model: openai.GPT4o,
+		Input: "Paris?",
+		Text:  &openai.ResponseTextConfig{Verbosity: "low"},
+	}
+	city, response, err := openai.CreateResponseTyped[cityInfo](context.Background(), client, request,
+		openai.WithStructuredOutputRetries(1), openai.WithStructuredOutputDescription("A city"))
+	checks.NoError(t, err, "CreateResponseTyped error")
+	if city.Population != 2100000 || response.ID != "resp_2" {
+		t.Errorf("unexpected result: %+v %+v", city, response)
+	}
+
+	text := requests[0]["text"].(map[string]any)
+	format := text["format"].(map[string]any)
+	if text["verbosity"] != "low" || format["type"] != "json_schema" || format["name"] != "cityInfo" ||
+		format["description"] != "A city" || format["strict"] != true {
+		t.Errorf("unexpected text config: %+v", text)
+	}
+	if requests[1]["previous_response_id"] != "resp_1" || len(requests[1]["input"].([]any)) != 1 {
+		t.Errorf("stored responses should be continued: %+v", requests[1])
+	}
+
+	store := false
+	request.Store = &store
+	_, _, err = openai.CreateResponseTyped[cityInfo](context.Background(), client, request,
+		openai.WithStructuredOutputRetries(1))
+	checks.NoError(t, err, "CreateResponseTyped error")
+	input := requests[3]["input"].([]any)
+	if requests[3]["previous_response_id"] != nil || len(input) != 3 ||
+		input[1].(map[string]any)["type"] != "message" || input[1].(map[string]any)["role"] != "assistant" {
+		t.Errorf("unstored responses should be replayed as input: %+v", requests[3])
+	}
+}
+
+func TestCreateResponseTypedRefusal(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"resp_1","object":"response","status":"completed","output":[`+
+			`{"id":"msg_1","type":"message","role":"assistant","status":"completed",`+
+			`"content":[{"type":"refusal","refusal":"No."}]}]}`)
+	})
+
+	_, _, err := openai.CreateResponseTyped[cityInfo](context.Background(), client,
+		openai.CreateResponseRequest{Model: openai.GPT4o, Input: "Paris?"})
+	var refusalErr *openai.RefusalError
+	if !errors.As(err, &refusalErr) || refusalErr.Refusal != "No." {
+		t.Errorf("expected RefusalError, got %v", err)
+	}
+}
@@ -115,7 +115,8 @@ type ThreadDeleteResponse struct {
 // CreateThread creates a new thread.
 func (c *Client) CreateThread(ctx context.Context, request ThreadRequest) (response Thread, err error) {
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(threadsSuffix), withBody(request),
-		withBetaAssistantVersion(c.config.AssistantVersion))
+		withBetaAssistantVersion(c.config.AssistantVersion),
+		withOperation("CreateThread", request))
 	if err != nil {
 		return
 	}
@@ -128,7 +129,8 @@ func (c *Client) CreateThread(ctx context.Context, request ThreadRequest) (respo
 func (c *Client) RetrieveThread(ctx context.Context, threadID string) (response Thread, err error) {
 	urlSuffix := threadsSuffix + "/" + threadID
 	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix),
-		withBetaAssistantVersion(c.config.AssistantVersion))
+		withBetaAssistantVersion(c.config.AssistantVersion),
+		withOperation("RetrieveThread", nil))
 	if err != nil {
 		return
 	}
@@ -145,7 +147,8 @@ func (c *Client) ModifyThread(
 ) (response Thread, err error) {
 	urlSuffix := threadsSuffix + "/" + threadID
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request),
-		withBetaAssistantVersion(c.config.AssistantVersion))
+		withBetaAssistantVersion(c.config.AssistantVersion),
+		withOperation("ModifyThread", request))
 	if err != nil {
 		return
 	}
@@ -161,7 +164,8 @@ func (c *Client) DeleteThread(
 ) (response ThreadDeleteResponse, err error) {
 	urlSuffix := threadsSuffix + "/" + threadID
 	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(urlSuffix),
-		withBetaAssistantVersion(c.config.AssistantVersion))
+		withBetaAssistantVersion(c.config.AssistantVersion),
+		withOperation("DeleteThread", nil))
 	if err != nil {
 		return
 	}
@@ -0,0 +1,214 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AzureADDefaultAuthorityHost is the Microsoft Entra ID authority of the public cloud.
+	AzureADDefaultAuthorityHost = "https://login.microsoftonline.com"
+	// AzureADCognitiveServicesScope is the scope of access tokens for Azure OpenAI.
+	AzureADCognitiveServicesScope = "https://cognitiveservices.azure.com/.default"
+
+	defaultTokenRefreshMargin = 5 * time.Minute
+)
+
+// TokenProvider supplies the bearer token sent with every request. When set
+// in ClientConfig it takes precedence over the static token the config was
+// created with, which allows short-lived tokens such as Microsoft Entra ID
+// access tokens to be refreshed without rebuilding the Client.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenProviderFunc adapts an ordinary function to the TokenProvider interface.
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+func (f TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// AccessToken is a token together with the time it expires at.
+type AccessToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// CachedTokenProvider caches the token returned by a fetch function and
+// fetches a new one shortly before it expires. It is safe for concurrent use;
+// concurrent callers share a single fetch.
+type CachedTokenProvider struct {
+	fetch         func(ctx context.Context) (AccessToken, error)
+	refreshMargin time.Duration
+	now           func() time.Time
+
+	mu    sync.Mutex
+	token AccessToken
+}
+
+// NewCachedTokenProvider creates a CachedTokenProvider. Tokens are refreshed
+// once they are within refreshMargin of their expiry; a zero margin defaults
+// to five minutes.
+func NewCachedTokenProvider(
+	fetch func(ctx context.Context) (AccessToken, error),
+	refreshMargin time.Duration,
+) *CachedTokenProvider {
+	if refreshMargin <= 0 {
+		refreshMargin = defaultTokenRefreshMargin
+	}
+	return &CachedTokenProvider{
+		fetch:         fetch,
+		refreshMargin: refreshMargin,
+		now:           time.Now,
+	}
+}
+
+// Token returns the cached token, fetching a new one if it is about to
+// expire. If the refresh fails while the cached token is still valid, the
+// cached token is returned and the refresh is retried on the next call.
+func (p *CachedTokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if p.token.Token != "" && now.Add(p.refreshMargin).Before(p.token.ExpiresAt) {
+		return p.token.Token, nil
+	}
+
+	token, err := p.fetch(ctx)
+	if err != nil {
+		if p.token.Token != "" && now.Before(p.token.ExpiresAt) {
+			return p.token.Token, nil
+		}
+		return "", err
+	}
+	p.token = token
+	return token.Token, nil
+}
+
+// AzureADClientCredentials configures the OAuth 2.0 client credentials flow
+// against Microsoft Entra ID (formerly Azure AD).
+type AzureADClientCredentials struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	// Scope defaults to AzureADCognitiveServicesScope.
+	Scope string
+	// AuthorityHost defaults to AzureADDefaultAuthorityHost. Use it for
+	// sovereign clouds.
+	AuthorityHost string
+	// TokenURL overrides the token endpoint derived from AuthorityHost and TenantID.
+	TokenURL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient HTTPDoer
+	// RefreshMargin is how long before expiry a token is refreshed.
+	// It defaults to five minutes.
+	RefreshMargin time.Duration
+}
+
+// NewAzureADTokenProvider creates a caching TokenProvider that obtains access
+// tokens with the client credentials flow.
+func NewAzureADTokenProvider(credentials AzureADClientCredentials) *CachedTokenProvider {
+	provider := NewCachedTokenProvider(nil, credentials.RefreshMargin)
+	provider.fetch = func(ctx context.Context) (AccessToken, error) {
+		return credentials.fetchToken(ctx, provider.now)
+	}
+	return provider
+}
+
+// DefaultAzureADConfig creates a configuration for Azure OpenAI that
+// authenticates every request with a token from provider.
+func DefaultAzureADConfig(provider TokenProvider, baseURL string) ClientConfig {
+	config := DefaultAzureConfig("", baseURL)
+	config.APIType = APITypeAzureAD
+	config.TokenProvider = provider
+	return config
+}
+
+func (c AzureADClientCredentials) tokenURL() string {
+	if c.TokenURL != "" {
+		return c.TokenURL
+	}
+	authority := c.AuthorityHost
+	if authority == "" {
+		authority = AzureADDefaultAuthorityHost
+	}
+	return fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimRight(authority, "/"), url.PathEscape(c.TenantID))
+}
+
+type azureADTokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	TokenType        string      `json:"token_type"`
+	ExpiresIn        json.Number `json:"expires_in"`
+	Error            string      `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
+
+func (c AzureADClientCredentials) fetchToken(ctx context.Context, now func() time.Time) (AccessToken, error) {
+	scope := c.Scope
+	if scope == "" {
+		scope = AzureADCognitiveServicesScope
+	}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.ClientID},
+		"client_secret": {c.ClientSecret},
+		"scope":         {scope},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return AccessToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	issuedAt := now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return AccessToken{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("error, reading token response body: %w", err)
+	}
+	var tokenResp azureADTokenResponse
+	err = json.Unmarshal(body, &tokenResp)
+	if err == nil && tokenResp.Error != "" {
+		err = fmt.Errorf("%s: %s", tokenResp.Error, tokenResp.ErrorDescription)
+	}
+	if err != nil || isFailureStatusCode(resp) || tokenResp.AccessToken == "" {
+		if err == nil {
+			err = errors.New("token endpoint returned no access token")
+		}
+		return AccessToken{}, &RequestError{
+			HTTPStatus:     resp.Status,
+			HTTPStatusCode: resp.StatusCode,
+			Err:            err,
+			Body:           body,
+		}
+	}
+
+	expiresIn, err := tokenResp.ExpiresIn.Int64()
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("error, parsing token expiry: %w", err)
+	}
+	return AccessToken{
+		Token:     tokenResp.AccessToken,
+		ExpiresAt: issuedAt.Add(time.Duration(expiresIn) * time.Second),
+	}, nil
+}
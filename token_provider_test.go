@@ -0,0 +1,203 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// newFakeAuthority serves the client credentials flow of a single tenant,
+// issuing tokens "token-1", "token-2", ... that expire after expiresIn seconds.
+func newFakeAuthority(t *testing.T, expiresIn int) (*httptest.Server, *int32) {
+	t.Helper()
+	var issued int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tenant-1/oauth2/v2.0/token" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		if r.PostForm.Get("grant_type") != "client_credentials" ||
+			r.PostForm.Get("scope") != openai.AzureADCognitiveServicesScope {
+			t.Errorf("unexpected token request: %v", r.PostForm)
+		}
+		if r.PostForm.Get("client_id") != "client-1" || r.PostForm.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client","error_description":"AADSTS7000215: Invalid client secret."}`)
+			return
+		}
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"token_type":"Bearer","expires_in":%d,"access_token":"token-%d"}`, expiresIn, n)
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+// newAuthorizationRecorder serves the models endpoint and records the
+// Authorization header of every request.
+func newAuthorizationRecorder(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"object":"list","data":[]}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &authorizations
+}
+
+func TestAzureADTokenProviderCachesToken(t *testing.T) {
+	authority, issued := newFakeAuthority(t, 3600)
+	api, authorizations := newAuthorizationRecorder(t)
+
+	provider := openai.NewAzureADTokenProvider(openai.AzureADClientCredentials{
+		TenantID:      "tenant-1",
+		ClientID:      "client-1",
+		ClientSecret:  "secret",
+		AuthorityHost: authority.URL,
+	})
+	client := openai.NewClientWithConfig(openai.DefaultAzureADConfig(provider, api.URL))
+	for i := 0; i < 3; i++ {
+		_, err := client.ListModels(context.Background())
+		checks.NoError(t, err, "ListModels error")
+	}
+
+	if *issued != 1 {
+		t.Errorf("expected the token to be fetched once, got %d fetches", *issued)
+	}
+	for _, authorization := range *authorizations {
+		if authorization != "Bearer token-1" {
+			t.Errorf("unexpected Authorization header: %q", authorization)
+		}
+	}
+}
+
+func TestAzureADTokenProviderRefreshesBeforeExpiry(t *testing.T) {
+	authority, _ := newFakeAuthority(t, 60)
+	api, authorizations := newAuthorizationRecorder(t)
+
+	// Tokens expire within the refresh margin, so each request needs a new one.
+	provider := openai.NewAzureADTokenProvider(openai.AzureADClientCredentials{
+		TenantID:      "tenant-1",
+		ClientID:      "client-1",
+		ClientSecret:  "secret",
+		TokenURL:      authority.URL + "/tenant-1/oauth2/v2.0/token",
+		RefreshMargin: time.Hour,
+	})
+	client := openai.NewClientWithConfig(openai.DefaultAzureADConfig(provider, api.URL))
+	for i := 0; i < 2; i++ {
+		_, err := client.ListModels(context.Background())
+		checks.NoError(t, err, "ListModels error")
+	}
+
+	if fmt.Sprint(*authorizations) != "[Bearer token-1 Bearer token-2]" {
+		t.Errorf("unexpected Authorization headers: %v", *authorizations)
+	}
+}
+
+func TestAzureADTokenProviderError(t *testing.T) {
+	authority, _ := newFakeAuthority(t, 3600)
+	api, authorizations := newAuthorizationRecorder(t)
+
+	provider := openai.NewAzureADTokenProvider(openai.AzureADClientCredentials{
+		TenantID:      "tenant-1",
+		ClientID:      "client-1",
+		ClientSecret:  "wrong",
+		AuthorityHost: authority.URL,
+	})
+	client := openai.NewClientWithConfig(openai.DefaultAzureADConfig(provider, api.URL))
+	_, err := client.ListModels(context.Background())
+
+	var reqErr *openai.RequestError
+	if !errors.As(err, &reqErr) || reqErr.HTTPStatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected RequestError from the token endpoint, got %v", err)
+	}
+	if len(*authorizations) != 0 {
+		t.Fatalf("no request should be sent without a token, got %d", len(*authorizations))
+	}
+}
+
+func TestCachedTokenProviderKeepsValidTokenOnRefreshFailure(t *testing.T) {
+	var calls int
+	provider := openai.NewCachedTokenProvider(func(context.Context) (openai.AccessToken, error) {
+		calls++
+		if calls > 1 {
+			return openai.AccessToken{}, errors.New("authority unavailable")
+		}
+		return openai.AccessToken{Token: "token-1", ExpiresAt: time.Now().Add(time.Minute)}, nil
+	}, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		token, err := provider.Token(context.Background())
+		checks.NoError(t, err, "Token error")
+		if token != "token-1" {
+			t.Fatalf("unexpected token: %q", token)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected a refresh attempt on the second call, got %d calls", calls)
+	}
+}
+
+func TestTokenProviderOverridesAPIKey(t *testing.T) {
+	api, authorizations := newAuthorizationRecorder(t)
+
+	config := openai.DefaultConfig("static-token")
+	config.BaseURL = api.URL
+	config.TokenProvider = openai.TokenProviderFunc(func(context.Context) (string, error) {
+		return "dynamic-token", nil
+	})
+	_, err := openai.NewClientWithConfig(config).ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+
+	if fmt.Sprint(*authorizations) != "[Bearer dynamic-token]" {
+		t.Errorf("unexpected Authorization headers: %v", *authorizations)
+	}
+}
+
+func TestTokenProviderErrorVectorStores(t *testing.T) {
+	api, authorizations := newAuthorizationRecorder(t)
+
+	errToken := errors.New("authority unavailable")
+	config := openai.DefaultConfig("")
+	config.BaseURL = api.URL
+	config.TokenProvider = openai.TokenProviderFunc(func(context.Context) (string, error) {
+		return "", errToken
+	})
+	client := openai.NewClientWithConfig(config)
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"CreateVectorStore": func() error {
+			_, err := client.CreateVectorStore(ctx, openai.VectorStoreRequest{})
+			return err
+		},
+		"ListVectorStores": func() error {
+			_, err := client.ListVectorStores(ctx, openai.Pagination{})
+			return err
+		},
+		"DeleteVectorStoreFile": func() error {
+			return client.DeleteVectorStoreFile(ctx, "vs_1", "file_1")
+		},
+		"ListVectorStoreFilesInBatch": func() error {
+			_, err := client.ListVectorStoreFilesInBatch(ctx, "vs_1", "batch_1", openai.Pagination{})
+			return err
+		},
+	}
+	for name, call := range calls {
+		checks.ErrorIs(t, call(), errToken, name+" should return the token error")
+	}
+	if len(*authorizations) != 0 {
+		t.Fatalf("no request should be sent without a token, got %d", len(*authorizations))
+	}
+}
@@ -0,0 +1,265 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const defaultToolRunnerMaxIterations = 10
+
+var (
+	// ErrToolRunnerMaxIterations is returned when the model still requests
+	// tool calls after the maximum number of iterations.
+	ErrToolRunnerMaxIterations = errors.New("tool runner reached the maximum number of iterations")
+	// ErrToolRunnerMaxToolCalls is returned when the model requests more tool
+	// calls than ToolRunnerConfig.MaxToolCalls allows.
+	ErrToolRunnerMaxToolCalls = errors.New("tool runner reached the maximum number of tool calls")
+	// ErrToolRunnerNoChoices is returned when a chat completion has no choices.
+	ErrToolRunnerNoChoices = errors.New("tool runner received a chat completion without choices")
+)
+
+// ToolRunnerConfig configures a ChatToolRunner or a ResponseToolRunner.
+type ToolRunnerConfig struct {
+	// MaxIterations limits the number of chat completions per run. It defaults to 10.
+	MaxIterations int
+	// MaxToolCalls, when positive, limits the number of tool calls executed
+	// per run. The run stops with ErrToolRunnerMaxToolCalls instead of
+	// executing calls beyond the limit. It is independent of the MaxToolCalls
+	// field of a Responses API request, which limits built-in tool calls.
+	MaxToolCalls int
+	// Approve, when set, is consulted before each tool call is executed.
+	// Calls that are not approved are answered with a message saying so
+	// instead of being executed, and an error aborts the run.
+	Approve func(ctx context.Context, call ToolCall) (approved bool, err error)
+	// HandleError converts a failed tool call, including calls of unknown
+	// tools, into the output sent back to the model. Returning an error aborts
+	// the run instead. By default the error message is sent to the model.
+	HandleError func(call ToolCall, err error) (string, error)
+}
+
+// ChatToolRunner runs the tool-calling loop of the Chat Completions API: it
+// sends the request, executes the tool calls the model makes with the
+// registered handlers, feeds the results back and repeats until the model
+// answers without calling tools.
+type ChatToolRunner struct {
+	client *Client
+	config ToolRunnerConfig
+	tools  map[string]FunctionTool
+	order  []string
+}
+
+// ToolRunResult is the outcome of a run.
+type ToolRunResult struct {
+	// Response is the last chat completion, which holds the final answer
+	// unless the run stopped early.
+	Response ChatCompletionResponse
+	// Messages is the conversation including the request messages, the
+	// assistant messages and the tool results.
+	Messages []ChatCompletionMessage
+	// Iterations is the number of chat completions that were created.
+	Iterations int
+	// Usage is the total token usage of all chat completions.
+	Usage Usage
+}
+
+// NewChatToolRunner creates a ChatToolRunner executing the given tools.
+func NewChatToolRunner(client *Client, config ToolRunnerConfig, tools ...FunctionTool) *ChatToolRunner {
+	runner := &ChatToolRunner{
+		client: client,
+		config: config,
+		tools:  make(map[string]FunctionTool),
+	}
+	for _, tool := range tools {
+		runner.Register(tool)
+	}
+	return runner
+}
+
+// Register adds a tool, replacing any tool with the same name.
+func (r *ChatToolRunner) Register(tool FunctionTool) {
+	if _, ok := r.tools[tool.Definition.Name]; !ok {
+		r.order = append(r.order, tool.Definition.Name)
+	}
+	r.tools[tool.Definition.Name] = tool
+}
+
+// Run executes the tool-calling loop for request. The registered tools are
+// added to request.Tools unless a tool of the same name is already present.
+// Only the first choice of each chat completion is followed.
+func (r *ChatToolRunner) Run(ctx context.Context, request ChatCompletionRequest) (ToolRunResult, error) {
+	request.Tools = r.requestTools(request.Tools)
+	result := ToolRunResult{
+		Messages: append([]ChatCompletionMessage(nil), request.Messages...),
+	}
+
+	maxIterations := r.config.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = defaultToolRunnerMaxIterations
+	}
+	var toolCalls int
+	for result.Iterations < maxIterations {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		request.Messages = result.Messages
+		response, err := r.client.CreateChatCompletion(ctx, request)
+		if err != nil {
+			return result, err
+		}
+		result.Response = response
+		result.Iterations++
+		addUsage(&result.Usage, response.Usage)
+		if len(response.Choices) == 0 {
+			return result, ErrToolRunnerNoChoices
+		}
+
+		message := response.Choices[0].Message
+		result.Messages = append(result.Messages, message)
+		if len(message.ToolCalls) == 0 {
+			return result, nil
+		}
+		if r.config.exceedsMaxToolCalls(toolCalls + len(message.ToolCalls)) {
+			return result, ErrToolRunnerMaxToolCalls
+		}
+		toolCalls += len(message.ToolCalls)
+
+		outputs, err := r.runToolCalls(ctx, message.ToolCalls, parallelToolCallsAllowed(request.ParallelToolCalls))
+		if err != nil {
+			return result, err
+		}
+		result.Messages = append(result.Messages, outputs...)
+	}
+	return result, ErrToolRunnerMaxIterations
+}
+
+func (c ToolRunnerConfig) exceedsMaxToolCalls(toolCalls int) bool {
+	return c.MaxToolCalls > 0 && toolCalls > c.MaxToolCalls
+}
+
+func (r *ChatToolRunner) requestTools(tools []Tool) []Tool {
+	present := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if tool.Function != nil {
+			present[tool.Function.Name] = true
+		}
+	}
+	for _, name := range r.order {
+		if !present[name] {
+			tools = append(tools, r.tools[name].Tool())
+		}
+	}
+	return tools
+}
+
+func parallelToolCallsAllowed(parallelToolCalls any) bool {
+	allowed, ok := parallelToolCalls.(bool)
+	return !ok || allowed
+}
+
+func addUsage(total *Usage, usage Usage) {
+	total.PromptTokens += usage.PromptTokens
+	total.CompletionTokens += usage.CompletionTokens
+	total.TotalTokens += usage.TotalTokens
+}
+
+// runToolCalls executes the calls and returns one tool message per call, in
+// the order of the calls.
+func (r *ChatToolRunner) runToolCalls(ctx context.Context, calls []ToolCall, parallel bool) (
+	[]ChatCompletionMessage,
+	error,
+) {
+	outputs, err := runFunctionTools(ctx, r.tools, r.config, calls, parallel)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]ChatCompletionMessage, len(calls))
+	for i, call := range calls {
+		messages[i] = ChatCompletionMessage{
+			Role:       ChatMessageRoleTool,
+			Content:    outputs[i],
+			Name:       call.Function.Name,
+			ToolCallID: call.ID,
+		}
+	}
+	return messages, nil
+}
+
+// runFunctionTools executes the calls, concurrently if parallel is set, and
+// returns their outputs in the order of the calls.
+func runFunctionTools(
+	ctx context.Context,
+	tools map[string]FunctionTool,
+	config ToolRunnerConfig,
+	calls []ToolCall,
+	parallel bool,
+) ([]string, error) {
+	outputs := make([]string, len(calls))
+	errs := make([]error, len(calls))
+	if parallel && len(calls) > 1 {
+		var wg sync.WaitGroup
+		for i := range calls {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				outputs[i], errs[i] = runFunctionTool(ctx, tools, config, calls[i])
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range calls {
+			outputs[i], errs[i] = runFunctionTool(ctx, tools, config, calls[i])
+			if errs[i] != nil {
+				break
+			}
+		}
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
+
+// runFunctionTool approves and executes a call with the matching tool, and
+// converts handler errors into model-facing output.
+func runFunctionTool(
+	ctx context.Context,
+	tools map[string]FunctionTool,
+	config ToolRunnerConfig,
+	call ToolCall,
+) (string, error) {
+	if config.Approve != nil {
+		approved, err := config.Approve(ctx, call)
+		if err != nil {
+			return "", err
+		}
+		if !approved {
+			return fmt.Sprintf("The call to %s was not approved by the user.", call.Function.Name), nil
+		}
+	}
+
+	var (
+		output string
+		err    error
+	)
+	if tool, ok := tools[call.Function.Name]; ok && tool.Handler != nil {
+		output, err = tool.Handler(ctx, call.Function.Arguments)
+	} else {
+		err = fmt.Errorf("unknown tool %q", call.Function.Name)
+	}
+	if err == nil {
+		return output, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", ctxErr
+	}
+	if config.HandleError != nil {
+		return config.HandleError(call, err)
+	}
+	return fmt.Sprintf("Error: %v", err), nil
+}
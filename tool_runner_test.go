@@ -0,0 +1,250 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func toolCallResponse(calls ...openai.ToolCall) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{
+			Message: openai.ChatCompletionMessage{
+				Role:      openai.ChatMessageRoleAssistant,
+				ToolCalls: calls,
+			},
+			FinishReason: openai.FinishReasonToolCalls,
+		}},
+		Usage: openai.Usage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2},
+	}
+}
+
+func answerResponse(content string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{
+			Message:      openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content},
+			FinishReason: openai.FinishReasonStop,
+		}},
+		Usage: openai.Usage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2},
+	}
+}
+
+func functionCall(id, name, arguments string) openai.ToolCall {
+	return openai.ToolCall{
+		ID:       id,
+		Type:     openai.ToolTypeFunction,
+		Function: openai.FunctionCall{Name: name, Arguments: arguments},
+	}
+}
+
+// registerChatResponses serves the responses in order and records the requests.
+func registerChatResponses(
+	server *test.ServerTest,
+	requests *[]openai.ChatCompletionRequest,
+	responses ...openai.ChatCompletionResponse,
+) {
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		*requests = append(*requests, request)
+		if len(*requests) > len(responses) {
+			http.Error(w, "unexpected request", http.StatusInternalServerError)
+			return
+		}
+		resBytes, _ := json.Marshal(responses[len(*requests)-1])
+		fmt.Fprint(w, string(resBytes))
+	})
+}
+
+func weatherTool(calls *int32) openai.FunctionTool {
+	return openai.FunctionTool{
+		Definition: openai.FunctionDefinition{Name: "get_weather", Parameters: json.RawMessage(`{"type":"object"}`)},
+		Handler: func(_ context.Context, arguments string) (string, error) {
+			atomic.AddInt32(calls, 1)
+			var args struct {
+				City string `json:"city"`
+			}
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", err
+			}
+			return "sunny in " + args.City, nil
+		},
+	}
+}
+
+func TestChatToolRunner(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []openai.ChatCompletionRequest
+	registerChatResponses(server, &requests,
+		toolCallResponse(
+			functionCall("call_1", "get_weather", `{"city":"Paris"}`),
+			functionCall("call_2", "get_weather", `{"city":"Rome"}`),
+			functionCall("call_3", "get_time", `{}`),
+		),
+		answerResponse("Sunny everywhere."),
+	)
+
+	var calls int32
+	runner := openai.NewChatToolRunner(client, openai.ToolRunnerConfig{}, weatherTool(&calls))
+	result, err := runner.Run(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Weather?"}},
+	})
+	checks.NoError(t, err, "Run error")
+
+	if result.Iterations != 2 || result.Usage.TotalTokens != 4 {
+		t.Errorf("unexpected iterations or usage: %d %+v", result.Iterations, result.Usage)
+	}
+	if result.Response.Choices[0].Message.Content != "Sunny everywhere." {
+		t.Errorf("unexpected final response: %+v", result.Response)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 handler calls, got %d", calls)
+	}
+	if len(result.Messages) != 6 {
+		t.Fatalf("expected 6 messages, got %+v", result.Messages)
+	}
+	outputs := result.Messages[2:5]
+	for i, expected := range []string{"sunny in Paris", "sunny in Rome", `Error: unknown tool "get_time"`} {
+		if outputs[i].Role != openai.ChatMessageRoleTool || outputs[i].Content != expected ||
+			outputs[i].ToolCallID != fmt.Sprintf("call_%d", i+1) {
+			t.Errorf("unexpected tool message %d: %+v", i, outputs[i])
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if len(requests[0].Tools) != 1 || requests[0].Tools[0].Function.Name != "get_weather" {
+		t.Errorf("registered tools should be sent: %+v", requests[0].Tools)
+	}
+	if len(requests[1].Messages) != 5 || requests[1].Messages[4].ToolCallID != "call_3" {
+		t.Errorf("tool results should be sent back: %+v", requests[1].Messages)
+	}
+}
+
+func TestChatToolRunnerMaxIterations(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []openai.ChatCompletionRequest
+	call := functionCall("call_1", "get_weather", `{"city":"Paris"}`)
+	registerChatResponses(server, &requests, toolCallResponse(call), toolCallResponse(call), toolCallResponse(call))
+
+	var calls int32
+	runner := openai.NewChatToolRunner(client, openai.ToolRunnerConfig{MaxIterations: 2}, weatherTool(&calls))
+	result, err := runner.Run(context.Background(), openai.ChatCompletionRequest{
+		Model:             openai.GPT4o,
+		Messages:          []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Weather?"}},
+		ParallelToolCalls: false,
+	})
+	if !errors.Is(err, openai.ErrToolRunnerMaxIterations) {
+		t.Fatalf("expected ErrToolRunnerMaxIterations, got %v", err)
+	}
+	if result.Iterations != 2 || len(requests) != 2 || calls != 2 {
+		t.Errorf("unexpected run: iterations=%d requests=%d calls=%d", result.Iterations, len(requests), calls)
+	}
+}
+
+func TestChatToolRunnerMaxToolCalls(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []openai.ChatCompletionRequest
+	call := functionCall("call_1", "get_weather", `{"city":"Paris"}`)
+	registerChatResponses(server, &requests, toolCallResponse(call), toolCallResponse(call))
+
+	var calls int32
+	runner := openai.NewChatToolRunner(client, openai.ToolRunnerConfig{MaxToolCalls: 1}, weatherTool(&calls))
+	result, err := runner.Run(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Weather?"}},
+	})
+	if !errors.Is(err, openai.ErrToolRunnerMaxToolCalls) {
+		t.Fatalf("expected ErrToolRunnerMaxToolCalls, got %v", err)
+	}
+	if result.Iterations != 2 || calls != 1 {
+		t.Errorf("unexpected run: iterations=%d calls=%d", result.Iterations, calls)
+	}
+}
+
+func TestChatToolRunnerHooks(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []openai.ChatCompletionRequest
+	registerChatResponses(server, &requests,
+		toolCallResponse(
+			functionCall("call_1", "get_weather", `{"city":"Paris"}`),
+			functionCall("call_2", "get_weather", `not json`),
+		),
+		answerResponse("done"),
+	)
+
+	var calls int32
+	runner := openai.NewChatToolRunner(client, openai.ToolRunnerConfig{
+		Approve: func(_ context.Context, call openai.ToolCall) (bool, error) {
+			return call.ID != "call_1", nil
+		},
+		HandleError: func(call openai.ToolCall, err error) (string, error) {
+			return "failed: " + call.ID, nil
+		},
+	}, weatherTool(&calls))
+	result, err := runner.Run(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Weather?"}},
+	})
+	checks.NoError(t, err, "Run error")
+
+	if calls != 1 {
+		t.Errorf("only the approved call should run, got %d calls", calls)
+	}
+	if content := result.Messages[2].Content; !strings.Contains(content, "not approved") {
+		t.Errorf("unexpected denied call output: %q", content)
+	}
+	if content := result.Messages[3].Content; content != "failed: call_2" {
+		t.Errorf("unexpected failed call output: %q", content)
+	}
+}
+
+func TestChatToolRunnerAbort(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests []openai.ChatCompletionRequest
+	registerChatResponses(server, &requests,
+		toolCallResponse(functionCall("call_1", "get_weather", `{"city":"Paris"}`)),
+		answerResponse("done"),
+	)
+
+	errAbort := errors.New("abort")
+	var calls int32
+	runner := openai.NewChatToolRunner(client, openai.ToolRunnerConfig{
+		Approve: func(context.Context, openai.ToolCall) (bool, error) {
+			return false, errAbort
+		},
+	}, weatherTool(&calls))
+	_, err := runner.Run(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Weather?"}},
+	})
+	if !errors.Is(err, errAbort) || len(requests) != 1 || calls != 0 {
+		t.Errorf("approval error should abort the run: err=%v requests=%d calls=%d", err, len(requests), calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = runner.Run(ctx, openai.ChatCompletionRequest{Model: openai.GPT4o})
+	if !errors.Is(err, context.Canceled) || len(requests) != 1 {
+		t.Errorf("canceled context should stop the run: err=%v requests=%d", err, len(requests))
+	}
+}